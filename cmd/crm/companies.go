@@ -6,7 +6,6 @@ package main
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/fatih/color"
 	"github.com/google/uuid"
@@ -31,6 +30,7 @@ var companyAddCmd = &cobra.Command{
 		domain, _ := cmd.Flags().GetString("domain")
 		fields, _ := cmd.Flags().GetStringArray("field")
 		tags, _ := cmd.Flags().GetStringSlice("tag")
+		source, _ := cmd.Flags().GetString("source")
 
 		c.Domain = domain
 
@@ -42,6 +42,7 @@ var companyAddCmd = &cobra.Command{
 			c.Fields[k] = v
 		}
 		c.Tags = tags
+		c.Source = source
 
 		if err := store.CreateCompany(c); err != nil {
 			return err
@@ -60,9 +61,11 @@ var companyListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tag, _ := cmd.Flags().GetString("tag")
 		search, _ := cmd.Flags().GetString("search")
+		source, _ := cmd.Flags().GetString("source")
 		limit, _ := cmd.Flags().GetInt("limit")
 
 		filter := &storage.CompanyFilter{
+			Source: source,
 			Search: search,
 			Limit:  limit,
 		}
@@ -123,8 +126,22 @@ var companyShowCmd = &cobra.Command{
 				out("  %s: %v\n", k, v)
 			}
 		}
-		out("Created: %s\n", c.CreatedAt.Format(time.RFC3339))
-		out("Updated: %s\n", c.UpdatedAt.Format(time.RFC3339))
+		if c.Source != "" {
+			out("Source:  %s\n", c.Source)
+		}
+		out("Created: %s\n", formatTime(c.CreatedAt))
+		out("Updated: %s\n", formatTime(c.UpdatedAt))
+
+		notes, err := store.ListCompanyNotes(c.ID)
+		if err != nil {
+			return err
+		}
+		if len(notes) > 0 {
+			outln("Notes:")
+			for _, n := range notes {
+				out("  %s  %s\n", formatTime(n.CreatedAt), n.Content)
+			}
+		}
 
 		// Show relationships
 		rels, err := store.ListRelationships(c.ID)
@@ -205,6 +222,50 @@ var companyRmCmd = &cobra.Command{
 	},
 }
 
+var companyNoteCmd = &cobra.Command{
+	Use:   "note <id> <text>",
+	Short: "Add a note to a company's history",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := resolveCompany(args[0])
+		if err != nil {
+			return err
+		}
+
+		n := models.NewCompanyNote(c.ID, args[1])
+		if err := store.AddCompanyNote(n); err != nil {
+			return err
+		}
+
+		out("Added note %s to %s\n", color.New(color.FgCyan).Sprint(n.ID), c.Name)
+		return nil
+	},
+}
+
+var companyLinkDomainCmd = &cobra.Command{
+	Use:   "link-domain <id>",
+	Short: "Link contacts whose email is at the company's domain",
+	Long:  "Link every contact whose email is at the company's domain to it as works_at, skipping contacts already related to the company.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := resolveCompany(args[0])
+		if err != nil {
+			return err
+		}
+
+		linked, err := storage.LinkDomainContacts(store, c.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, contact := range linked {
+			out("  %s <%s>\n", contact.Name, contact.Email)
+		}
+		out("Linked %d contacts to %s\n", len(linked), c.Name)
+		return nil
+	},
+}
+
 // resolveCompany looks up a company by full UUID or ID prefix.
 func resolveCompany(idStr string) (*models.Company, error) {
 	if id, err := uuid.Parse(idStr); err == nil {
@@ -217,9 +278,11 @@ func init() {
 	companyAddCmd.Flags().String("domain", "", "company domain")
 	companyAddCmd.Flags().StringArray("field", nil, "custom field as KEY=VALUE (repeatable)")
 	companyAddCmd.Flags().StringSlice("tag", nil, "tag to apply (repeatable)")
+	companyAddCmd.Flags().String("source", models.SourceManual, "where the company came from, e.g. csv")
 
 	companyListCmd.Flags().StringP("tag", "t", "", "filter by tag")
 	companyListCmd.Flags().StringP("search", "s", "", "search companies")
+	companyListCmd.Flags().String("source", "", "filter by source")
 	companyListCmd.Flags().IntP("limit", "n", 20, "max results to show")
 
 	companyEditCmd.Flags().String("name", "", "new name")
@@ -230,6 +293,8 @@ func init() {
 	companyCmd.AddCommand(companyAddCmd)
 	companyCmd.AddCommand(companyListCmd)
 	companyCmd.AddCommand(companyShowCmd)
+	companyCmd.AddCommand(companyNoteCmd)
+	companyCmd.AddCommand(companyLinkDomainCmd)
 	companyCmd.AddCommand(companyEditCmd)
 	companyCmd.AddCommand(companyRmCmd)
 	rootCmd.AddCommand(companyCmd)
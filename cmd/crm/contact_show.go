@@ -0,0 +1,284 @@
+// ABOUTME: Rendering for "crm contact show": terminal, JSON, and markdown views.
+// ABOUTME: All three are built from the expanded contact so they show the same records.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+// contactShowJSON is the --json shape of "crm contact show". The storage
+// models carry no JSON tags, so the view is flattened here to keep every
+// key snake_case.
+type contactShowJSON struct {
+	ID            uuid.UUID           `json:"id"`
+	Name          string              `json:"name"`
+	Email         string              `json:"email,omitempty"`
+	Phone         string              `json:"phone,omitempty"`
+	DoNotContact  bool                `json:"do_not_contact"`
+	Tags          []string            `json:"tags"`
+	Fields        map[string]any      `json:"fields"`
+	Source        string              `json:"source,omitempty"`
+	Version       int                 `json:"version"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	Companies     []companyRefJSON    `json:"companies"`
+	Relationships []relationshipJSON  `json:"relationships"`
+	Notes         []noteJSON          `json:"notes"`
+	Attachments   []attachmentRefJSON `json:"attachments"`
+}
+
+// companyRefJSON is a company the contact is linked to.
+type companyRefJSON struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Domain string    `json:"domain,omitempty"`
+}
+
+// relationshipJSON is a relationship and the entity on its other end.
+type relationshipJSON struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	Context   string    `json:"context,omitempty"`
+	OtherID   uuid.UUID `json:"other_id"`
+	OtherType string    `json:"other_type,omitempty"` // empty when the counterpart no longer exists
+	OtherName string    `json:"other_name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// noteJSON is a note on the contact.
+type noteJSON struct {
+	ID        uuid.UUID `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// attachmentRefJSON is an attachment's metadata; the data itself is not included.
+type attachmentRefJSON struct {
+	ID          uuid.UUID `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int64     `json:"size"`
+	Path        string    `json:"path,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// newContactShowJSON flattens an expanded contact into its --json shape.
+func newContactShowJSON(e *storage.ContactExpanded) contactShowJSON {
+	c := e.Contact
+	v := contactShowJSON{
+		ID:            c.ID,
+		Name:          c.Name,
+		Email:         c.Email,
+		Phone:         c.Phone,
+		DoNotContact:  c.DoNotContact,
+		Tags:          c.Tags,
+		Fields:        c.Fields,
+		Source:        c.Source,
+		Version:       c.Version,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+		Companies:     make([]companyRefJSON, 0, len(e.Companies)),
+		Relationships: make([]relationshipJSON, 0, len(e.Relationships)),
+		Notes:         make([]noteJSON, 0, len(e.Notes)),
+		Attachments:   make([]attachmentRefJSON, 0, len(e.Attachments)),
+	}
+	for _, co := range e.Companies {
+		v.Companies = append(v.Companies, companyRefJSON{ID: co.ID, Name: co.Name, Domain: co.Domain})
+	}
+	for _, r := range e.Relationships {
+		v.Relationships = append(v.Relationships, relationshipJSON{
+			ID:        r.Relationship.ID,
+			Type:      r.Relationship.Type,
+			Context:   r.Relationship.Context,
+			OtherID:   r.OtherID,
+			OtherType: r.OtherType,
+			OtherName: r.OtherName,
+			CreatedAt: r.Relationship.CreatedAt,
+		})
+	}
+	for _, n := range e.Notes {
+		v.Notes = append(v.Notes, noteJSON{ID: n.ID, Content: n.Content, CreatedAt: n.CreatedAt})
+	}
+	for _, a := range e.Attachments {
+		v.Attachments = append(v.Attachments, attachmentRefJSON{
+			ID:          a.ID,
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+			Path:        a.Path,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+	return v
+}
+
+// writeContactJSON writes the expanded contact to w as indented JSON.
+func writeContactJSON(w io.Writer, e *storage.ContactExpanded) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newContactShowJSON(e))
+}
+
+// printContactExpanded writes the terminal detail view of a contact.
+func printContactExpanded(e *storage.ContactExpanded) {
+	c := e.Contact
+	cyan := color.New(color.FgCyan)
+	bold := color.New(color.Bold)
+
+	out("ID:      %s\n", cyan.Sprint(c.ID))
+	out("Name:    %s\n", bold.Sprint(c.Name))
+	if c.Email != "" {
+		out("Email:   <%s>\n", c.Email)
+	}
+	if c.Phone != "" {
+		out("Phone:   %s\n", c.Phone)
+	}
+	if c.DoNotContact {
+		out("Status:  %s\n", color.New(color.FgRed).Sprint("do not contact"))
+	}
+	if len(e.Companies) > 0 {
+		names := make([]string, len(e.Companies))
+		for i, co := range e.Companies {
+			names[i] = co.Name
+		}
+		out("Company: %s\n", strings.Join(names, ", "))
+	}
+	if len(c.Tags) > 0 {
+		out("Tags:    [%s]\n", strings.Join(c.Tags, ", "))
+	}
+	if len(c.Fields) > 0 {
+		outln("Fields:")
+		for _, k := range sortedKeys(c.Fields) {
+			out("  %s: %v\n", k, c.Fields[k])
+		}
+	}
+	if c.Source != "" {
+		out("Source:  %s\n", c.Source)
+	}
+	out("Created: %s\n", formatTime(c.CreatedAt))
+	out("Updated: %s\n", formatTime(c.UpdatedAt))
+
+	if len(e.Relationships) > 0 {
+		outln("Relationships:")
+		for _, r := range e.Relationships {
+			out("  %s %s  %s", r.Relationship.Type, relatedLabel(r), cyan.Sprint(r.OtherID))
+			if r.Relationship.Context != "" {
+				out(" (%s)", r.Relationship.Context)
+			}
+			outln()
+		}
+	}
+	if len(e.Notes) > 0 {
+		outln("Notes:")
+		for _, n := range e.Notes {
+			out("  %s  %s\n", formatTime(n.CreatedAt), n.Content)
+		}
+	}
+	if len(e.Attachments) > 0 {
+		outln("Attachments:")
+		for _, a := range e.Attachments {
+			out("  %s  %s\n", attachmentLabel(a), cyan.Sprint(a.ID))
+		}
+	}
+}
+
+// renderContactMarkdown renders the expanded contact as a markdown one-pager.
+func renderContactMarkdown(e *storage.ContactExpanded) string {
+	c := e.Contact
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", c.Name)
+	if c.DoNotContact {
+		b.WriteString("> **Do not contact.**\n\n")
+	}
+	if c.Email != "" {
+		fmt.Fprintf(&b, "- **Email:** %s\n", c.Email)
+	}
+	if c.Phone != "" {
+		fmt.Fprintf(&b, "- **Phone:** %s\n", c.Phone)
+	}
+	for _, co := range e.Companies {
+		fmt.Fprintf(&b, "- **Company:** %s", co.Name)
+		if co.Domain != "" {
+			fmt.Fprintf(&b, " (%s)", co.Domain)
+		}
+		b.WriteString("\n")
+	}
+	if len(c.Tags) > 0 {
+		fmt.Fprintf(&b, "- **Tags:** %s\n", strings.Join(c.Tags, ", "))
+	}
+	fmt.Fprintf(&b, "- **ID:** `%s`\n", c.ID)
+
+	if len(c.Fields) > 0 {
+		b.WriteString("\n## Fields\n\n")
+		for _, k := range sortedKeys(c.Fields) {
+			fmt.Fprintf(&b, "- **%s:** %v\n", k, c.Fields[k])
+		}
+	}
+
+	if len(e.Relationships) > 0 {
+		b.WriteString("\n## Relationships\n\n")
+		for _, r := range e.Relationships {
+			fmt.Fprintf(&b, "- %s %s", r.Relationship.Type, relatedLabel(r))
+			if r.Relationship.Context != "" {
+				fmt.Fprintf(&b, " — %s", r.Relationship.Context)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(e.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, n := range e.Notes {
+			fmt.Fprintf(&b, "- **%s:** %s\n", formatTime(n.CreatedAt), n.Content)
+		}
+	}
+
+	if len(e.Attachments) > 0 {
+		b.WriteString("\n## Attachments\n\n")
+		for _, a := range e.Attachments {
+			fmt.Fprintf(&b, "- %s\n", attachmentLabel(a))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n_Created %s · Updated %s_\n", formatTime(c.CreatedAt), formatTime(c.UpdatedAt))
+	return b.String()
+}
+
+// relatedLabel names a relationship's counterpart, falling back to a marker
+// when the other entity no longer exists.
+func relatedLabel(r *storage.RelatedEntity) string {
+	if r.OtherType == "" {
+		return "(missing entity)"
+	}
+	return fmt.Sprintf("%s (%s)", r.OtherName, r.OtherType)
+}
+
+// attachmentLabel describes an attachment by name, type, and size.
+func attachmentLabel(a *models.Attachment) string {
+	if a.ContentType == "" {
+		return fmt.Sprintf("%s (%d bytes)", a.Filename, a.Size)
+	}
+	return fmt.Sprintf("%s (%s, %d bytes)", a.Filename, a.ContentType, a.Size)
+}
+
+// sortedKeys returns the keys of m in lexical order so output is stable.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+// ABOUTME: Tests for the "crm contact show" JSON and markdown renderings.
+// ABOUTME: Verifies the exact snake_case JSON keys and that notes and attachments appear in both views.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+// testContactExpanded returns an expanded contact with one of each record.
+func testContactExpanded() *storage.ContactExpanded {
+	c := models.NewContact("Alice")
+	co := models.NewCompany("Acme")
+	rel := models.NewRelationship(c.ID, co.ID, "works_at", "")
+	att := models.NewAttachment(storage.EntityContact, c.ID, "resume.pdf")
+	att.ContentType = "application/pdf"
+	att.Size = 2048
+	return &storage.ContactExpanded{
+		Contact:       c,
+		Companies:     []*models.Company{co},
+		Relationships: []*storage.RelatedEntity{{Relationship: rel, OtherID: co.ID, OtherType: storage.EntityCompany, OtherName: co.Name}},
+		Notes:         []*models.ContactNote{models.NewContactNote(c.ID, "Met at the conference")},
+		Attachments:   []*models.Attachment{att},
+	}
+}
+
+func TestWriteContactJSON(t *testing.T) {
+	e := testContactExpanded()
+	e.Contact.Email = "alice@acme.com"
+	e.Contact.Source = models.SourceManual
+	e.Companies[0].Domain = "acme.com"
+	e.Relationships[0].Relationship.Context = "since 2020"
+
+	var buf bytes.Buffer
+	if err := writeContactJSON(&buf, e); err != nil {
+		t.Fatalf("writeContactJSON: %v", err)
+	}
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	assertKeys(t, "contact", got, "id", "name", "email", "do_not_contact", "tags", "fields", "source",
+		"version", "created_at", "updated_at", "companies", "relationships", "notes", "attachments")
+
+	for _, tc := range []struct {
+		key  string
+		want []string
+	}{
+		{"companies", []string{"id", "name", "domain"}},
+		{"relationships", []string{"id", "type", "context", "other_id", "other_type", "other_name", "created_at"}},
+		{"notes", []string{"id", "content", "created_at"}},
+		{"attachments", []string{"id", "filename", "content_type", "size", "created_at"}},
+	} {
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(got[tc.key], &items); err != nil || len(items) != 1 {
+			t.Fatalf("%s = %s (err %v), want one", tc.key, got[tc.key], err)
+		}
+		assertKeys(t, tc.key, items[0], tc.want...)
+	}
+
+	if !strings.Contains(string(got["notes"]), "Met at the conference") {
+		t.Errorf("notes = %s, want the conference note", got["notes"])
+	}
+	if !strings.Contains(string(got["attachments"]), "resume.pdf") {
+		t.Errorf("attachments = %s, want resume.pdf", got["attachments"])
+	}
+}
+
+// assertKeys fails unless obj has exactly the want keys.
+func assertKeys(t *testing.T, what string, obj map[string]json.RawMessage, want ...string) {
+	t.Helper()
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	want = slices.Clone(want)
+	sort.Strings(want)
+	if !slices.Equal(keys, want) {
+		t.Errorf("%s keys = %v, want %v", what, keys, want)
+	}
+}
+
+func TestRenderContactMarkdown(t *testing.T) {
+	md := renderContactMarkdown(testContactExpanded())
+	for _, want := range []string{
+		"# Alice",
+		"## Relationships",
+		"## Notes",
+		"Met at the conference",
+		"## Attachments",
+		"resume.pdf (application/pdf, 2048 bytes)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
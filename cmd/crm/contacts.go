@@ -4,9 +4,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
-	"time"
 
 	"github.com/fatih/color"
 	"github.com/google/uuid"
@@ -32,6 +33,7 @@ var contactAddCmd = &cobra.Command{
 		phone, _ := cmd.Flags().GetString("phone")
 		fields, _ := cmd.Flags().GetStringArray("field")
 		tags, _ := cmd.Flags().GetStringSlice("tag")
+		source, _ := cmd.Flags().GetString("source")
 
 		c.Email = email
 		c.Phone = phone
@@ -44,6 +46,7 @@ var contactAddCmd = &cobra.Command{
 			c.Fields[k] = v
 		}
 		c.Tags = tags
+		c.Source = source
 
 		if err := store.CreateContact(c); err != nil {
 			return err
@@ -62,9 +65,11 @@ var contactListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tag, _ := cmd.Flags().GetString("tag")
 		search, _ := cmd.Flags().GetString("search")
+		source, _ := cmd.Flags().GetString("source")
 		limit, _ := cmd.Flags().GetInt("limit")
 
 		filter := &storage.ContactFilter{
+			Source: source,
 			Search: search,
 			Limit:  limit,
 		}
@@ -92,6 +97,9 @@ var contactListCmd = &cobra.Command{
 			if len(c.Tags) > 0 {
 				out("  [%s]", strings.Join(c.Tags, ", "))
 			}
+			if c.DoNotContact {
+				out("  %s", color.New(color.FgRed).Sprint("(do not contact)"))
+			}
 			outln()
 		}
 		return nil
@@ -99,63 +107,60 @@ var contactListCmd = &cobra.Command{
 }
 
 var contactShowCmd = &cobra.Command{
-	Use:   "show <id>",
+	Use:   "show <id|name>",
 	Short: "Show contact details",
+	Long:  "Show a contact with its companies, relationships, notes, and attachments. Use --json for machine-readable output or --markdown for a one-page summary.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c, err := resolveContact(args[0])
 		if err != nil {
 			return err
 		}
-
-		cyan := color.New(color.FgCyan)
-		bold := color.New(color.Bold)
-
-		out("ID:      %s\n", cyan.Sprint(c.ID))
-		out("Name:    %s\n", bold.Sprint(c.Name))
-		if c.Email != "" {
-			out("Email:   <%s>\n", c.Email)
-		}
-		if c.Phone != "" {
-			out("Phone:   %s\n", c.Phone)
-		}
-		if len(c.Tags) > 0 {
-			out("Tags:    [%s]\n", strings.Join(c.Tags, ", "))
+		expanded, err := store.GetContactExpanded(c.ID)
+		if err != nil {
+			return err
 		}
-		if len(c.Fields) > 0 {
-			outln("Fields:")
-			for k, v := range c.Fields {
-				out("  %s: %v\n", k, v)
-			}
+
+		asJSON, _ := cmd.Flags().GetBool("json")
+		asMarkdown, _ := cmd.Flags().GetBool("markdown")
+		switch {
+		case asJSON:
+			return writeContactJSON(os.Stdout, expanded)
+		case asMarkdown:
+			out("%s", renderContactMarkdown(expanded))
+		default:
+			printContactExpanded(expanded)
 		}
-		out("Created: %s\n", c.CreatedAt.Format(time.RFC3339))
-		out("Updated: %s\n", c.UpdatedAt.Format(time.RFC3339))
+		return nil
+	},
+}
 
-		// Show relationships
-		rels, err := store.ListRelationships(c.ID)
+var contactNoteCmd = &cobra.Command{
+	Use:   "note <id|name> <text>",
+	Short: "Add a note to a contact's history",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := resolveContact(args[0])
 		if err != nil {
 			return err
 		}
-		if len(rels) > 0 {
-			outln("Relationships:")
-			for _, r := range rels {
-				out("  %s -[%s]-> %s", cyan.Sprint(r.SourceID), r.Type, cyan.Sprint(r.TargetID))
-				if r.Context != "" {
-					out(" (%s)", r.Context)
-				}
-				outln()
-			}
+
+		n := models.NewContactNote(c.ID, args[1])
+		if err := store.AddContactNote(n); err != nil {
+			return err
 		}
+
+		out("Added note %s to %s\n", color.New(color.FgCyan).Sprint(n.ID), c.Name)
 		return nil
 	},
 }
 
 var contactEditCmd = &cobra.Command{
-	Use:   "edit <id>",
+	Use:   "edit <id|name>",
 	Short: "Edit an existing contact",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c, err := resolveContact(args[0])
+		c, err := resolveContactExact(args[0])
 		if err != nil {
 			return err
 		}
@@ -182,6 +187,9 @@ var contactEditCmd = &cobra.Command{
 		if cmd.Flags().Changed("tag") {
 			c.Tags, _ = cmd.Flags().GetStringSlice("tag")
 		}
+		if cmd.Flags().Changed("do-not-contact") {
+			c.DoNotContact, _ = cmd.Flags().GetBool("do-not-contact")
+		}
 
 		c.Touch()
 		if err := store.UpdateContact(c); err != nil {
@@ -194,12 +202,12 @@ var contactEditCmd = &cobra.Command{
 }
 
 var contactRmCmd = &cobra.Command{
-	Use:     "rm <id>",
+	Use:     "rm <id|name>",
 	Aliases: []string{"delete", "del"},
 	Short:   "Remove a contact",
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c, err := resolveContact(args[0])
+		c, err := resolveContactExact(args[0])
 		if err != nil {
 			return err
 		}
@@ -213,12 +221,32 @@ var contactRmCmd = &cobra.Command{
 	},
 }
 
-// resolveContact looks up a contact by full UUID or ID prefix.
-func resolveContact(idStr string) (*models.Contact, error) {
-	if id, err := uuid.Parse(idStr); err == nil {
+// resolveContact looks up a contact by full UUID, ID prefix, or name. Exact
+// UUIDs never fall back to name matching; see pickContact for names.
+func resolveContact(query string) (*models.Contact, error) {
+	return lookupContact(query, false)
+}
+
+// resolveContactExact is resolveContact for commands that modify or delete
+// the contact: a name must match a contact's full name, or --pick must
+// choose among partial matches.
+func resolveContactExact(query string) (*models.Contact, error) {
+	return lookupContact(query, true)
+}
+
+// lookupContact resolves query by UUID, then ID prefix, then name.
+func lookupContact(query string, exactName bool) (*models.Contact, error) {
+	if id, err := uuid.Parse(query); err == nil {
 		return store.GetContact(id)
 	}
-	return store.GetContactByPrefix(idStr)
+	c, err := store.GetContactByPrefix(query)
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, storage.ErrContactNotFound) && !errors.Is(err, storage.ErrPrefixTooShort) {
+		return nil, err
+	}
+	return pickContact(query, exactName)
 }
 
 func init() {
@@ -226,9 +254,11 @@ func init() {
 	contactAddCmd.Flags().String("phone", "", "contact phone number")
 	contactAddCmd.Flags().StringArray("field", nil, "custom field as KEY=VALUE (repeatable)")
 	contactAddCmd.Flags().StringSlice("tag", nil, "tag to apply (repeatable)")
+	contactAddCmd.Flags().String("source", models.SourceManual, "where the contact came from, e.g. csv")
 
 	contactListCmd.Flags().StringP("tag", "t", "", "filter by tag")
 	contactListCmd.Flags().StringP("search", "s", "", "search contacts")
+	contactListCmd.Flags().String("source", "", "filter by source")
 	contactListCmd.Flags().IntP("limit", "n", 20, "max results to show")
 
 	contactEditCmd.Flags().String("name", "", "new name")
@@ -236,10 +266,20 @@ func init() {
 	contactEditCmd.Flags().String("phone", "", "new phone")
 	contactEditCmd.Flags().StringArray("field", nil, "set field KEY=VALUE (repeatable)")
 	contactEditCmd.Flags().StringSlice("tag", nil, "replace tags (repeatable)")
+	contactEditCmd.Flags().Bool("do-not-contact", false, "mark as do not contact (--do-not-contact=false clears it)")
+
+	contactShowCmd.Flags().Bool("json", false, "output the expanded contact as JSON")
+	contactShowCmd.Flags().Bool("markdown", false, "output a markdown one-pager")
+	contactShowCmd.MarkFlagsMutuallyExclusive("json", "markdown")
+
+	for _, cmd := range []*cobra.Command{contactShowCmd, contactNoteCmd, contactEditCmd, contactRmCmd} {
+		cmd.Flags().IntVar(&contactPick, "pick", 0, "choose the Nth match when a name matches several contacts")
+	}
 
 	contactCmd.AddCommand(contactAddCmd)
 	contactCmd.AddCommand(contactListCmd)
 	contactCmd.AddCommand(contactShowCmd)
+	contactCmd.AddCommand(contactNoteCmd)
 	contactCmd.AddCommand(contactEditCmd)
 	contactCmd.AddCommand(contactRmCmd)
 	rootCmd.AddCommand(contactCmd)
@@ -0,0 +1,68 @@
+// ABOUTME: Tests for contact commands that resolve a contact by name.
+// ABOUTME: Verifies destructive commands refuse partial name matches unless --pick chooses one.
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+// runCLI executes the root command with args against the database at dbPath.
+func runCLI(t *testing.T, dbPath string, args ...string) error {
+	t.Helper()
+	contactPick = 0
+	t.Cleanup(func() { contactPick = 0 })
+	rootCmd.SetArgs(append([]string{"--db", dbPath}, args...))
+	return rootCmd.Execute()
+}
+
+func TestContactRmRefusesPartialName(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_DATA_HOME", dir)
+	dbPath := filepath.Join(dir, "crm.db")
+
+	seed, err := storage.NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore: %v", err)
+	}
+	joanne := models.NewContact("Joanne Smith")
+	if err := seed.CreateContact(joanne); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	_ = seed.Close()
+
+	err = runCLI(t, dbPath, "contact", "rm", "ann")
+	if err == nil || !strings.Contains(err.Error(), "not the full name") {
+		t.Fatalf("rm with partial name: err = %v, want refusal", err)
+	}
+	if err := runCLI(t, dbPath, "contact", "edit", "ann", "--name", "Renamed"); err == nil {
+		t.Error("edit with partial name: expected refusal, got nil")
+	}
+
+	check, err := storage.NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, err := check.GetContact(joanne.ID)
+	_ = check.Close()
+	if err != nil || got.Name != "Joanne Smith" {
+		t.Fatalf("contact after refused rm/edit = %v (err %v), want Joanne Smith untouched", got, err)
+	}
+
+	if err := runCLI(t, dbPath, "contact", "rm", "ann", "--pick", "1"); err != nil {
+		t.Fatalf("rm with --pick: %v", err)
+	}
+	check, err = storage.NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = check.Close() }()
+	if _, err := check.GetContact(joanne.ID); err == nil {
+		t.Error("rm with --pick left the contact in place")
+	}
+}
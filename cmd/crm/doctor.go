@@ -0,0 +1,262 @@
+// ABOUTME: CLI command that reports data-quality issues in the CRM.
+// ABOUTME: Aggregates orphan finders, duplicate detection, and integrity checks; --fix repairs broken references and re-checks.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// doctorEntity identifies a single record flagged by the doctor report.
+type doctorEntity struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// doctorReport holds every category of issue found by the doctor command.
+type doctorReport struct {
+	ContactsWithoutCompany []doctorEntity   `json:"contacts_without_company"`
+	EmptyCompanies         []doctorEntity   `json:"empty_companies"`
+	DuplicateContacts      [][]doctorEntity `json:"duplicate_contacts"`
+
+	Integrity *storage.IntegrityReport `json:"integrity"`
+	Fixed     int                      `json:"fixed,omitempty"` // broken references and duplicates removed by --fix
+}
+
+// issueCount returns the total number of flagged records.
+func (r *doctorReport) issueCount() int {
+	n := len(r.ContactsWithoutCompany) + len(r.EmptyCompanies) + r.Integrity.Total()
+	for _, group := range r.DuplicateContacts {
+		n += len(group)
+	}
+	return n
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Report data-quality issues",
+	Long: "Check the CRM for contacts without a company, companies without contacts, likely duplicate contacts, " +
+		"relationships, attachments, or notes that point at deleted records, and duplicate relationships. " +
+		"--fix removes the broken references and duplicates, keeping the newest of each duplicate group, " +
+		"and then reports what is left.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+		report, err := runDoctor(fix)
+		if err != nil {
+			return err
+		}
+
+		asJSON, _ := cmd.Flags().GetBool("json")
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		}
+
+		printDoctorReport(report)
+		return nil
+	},
+}
+
+// runDoctor builds the report and, with fix set, repairs the integrity
+// issues it found and builds it again, so the result shows what remains
+// rather than what was just removed.
+func runDoctor(fix bool) (*doctorReport, error) {
+	report, err := buildDoctorReport()
+	if err != nil || !fix {
+		return report, err
+	}
+	fixed, err := fixIntegrity(report.Integrity)
+	if err != nil {
+		return nil, err
+	}
+	if report, err = buildDoctorReport(); err != nil {
+		return nil, err
+	}
+	report.Fixed = fixed
+	return report, nil
+}
+
+// buildDoctorReport runs every check against the store.
+func buildDoctorReport() (*doctorReport, error) {
+	report := &doctorReport{
+		ContactsWithoutCompany: []doctorEntity{},
+		EmptyCompanies:         []doctorEntity{},
+		DuplicateContacts:      [][]doctorEntity{},
+	}
+
+	contacts, err := store.ListContactsWithoutCompany()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contacts {
+		report.ContactsWithoutCompany = append(report.ContactsWithoutCompany, doctorEntity{ID: c.ID, Name: c.Name})
+	}
+
+	companies, err := store.ListEmptyCompanies()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range companies {
+		report.EmptyCompanies = append(report.EmptyCompanies, doctorEntity{ID: c.ID, Name: c.Name})
+	}
+
+	all, err := store.GetAllContacts()
+	if err != nil {
+		return nil, err
+	}
+	report.DuplicateContacts = findDuplicateContacts(all)
+
+	if report.Integrity, err = store.CheckIntegrity(); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// fixIntegrity deletes the dangling relationships, orphaned attachments and
+// notes, and duplicate relationships listed in r and returns how many
+// records were removed. Records already gone by the time they are deleted
+// are not counted.
+func fixIntegrity(r *storage.IntegrityReport) (int, error) {
+	fixed := 0
+	for _, id := range slices.Concat(r.DanglingRelationships, r.DuplicateRelationships) {
+		err := store.DeleteRelationship(id)
+		if errors.Is(err, storage.ErrRelationshipNotFound) {
+			continue
+		}
+		if err != nil {
+			return fixed, err
+		}
+		fixed++
+	}
+	for _, id := range r.OrphanedAttachments {
+		err := store.DeleteAttachment(id)
+		if errors.Is(err, storage.ErrAttachmentNotFound) {
+			continue
+		}
+		if err != nil {
+			return fixed, err
+		}
+		fixed++
+	}
+	notes := []struct {
+		ids    []uuid.UUID
+		delete func(uuid.UUID) error
+	}{
+		{r.OrphanedCompanyNotes, store.DeleteCompanyNote},
+		{r.OrphanedContactNotes, store.DeleteContactNote},
+	}
+	for _, n := range notes {
+		for _, id := range n.ids {
+			err := n.delete(id)
+			if errors.Is(err, storage.ErrNoteNotFound) {
+				continue
+			}
+			if err != nil {
+				return fixed, err
+			}
+			fixed++
+		}
+	}
+	return fixed, nil
+}
+
+// findDuplicateContacts groups contacts that share a normalized email or name.
+// A contact appears in at most one group; email matches take precedence.
+func findDuplicateContacts(contacts []*models.Contact) [][]doctorEntity {
+	seen := make(map[uuid.UUID]bool)
+	var groups [][]doctorEntity
+
+	keyFuncs := []func(*models.Contact) string{
+		func(c *models.Contact) string { return strings.ToLower(strings.TrimSpace(c.Email)) },
+		func(c *models.Contact) string { return strings.ToLower(strings.Join(strings.Fields(c.Name), " ")) },
+	}
+	for _, keyFn := range keyFuncs {
+		var order []string
+		byKey := make(map[string][]*models.Contact)
+		for _, c := range contacts {
+			key := keyFn(c)
+			if key == "" || seen[c.ID] {
+				continue
+			}
+			if _, ok := byKey[key]; !ok {
+				order = append(order, key)
+			}
+			byKey[key] = append(byKey[key], c)
+		}
+		for _, key := range order {
+			matches := byKey[key]
+			if len(matches) < 2 {
+				continue
+			}
+			group := make([]doctorEntity, 0, len(matches))
+			for _, c := range matches {
+				seen[c.ID] = true
+				group = append(group, doctorEntity{ID: c.ID, Name: c.Name})
+			}
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// printDoctorReport renders the report as categorized text.
+func printDoctorReport(r *doctorReport) {
+	cyan := color.New(color.FgCyan)
+	bold := color.New(color.Bold)
+
+	printSection := func(title string, entities []doctorEntity) {
+		out("%s (%d)\n", bold.Sprint(title), len(entities))
+		for _, e := range entities {
+			out("  %s  %s\n", cyan.Sprint(e.ID), e.Name)
+		}
+	}
+
+	printSection("Contacts without a company", r.ContactsWithoutCompany)
+	printSection("Companies without contacts", r.EmptyCompanies)
+
+	out("%s (%d)\n", bold.Sprint("Likely duplicate contacts"), len(r.DuplicateContacts))
+	for i, group := range r.DuplicateContacts {
+		out("  group %d:\n", i+1)
+		for _, e := range group {
+			out("    %s  %s\n", cyan.Sprint(e.ID), e.Name)
+		}
+	}
+
+	printIDs := func(title string, ids []uuid.UUID) {
+		out("%s (%d)\n", bold.Sprint(title), len(ids))
+		for _, id := range ids {
+			out("  %s\n", cyan.Sprint(id))
+		}
+	}
+	printIDs("Relationships with a missing endpoint", r.Integrity.DanglingRelationships)
+	printIDs("Attachments for missing records", r.Integrity.OrphanedAttachments)
+	printIDs("Duplicate relationships", r.Integrity.DuplicateRelationships)
+	printIDs("Company notes for missing companies", r.Integrity.OrphanedCompanyNotes)
+	printIDs("Contact notes for missing contacts", r.Integrity.OrphanedContactNotes)
+
+	if r.Fixed > 0 {
+		out("Removed %d broken or duplicate record(s).\n", r.Fixed)
+	}
+	if r.issueCount() == 0 {
+		outln("No issues found.")
+	}
+}
+
+func init() {
+	doctorCmd.Flags().Bool("json", false, "output the report as JSON")
+	doctorCmd.Flags().Bool("fix", false, "delete broken references and duplicate relationships, then report what remains")
+
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,54 @@
+// ABOUTME: Tests for the doctor command's repair pass.
+// ABOUTME: Verifies --fix removes orphaned records and reports the state after repair.
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+func TestRunDoctorFixReportsRemainingIssues(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "crm.db")
+	s, err := storage.NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore: %v", err)
+	}
+	store = s
+	t.Cleanup(func() { _ = closeStore() })
+
+	// The store's own writes never orphan a note, so insert one directly.
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO contact_notes (id, contact_id, content, created_at) VALUES (?, ?, 'gone', ?)`,
+		uuid.New().String(), uuid.New().String(), time.Now().UTC())
+	_ = db.Close()
+	if err != nil {
+		t.Fatalf("insert orphaned note: %v", err)
+	}
+
+	report, err := runDoctor(false)
+	if err != nil {
+		t.Fatalf("runDoctor: %v", err)
+	}
+	if len(report.Integrity.OrphanedContactNotes) != 1 {
+		t.Fatalf("OrphanedContactNotes = %v, want one", report.Integrity.OrphanedContactNotes)
+	}
+
+	report, err = runDoctor(true)
+	if err != nil {
+		t.Fatalf("runDoctor --fix: %v", err)
+	}
+	if report.Fixed != 1 {
+		t.Errorf("Fixed = %d, want 1", report.Fixed)
+	}
+	if report.Integrity.Total() != 0 {
+		t.Errorf("report after --fix still lists %+v", report.Integrity)
+	}
+}
@@ -3,6 +3,10 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+
 	mcpserver "github.com/harperreed/crm/internal/mcp"
 	"github.com/spf13/cobra"
 )
@@ -12,11 +16,51 @@ var mcpCmd = &cobra.Command{
 	Short: "Start MCP server (stdio transport)",
 	Long:  "Start an MCP server that exposes CRM tools, resources, and prompts over stdio.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		server := mcpserver.NewServer(store)
-		return server.Serve(cmd.Context())
+		if printSchema, _ := cmd.Flags().GetBool("print-schema"); printSchema {
+			return printToolCatalog()
+		}
+
+		path, err := appConfig.StoragePath()
+		if err != nil {
+			return err
+		}
+		// stdout carries the MCP protocol, so diagnostics go to stderr.
+		_, _ = fmt.Fprintf(os.Stderr, "crm: using %s storage at %s\n", appConfig.GetBackend(), path)
+
+		server := mcpserver.NewServer(store,
+			mcpserver.WithVersion(version),
+			mcpserver.WithStorageLocation(appConfig.GetBackend(), path),
+			mcpserver.WithLog(os.Stderr),
+		)
+		if err := server.Serve(cmd.Context()); err != nil {
+			return err
+		}
+		if cmd.Context().Err() != nil {
+			if err := closeStore(); err != nil {
+				return fmt.Errorf("close storage: %w", err)
+			}
+			_, _ = fmt.Fprintln(os.Stderr, "crm: storage closed")
+		}
+		return nil
 	},
 }
 
+// printToolCatalog writes the JSON Schema catalog of every MCP tool to
+// stdout.
+func printToolCatalog() error {
+	specs, err := mcpserver.Catalog()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(specs, "", "  ")
+	if err != nil {
+		return err
+	}
+	outln(string(data))
+	return nil
+}
+
 func init() {
+	mcpCmd.Flags().Bool("print-schema", false, "print the JSON Schema catalog of all tools and exit")
 	rootCmd.AddCommand(mcpCmd)
 }
@@ -0,0 +1,84 @@
+// ABOUTME: CLI command that copies the current store into another backend.
+// ABOUTME: Opens the destination from --to-backend and --to, then reports what MigrateStore copied.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/harperreed/crm/internal/config"
+	"github.com/harperreed/crm/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy all data into another storage backend",
+	Long: "Copy every contact, company, relationship, and attachment from the current store into the " +
+		"backend given by --to-backend at --to, keeping IDs and timestamps. Records the destination " +
+		"already holds are skipped, so an interrupted migration can be rerun.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backend, _ := cmd.Flags().GetString("to-backend")
+		to, _ := cmd.Flags().GetString("to")
+		if to == "" {
+			return errors.New("--to is required")
+		}
+
+		srcPath, err := appConfig.StoragePath()
+		if err != nil {
+			return err
+		}
+		dstCfg := &config.Config{Backend: backend, DBPath: to}
+		dstPath, err := dstCfg.StoragePath()
+		if err != nil {
+			return err
+		}
+		if filepath.Clean(dstPath) == filepath.Clean(srcPath) {
+			return errors.New("destination is the current store")
+		}
+
+		dst, err := dstCfg.OpenStorage()
+		if err != nil {
+			return fmt.Errorf("open destination: %w", err)
+		}
+		defer func() { _ = dst.Close() }()
+
+		report, err := storage.MigrateStore(store, dst)
+		if err != nil {
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			out("Copied %d companies, %d contacts, %d notes, %d photos, %d relationships, %d attachments to %s storage at %s\n",
+				report.Companies, report.Contacts, report.CompanyNotes+report.ContactNotes, report.Photos,
+				report.Relationships, report.Attachments, dstCfg.GetBackend(), dstPath)
+			if report.Skipped > 0 {
+				out("Skipped %d records already in the destination\n", report.Skipped)
+			}
+			for _, e := range report.Errors {
+				outln("  failed:", e)
+			}
+		}
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("%d records failed to copy", len(report.Errors))
+		}
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().String("to-backend", "sqlite", "destination backend: sqlite or markdown")
+	migrateCmd.Flags().String("to", "", "destination sqlite file or markdown directory (required)")
+	migrateCmd.Flags().Bool("json", false, "output the report as JSON")
+	rootCmd.AddCommand(migrateCmd)
+}
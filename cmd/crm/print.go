@@ -6,8 +6,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
+// displayLoc is the zone timestamps are shown in; set from config at startup.
+var displayLoc = time.Local
+
 // out writes a formatted string to stdout.
 func out(format string, a ...any) {
 	_, _ = fmt.Fprintf(os.Stdout, format, a...)
@@ -17,3 +21,8 @@ func out(format string, a ...any) {
 func outln(a ...any) {
 	_, _ = fmt.Fprintln(os.Stdout, a...)
 }
+
+// formatTime renders a stored (UTC) timestamp in the display zone.
+func formatTime(t time.Time) string {
+	return t.In(displayLoc).Format(time.RFC3339)
+}
@@ -0,0 +1,88 @@
+// ABOUTME: CLI command listing recently viewed contacts and companies.
+// ABOUTME: Requires "track_access": true in config.json to record reads.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List recently viewed contacts and companies",
+	Long:  `List recently viewed contacts and companies. Reads are only recorded when "track_access": true is set in config.json.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entityType, _ := cmd.Flags().GetString("type")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		switch entityType {
+		case "", storage.EntityContact, storage.EntityCompany:
+			// valid
+		default:
+			return fmt.Errorf("invalid type %q, expected %q or %q", entityType, storage.EntityContact, storage.EntityCompany)
+		}
+
+		ids, err := store.ListRecent(entityType, limit)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			msg := "No recent items."
+			if !appConfig.TrackAccess {
+				msg += ` Enable tracking with "track_access": true in config.json.`
+			}
+			outln(msg)
+			return nil
+		}
+
+		names, err := entityNames()
+		if err != nil {
+			return err
+		}
+
+		cyan := color.New(color.FgCyan)
+		bold := color.New(color.Bold)
+		for _, id := range ids {
+			name, ok := names[id]
+			if !ok {
+				continue
+			}
+			out("%s  %s\n", cyan.Sprint(id), bold.Sprint(name))
+		}
+		return nil
+	},
+}
+
+// entityNames maps every contact and company ID to its name. Names are
+// looked up in bulk so listing does not itself count as a read.
+func entityNames() (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string)
+	err := store.ForEachContact(func(c *models.Contact) error {
+		names[c.ID] = c.Name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = store.ForEachCompany(func(c *models.Company) error {
+		names[c.ID] = c.Name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func init() {
+	recentCmd.Flags().String("type", "", "only show this entity type (contact or company)")
+	recentCmd.Flags().IntP("limit", "n", 10, "max results to show")
+
+	rootCmd.AddCommand(recentCmd)
+}
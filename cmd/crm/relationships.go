@@ -29,7 +29,7 @@ func resolveEntityID(idStr string) (uuid.UUID, error) {
 
 var linkCmd = &cobra.Command{
 	Use:   "link <source-id> <target-id>",
-	Short: "Create a relationship between two entities",
+	Short: "Create or update a relationship between two entities",
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sourceID, err := resolveEntityID(args[0])
@@ -45,11 +45,16 @@ var linkCmd = &cobra.Command{
 		context, _ := cmd.Flags().GetString("context")
 
 		rel := models.NewRelationship(sourceID, targetID, relType, context)
-		if err := store.CreateRelationship(rel); err != nil {
+		newID := rel.ID
+		if err := store.CreateOrUpdateRelationship(rel); err != nil {
 			return err
 		}
 
 		cyan := color.New(color.FgCyan)
+		if rel.ID != newID {
+			out("Updated relationship %s\n", cyan.Sprint(rel.ID))
+			return nil
+		}
 		out("Created relationship %s\n", cyan.Sprint(rel.ID))
 		return nil
 	},
@@ -0,0 +1,120 @@
+// ABOUTME: Name-based contact resolution for CLI commands that take a contact.
+// ABOUTME: Lists numbered candidates when a name is ambiguous or, for destructive commands, only partial, so --pick can choose one.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+// contactPick is the 1-based --pick choice among name matches; 0 means none.
+var contactPick int
+
+// pickContact narrows name matches for query to a single contact, honoring
+// --pick. An ambiguous name without --pick is an error listing the
+// candidates. With exactOnly, as for destructive commands, a partial match
+// is never taken on its own: it needs --pick even when it is the only one.
+func pickContact(query string, exactOnly bool) (*models.Contact, error) {
+	matches, exact, err := contactsByName(query)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case exactOnly && !exact && len(matches) > 0 && contactPick == 0:
+		return nil, partialContactError(query, matches)
+	case len(matches) == 0:
+		return nil, fmt.Errorf("no contact matches %q", query)
+	case contactPick > len(matches):
+		return nil, fmt.Errorf("--pick %d is out of range: %q matches %d contact(s)", contactPick, query, len(matches))
+	case contactPick > 0:
+		return matches[contactPick-1], nil
+	case len(matches) == 1:
+		return matches[0], nil
+	default:
+		return nil, ambiguousContactError(query, matches)
+	}
+}
+
+// contactsByName returns contacts whose name contains query, ignoring case.
+// If any name matches query exactly, only the exact matches are returned and
+// exact is true. Results are ordered by name, then creation time, so --pick
+// is stable.
+func contactsByName(query string) (matches []*models.Contact, exact bool, err error) {
+	q := normalizeName(query)
+	var exactMatches, partial []*models.Contact
+	err = store.ForEachContact(func(c *models.Contact) error {
+		name := normalizeName(c.Name)
+		switch {
+		case name == q:
+			exactMatches = append(exactMatches, c)
+		case strings.Contains(name, q):
+			partial = append(partial, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	matches, exact = partial, len(exactMatches) > 0
+	if exact {
+		matches = exactMatches
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].CreatedAt.Before(matches[j].CreatedAt)
+	})
+	return matches, exact, nil
+}
+
+// normalizeName lowercases s and collapses runs of whitespace.
+func normalizeName(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// ambiguousContactError describes each candidate by ID, name, company, and
+// email, numbered for use with --pick.
+func ambiguousContactError(query string, matches []*models.Contact) error {
+	return candidateError(fmt.Sprintf("%q matches %d contacts:", query, len(matches)), matches)
+}
+
+// partialContactError refuses a partial name match for a destructive
+// command, listing the candidates for --pick.
+func partialContactError(query string, matches []*models.Contact) error {
+	return candidateError(fmt.Sprintf("%q is not the full name of any contact; partial matches:", query), matches)
+}
+
+// candidateError lists matches under header, numbered for use with --pick.
+func candidateError(header string, matches []*models.Contact) error {
+	var b strings.Builder
+	b.WriteString(header + "\n")
+	for i, c := range matches {
+		fmt.Fprintf(&b, "  %d. %s  %s", i+1, c.ID, c.Name)
+		if company := primaryCompanyName(c); company != "" {
+			fmt.Fprintf(&b, "  %s", company)
+		}
+		if c.Email != "" {
+			fmt.Fprintf(&b, "  <%s>", c.Email)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("re-run with --pick N or pass the contact's ID")
+	return errors.New(b.String())
+}
+
+// primaryCompanyName returns the name of the first company linked to c, or
+// "" if there is none or the lookup fails; it only decorates a candidate list.
+func primaryCompanyName(c *models.Contact) string {
+	expanded, err := store.GetContactExpanded(c.ID)
+	if err != nil || len(expanded.Companies) == 0 {
+		return ""
+	}
+	return expanded.Companies[0].Name
+}
@@ -4,14 +4,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/harperreed/crm/internal/config"
 	"github.com/harperreed/crm/internal/storage"
 	"github.com/spf13/cobra"
 )
 
-var store storage.Storage
+var (
+	store     storage.Storage
+	appConfig *config.Config
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "crm",
@@ -26,6 +33,22 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("load config: %w", err)
 		}
+		// Explicit flags beat CRM_DB and the config file; --db beats --profile.
+		if cmd.Flags().Changed("profile") {
+			profile, _ := cmd.Flags().GetString("profile")
+			cfg.UseProfile(profile)
+		}
+		if cmd.Flags().Changed("db") {
+			cfg.DBPath, _ = cmd.Flags().GetString("db")
+		}
+		appConfig = cfg
+
+		loc, err := cfg.DisplayLocation()
+		if err != nil {
+			return err
+		}
+		displayLoc = loc
+
 		s, err := cfg.OpenStorage()
 		if err != nil {
 			return fmt.Errorf("open storage: %w", err)
@@ -34,14 +57,30 @@ var rootCmd = &cobra.Command{
 		return nil
 	},
 	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
-		if store != nil {
-			return store.Close()
-		}
-		return nil
+		return closeStore()
 	},
 }
 
-// Execute runs the root command.
+func init() {
+	rootCmd.PersistentFlags().String("db", "", "storage location (sqlite file or markdown dir); overrides $"+config.DBPathEnv)
+	rootCmd.PersistentFlags().String("profile", "", "named profile stored under the data directory (e.g. work, personal); overrides $"+config.DBPathEnv+" and db_path")
+}
+
+// closeStore closes the open storage backend, if any. It is safe to call
+// more than once.
+func closeStore() error {
+	if store == nil {
+		return nil
+	}
+	err := store.Close()
+	store = nil
+	return err
+}
+
+// Execute runs the root command. SIGINT and SIGTERM cancel the command's
+// context so long-running commands such as mcp can shut down cleanly.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return rootCmd.ExecuteContext(ctx)
 }
@@ -4,10 +4,13 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/harperreed/crm/internal/storage"
 )
@@ -16,8 +19,25 @@ import (
 type Config struct {
 	Backend string `json:"backend,omitempty"` // "sqlite" or "markdown", default "sqlite"
 	DataDir string `json:"data_dir,omitempty"`
+	DBPath  string `json:"db_path,omitempty"` // explicit database file (sqlite) or directory (markdown)
+	Profile string `json:"profile,omitempty"` // named profile stored alongside the default data
+
+	TrackAccess     bool   `json:"track_access,omitempty"`     // record reads for the "recently viewed" list
+	UniqueEmail     bool   `json:"unique_email,omitempty"`     // reject contacts whose email another contact already uses
+	IdempotencyTTL  string `json:"idempotency_ttl,omitempty"`  // Go duration, e.g. "24h"; empty uses the storage default
+	ChangeRetention string `json:"change_retention,omitempty"` // Go duration the change feed and deletion log are kept; empty uses the storage default
+	DefaultLimit    int    `json:"default_limit,omitempty"`    // list size when none is requested; 0 uses the storage default
+	MaxLimit        int    `json:"max_limit,omitempty"`        // largest list size a request may ask for; 0 uses the storage default
+
+	Timezone string `json:"timezone,omitempty"` // IANA zone for CLI display, e.g. "Europe/Berlin"; empty uses the local zone
 }
 
+// DBPathEnv names the environment variable that overrides the storage location.
+const DBPathEnv = "CRM_DB"
+
+// profileNamePattern restricts profile names to safe single path components.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 // GetBackend returns the configured storage backend, defaulting to "sqlite".
 func (c *Config) GetBackend() string {
 	if c.Backend == "" {
@@ -26,6 +46,14 @@ func (c *Config) GetBackend() string {
 	return c.Backend
 }
 
+// UseProfile selects a named profile chosen explicitly, e.g. with --profile.
+// It clears any DBPath taken from CRM_DB or db_path, since that location
+// would otherwise win over the profile in StoragePath.
+func (c *Config) UseProfile(name string) {
+	c.Profile = name
+	c.DBPath = ""
+}
+
 // GetDataDir returns the effective data directory, expanding ~ to the user's
 // home directory. Falls back to the XDG data directory when no override is set.
 func (c *Config) GetDataDir() string {
@@ -47,15 +75,76 @@ func ExpandPath(path string) string {
 	return filepath.Join(home, path[1:])
 }
 
+// StoragePath resolves where the configured backend keeps its data: the
+// database file for sqlite, or the root directory for markdown. An explicit
+// DBPath wins over a Profile, which wins over the default location.
+func (c *Config) StoragePath() (string, error) {
+	if c.DBPath != "" {
+		return ExpandPath(c.DBPath), nil
+	}
+	if c.Profile != "" && !profileNamePattern.MatchString(c.Profile) {
+		return "", fmt.Errorf("invalid profile name %q: use letters, digits, '-' or '_'", c.Profile)
+	}
+
+	switch c.GetBackend() {
+	case "sqlite":
+		name := "crm"
+		if c.Profile != "" {
+			name = c.Profile
+		}
+		return filepath.Join(c.GetDataDir(), name+".db"), nil
+	case "markdown":
+		if c.Profile != "" {
+			return filepath.Join(c.GetDataDir(), c.Profile), nil
+		}
+		return c.GetDataDir(), nil
+	default:
+		return "", fmt.Errorf("unknown storage backend: %q", c.GetBackend())
+	}
+}
+
+// DisplayLocation returns the time zone the CLI renders timestamps in.
+// Timestamps are always stored in UTC; this only affects display.
+func (c *Config) DisplayLocation() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// StorageOptions translates config settings into backend options.
+func (c *Config) StorageOptions() storage.Options {
+	// Load validates IdempotencyTTL and ChangeRetention, so a parse failure
+	// here means a hand-built Config; fall back to the storage default.
+	ttl, _ := time.ParseDuration(c.IdempotencyTTL)
+	retention, _ := time.ParseDuration(c.ChangeRetention)
+	return storage.Options{
+		TrackAccess:        c.TrackAccess,
+		EnforceUniqueEmail: c.UniqueEmail,
+		IdempotencyTTL:     ttl,
+		ChangeRetention:    retention,
+		DefaultLimit:       c.DefaultLimit,
+		MaxLimit:           c.MaxLimit,
+	}
+}
+
 // OpenStorage creates and returns a Storage implementation based on the
 // configured backend.
 func (c *Config) OpenStorage() (storage.Storage, error) {
+	path, err := c.StoragePath()
+	if err != nil {
+		return nil, err
+	}
+
 	switch c.GetBackend() {
 	case "sqlite":
-		dbPath := filepath.Join(c.GetDataDir(), "crm.db")
-		return storage.NewSqliteStore(dbPath)
+		return storage.NewSqliteStoreWithOptions(path, c.StorageOptions())
 	case "markdown":
-		return storage.NewMarkdownStore(c.GetDataDir())
+		return storage.NewMarkdownStoreWithOptions(path, c.StorageOptions())
 	default:
 		return nil, fmt.Errorf("unknown storage backend: %q", c.GetBackend())
 	}
@@ -72,19 +161,44 @@ func GetConfigPath() string {
 
 // Load reads the config file from the XDG config path. If the file does not
 // exist, it returns a zero-value Config (which defaults to sqlite backend).
+// A non-empty CRM_DB environment variable overrides the configured DBPath.
 func Load() (*Config, error) {
+	var cfg Config
+
 	path := filepath.Clean(GetConfigPath())
 	data, err := os.ReadFile(path) //nolint:gosec // path is derived from XDG env or home dir, not user input
-	if os.IsNotExist(err) {
-		return &Config{}, nil
-	}
-	if err != nil {
+	switch {
+	case os.IsNotExist(err):
+		// No config file: fall through to defaults.
+	case err != nil:
 		return nil, fmt.Errorf("read config: %w", err)
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse config: %w", err)
+		}
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parse config: %w", err)
+	if cfg.IdempotencyTTL != "" {
+		if _, err := time.ParseDuration(cfg.IdempotencyTTL); err != nil {
+			return nil, fmt.Errorf("parse idempotency_ttl: %w", err)
+		}
+	}
+	if cfg.ChangeRetention != "" {
+		if _, err := time.ParseDuration(cfg.ChangeRetention); err != nil {
+			return nil, fmt.Errorf("parse change_retention: %w", err)
+		}
+	}
+
+	if cfg.DefaultLimit < 0 || cfg.MaxLimit < 0 {
+		return nil, errors.New("default_limit and max_limit must not be negative")
+	}
+
+	if _, err := cfg.DisplayLocation(); err != nil {
+		return nil, err
+	}
+
+	if env := os.Getenv(DBPathEnv); env != "" {
+		cfg.DBPath = env
 	}
 	return &cfg, nil
 }
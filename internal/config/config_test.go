@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -88,3 +89,161 @@ func TestOpenStorageUnknown(t *testing.T) {
 		t.Fatal("expected error for unknown backend, got nil")
 	}
 }
+
+func TestStoragePath(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"sqlite default", Config{DataDir: "/data"}, "/data/crm.db"},
+		{"sqlite profile", Config{DataDir: "/data", Profile: "work"}, "/data/work.db"},
+		{"markdown default", Config{Backend: "markdown", DataDir: "/data"}, "/data"},
+		{"markdown profile", Config{Backend: "markdown", DataDir: "/data", Profile: "work"}, "/data/work"},
+		{"explicit path wins", Config{DataDir: "/data", Profile: "work", DBPath: "/elsewhere/x.db"}, "/elsewhere/x.db"},
+	}
+	for _, tt := range tests {
+		got, err := tt.cfg.StoragePath()
+		if err != nil {
+			t.Errorf("%s: StoragePath: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: StoragePath() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStoragePathInvalidProfile(t *testing.T) {
+	for _, profile := range []string{"../escape", "a/b", "with space"} {
+		cfg := &Config{DataDir: "/data", Profile: profile}
+		if _, err := cfg.StoragePath(); err == nil {
+			t.Errorf("StoragePath with profile %q: expected error, got nil", profile)
+		}
+	}
+}
+
+func TestLoadDBPathEnv(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(DBPathEnv, "/tmp/env.db")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DBPath != "/tmp/env.db" {
+		t.Errorf("DBPath = %q, want %q", cfg.DBPath, "/tmp/env.db")
+	}
+}
+
+func TestUseProfileOverridesDBPathEnv(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(DBPathEnv, "/tmp/env.db")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cfg.DataDir = "/data"
+	cfg.UseProfile("work")
+
+	got, err := cfg.StoragePath()
+	if err != nil {
+		t.Fatalf("StoragePath: %v", err)
+	}
+	if got != "/data/work.db" {
+		t.Errorf("StoragePath = %q, want the work profile rather than $%s", got, DBPathEnv)
+	}
+}
+
+func TestOpenStorageProfile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{Backend: "sqlite", DataDir: dir, Profile: "personal"}
+
+	s, err := cfg.OpenStorage()
+	if err != nil {
+		t.Fatalf("OpenStorage: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if _, err := os.Stat(filepath.Join(dir, "personal.db")); os.IsNotExist(err) {
+		t.Error("expected personal.db to be created")
+	}
+}
+
+func TestLoadInvalidIdempotencyTTL(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "crm"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "crm", "config.json"), []byte(`{"idempotency_ttl": "soon"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); err == nil {
+		t.Error("Load with invalid idempotency_ttl: expected error, got nil")
+	}
+}
+
+func TestStorageOptionsIdempotencyTTL(t *testing.T) {
+	cfg := &Config{IdempotencyTTL: "90m"}
+	if got := cfg.StorageOptions().IdempotencyTTL; got != 90*time.Minute {
+		t.Errorf("IdempotencyTTL = %v, want 90m", got)
+	}
+}
+
+func TestStorageOptionsChangeRetention(t *testing.T) {
+	cfg := &Config{ChangeRetention: "720h"}
+	if got := cfg.StorageOptions().ChangeRetention; got != 720*time.Hour {
+		t.Errorf("ChangeRetention = %v, want 720h", got)
+	}
+}
+
+func TestLoadNegativeLimit(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "crm"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "crm", "config.json"), []byte(`{"max_limit": -1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); err == nil {
+		t.Error("Load with negative max_limit: expected error, got nil")
+	}
+}
+
+func TestStorageOptionsLimits(t *testing.T) {
+	cfg := &Config{DefaultLimit: 25, MaxLimit: 500}
+	opts := cfg.StorageOptions()
+	if opts.DefaultLimit != 25 || opts.MaxLimit != 500 {
+		t.Errorf("limits = %d/%d, want 25/500", opts.DefaultLimit, opts.MaxLimit)
+	}
+}
+
+func TestStorageOptionsUniqueEmail(t *testing.T) {
+	if (&Config{}).StorageOptions().EnforceUniqueEmail {
+		t.Error("EnforceUniqueEmail on by default, want off")
+	}
+	if !(&Config{UniqueEmail: true}).StorageOptions().EnforceUniqueEmail {
+		t.Error("UniqueEmail config not passed to storage options")
+	}
+}
+
+func TestDisplayLocation(t *testing.T) {
+	loc, err := (&Config{}).DisplayLocation()
+	if err != nil || loc != time.Local {
+		t.Errorf("default DisplayLocation = %v, %v; want Local", loc, err)
+	}
+
+	loc, err = (&Config{Timezone: "UTC"}).DisplayLocation()
+	if err != nil || loc.String() != "UTC" {
+		t.Errorf("DisplayLocation(UTC) = %v, %v", loc, err)
+	}
+
+	if _, err := (&Config{Timezone: "Mars/Olympus_Mons"}).DisplayLocation(); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+}
@@ -0,0 +1,42 @@
+// ABOUTME: Static catalog of every MCP tool with its JSON Schema input definition.
+// ABOUTME: Lets integrators dump and diff the tool surface without starting a server.
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ToolSpec describes one MCP tool for documentation or client generation.
+type ToolSpec struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// Catalog returns a spec for every registered tool, sorted by name. It
+// fails if any tool's input schema is not a valid JSON object.
+func Catalog() ([]ToolSpec, error) {
+	var s Server
+	entries := s.toolEntries()
+	specs := make([]ToolSpec, 0, len(entries))
+	for _, e := range entries {
+		schema, err := json.Marshal(e.tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s input schema: %w", e.tool.Name, err)
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(schema, &obj); err != nil {
+			return nil, fmt.Errorf("%s input schema is not a JSON object: %w", e.tool.Name, err)
+		}
+		specs = append(specs, ToolSpec{
+			Name:        e.tool.Name,
+			Description: e.tool.Description,
+			InputSchema: schema,
+		})
+	}
+	slices.SortFunc(specs, func(a, b ToolSpec) int { return strings.Compare(a.Name, b.Name) })
+	return specs, nil
+}
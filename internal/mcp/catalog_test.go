@@ -0,0 +1,44 @@
+// ABOUTME: Tests for the static MCP tool catalog.
+// ABOUTME: Verifies the catalog matches the tools a live server lists and that every schema is an object.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestCatalogMatchesServedTools(t *testing.T) {
+	specs, err := Catalog()
+	if err != nil {
+		t.Fatalf("Catalog: %v", err)
+	}
+
+	session := connectTestServer(t, newTestStore(t))
+	listed, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(specs) != len(listed.Tools) {
+		t.Fatalf("catalog has %d tools, server lists %d", len(specs), len(listed.Tools))
+	}
+
+	served := make(map[string]bool)
+	for _, tool := range listed.Tools {
+		served[tool.Name] = true
+	}
+	for i, spec := range specs {
+		if !served[spec.Name] {
+			t.Errorf("catalog tool %q is not served", spec.Name)
+		}
+		if i > 0 && specs[i-1].Name >= spec.Name {
+			t.Errorf("catalog not sorted at %q", spec.Name)
+		}
+		var schema struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(spec.InputSchema, &schema); err != nil || schema.Type != "object" {
+			t.Errorf("%s input schema type = %q (err %v), want object", spec.Name, schema.Type, err)
+		}
+	}
+}
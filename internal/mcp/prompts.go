@@ -149,7 +149,8 @@ func (s *Server) handleCRMSearchPrompt(_ context.Context, req *mcp.GetPromptRequ
 							"Please analyze these results and:\n"+
 							"1. Summarize what was found\n"+
 							"2. Highlight any relationships between the results\n"+
-							"3. Suggest follow-up actions",
+							"3. Suggest follow-up actions, never proposing outreach to contacts with DoNotContact set\n"+
+							"4. List any DoNotContact contacts separately so they are not missed",
 						query, string(data),
 					),
 				},
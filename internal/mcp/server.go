@@ -4,6 +4,10 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -12,27 +16,122 @@ import (
 
 // Server wraps an MCP server with a CRM storage backend.
 type Server struct {
-	server *mcp.Server
-	store  storage.Storage
+	server      *mcp.Server
+	store       storage.Storage
+	version     string
+	backend     string
+	storagePath string
+	log         io.Writer
+
+	// inflight counts tool calls still running, so Serve can let them
+	// finish before returning on shutdown. inflightMu guards shuttingDown
+	// and every inflight.Add, so no call is added once the wait has begun.
+	inflight     sync.WaitGroup
+	inflightMu   sync.Mutex
+	shuttingDown bool
+}
+
+// ShutdownTimeout bounds how long Serve waits for in-flight tool calls
+// once its context is cancelled.
+const ShutdownTimeout = 5 * time.Second
+
+// Option configures optional Server metadata.
+type Option func(*Server)
+
+// WithVersion sets the application version reported to clients.
+func WithVersion(version string) Option {
+	return func(s *Server) { s.version = version }
+}
+
+// WithStorageLocation records the backend name and resolved storage path
+// reported by the server_info tool.
+func WithStorageLocation(backend, path string) Option {
+	return func(s *Server) {
+		s.backend = backend
+		s.storagePath = path
+	}
+}
+
+// WithLog sets where Serve reports shutdown progress. By default nothing is
+// logged.
+func WithLog(w io.Writer) Option {
+	return func(s *Server) { s.log = w }
 }
 
 // NewServer creates an MCP server wired to the given storage backend,
 // registering all CRM tools, resource templates, and prompts.
-func NewServer(store storage.Storage) *Server {
+func NewServer(store storage.Storage, opts ...Option) *Server {
 	s := &Server{
-		server: mcp.NewServer(
-			&mcp.Implementation{Name: "crm", Version: "1.0.0"},
-			nil,
-		),
-		store: store,
+		store:   store,
+		version: "1.0.0",
+		log:     io.Discard,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.server = mcp.NewServer(
+		&mcp.Implementation{Name: "crm", Version: s.version},
+		nil,
+	)
 	s.registerTools()
 	s.registerResources()
 	s.registerPrompts()
 	return s
 }
 
-// Serve runs the MCP server on stdio until ctx is cancelled or the connection closes.
+// Serve runs the MCP server on stdio until ctx is cancelled or the
+// connection closes. On cancellation it waits up to ShutdownTimeout for
+// in-flight tool calls and returns nil, leaving the store for the caller
+// to close.
 func (s *Server) Serve(ctx context.Context) error {
-	return s.server.Run(ctx, &mcp.StdioTransport{})
+	err := s.server.Run(ctx, &mcp.StdioTransport{})
+	if ctx.Err() == nil {
+		return err
+	}
+	s.logf("shutting down: waiting for in-flight tool calls")
+	if !s.waitInflight(ShutdownTimeout) {
+		s.logf("gave up on in-flight tool calls after %s", ShutdownTimeout)
+	}
+	return nil
+}
+
+// track wraps a tool handler so Serve can wait for it during shutdown.
+// Calls that arrive once shutdown has begun are rejected.
+func (s *Server) track(h mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.inflightMu.Lock()
+		if s.shuttingDown {
+			s.inflightMu.Unlock()
+			return errResult("server is shutting down")
+		}
+		s.inflight.Add(1)
+		s.inflightMu.Unlock()
+		defer s.inflight.Done()
+		return h(ctx, req)
+	}
+}
+
+// waitInflight stops accepting tool calls and waits for running ones to
+// finish, reporting false if timeout passes first.
+func (s *Server) waitInflight(timeout time.Duration) bool {
+	s.inflightMu.Lock()
+	s.shuttingDown = true
+	s.inflightMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// logf writes one shutdown progress line.
+func (s *Server) logf(format string, a ...any) {
+	_, _ = fmt.Fprintf(s.log, "crm: "+format+"\n", a...)
 }
@@ -5,11 +5,18 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/harperreed/crm/internal/models"
 	"github.com/harperreed/crm/internal/storage"
 )
 
@@ -27,9 +34,9 @@ func newTestStore(t *testing.T) storage.Storage {
 }
 
 // connectTestServer creates a Server and connects a client via in-memory transport.
-func connectTestServer(t *testing.T, store storage.Storage) *mcp.ClientSession {
+func connectTestServer(t *testing.T, store storage.Storage, opts ...Option) *mcp.ClientSession {
 	t.Helper()
-	srv := NewServer(store)
+	srv := NewServer(store, opts...)
 
 	serverTransport, clientTransport := mcp.NewInMemoryTransports()
 
@@ -73,8 +80,15 @@ func TestServerListTools(t *testing.T) {
 
 	expectedTools := []string{
 		"add_contact", "list_contacts", "get_contact", "update_contact", "delete_contact",
+		"add_contacts_batch",
+		"add_contact_note", "list_contact_notes", "delete_contact_note",
 		"add_company", "list_companies", "get_company", "update_company", "delete_company",
-		"link", "unlink",
+		"add_company_note", "list_company_notes", "delete_company_note",
+		"link", "unlink", "suggest_colleagues",
+		"resolve_email", "list_contacts_by_domain", "link_domain_contacts",
+		"attach_file", "list_attachments", "get_attachment",
+		"set_contact_photo", "get_contact_photo",
+		"server_info",
 	}
 
 	toolNames := make(map[string]bool)
@@ -132,6 +146,48 @@ func TestServerAddAndGetContact(t *testing.T) {
 	}
 }
 
+func TestServerContactSource(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	for _, args := range []map[string]any{
+		{"name": "Ada"},
+		{"name": "Grace", "source": "csv"},
+	} {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "add_contact", Arguments: args})
+		if err != nil || result.IsError {
+			t.Fatalf("add_contact %v: err=%v text=%s", args, err, contentText(result))
+		}
+	}
+
+	list, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_contacts",
+		Arguments: map[string]any{"source": "csv"},
+	})
+	if err != nil || list.IsError {
+		t.Fatalf("list_contacts: err=%v text=%s", err, contentText(list))
+	}
+	var contacts []struct {
+		Name   string `json:"Name"`
+		Source string `json:"Source"`
+	}
+	if err := parseContent(list, &contacts); err != nil {
+		t.Fatalf("parse list_contacts: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].Name != "Grace" {
+		t.Fatalf("list_contacts source=csv = %+v, want only Grace", contacts)
+	}
+
+	all, err := store.ListContacts(&storage.ContactFilter{Source: models.SourceMCP})
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(all) != 1 || all[0].Name != "Ada" {
+		t.Errorf("contacts with source mcp = %v, want only Ada", all)
+	}
+}
+
 func TestServerAddAndGetCompany(t *testing.T) {
 	store := newTestStore(t)
 	session := connectTestServer(t, store)
@@ -258,6 +314,30 @@ func TestServerLinkAndUnlink(t *testing.T) {
 		t.Fatalf("parse link: %v", err)
 	}
 
+	// Re-linking the pair in the other direction updates the same relationship.
+	relinkResult, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "link",
+		Arguments: map[string]any{
+			"source_id": h2.ID,
+			"target_id": h1.ID,
+			"type":      "knows",
+			"context":   "met at a conference",
+		},
+	})
+	if err != nil || relinkResult.IsError {
+		t.Fatalf("relink: err=%v isError=%v text=%s", err, relinkResult.IsError, contentText(relinkResult))
+	}
+	var relinked struct {
+		ID      string `json:"ID"`
+		Context string `json:"Context"`
+	}
+	if err := parseContent(relinkResult, &relinked); err != nil {
+		t.Fatalf("parse relink: %v", err)
+	}
+	if relinked.ID != relHolder.ID || relinked.Context != "met at a conference" {
+		t.Errorf("relink = %+v, want ID %s with updated context", relinked, relHolder.ID)
+	}
+
 	// Unlink.
 	unlinkResult, err := session.CallTool(ctx, &mcp.CallToolParams{
 		Name:      "unlink",
@@ -363,6 +443,103 @@ func TestServerUpdateContact(t *testing.T) {
 	}
 }
 
+func TestServerUpdateContactDoNotContact(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	contact := models.NewContact("Quiet Quinn")
+	contact.Email = "quinn@example.com"
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	type contactData struct {
+		Name         string `json:"Name"`
+		Email        string `json:"Email"`
+		DoNotContact bool   `json:"DoNotContact"`
+	}
+	update := func(args map[string]any) contactData {
+		t.Helper()
+		args["id"] = contact.ID.String()
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "update_contact", Arguments: args})
+		if err != nil || res.IsError {
+			t.Fatalf("update_contact: err=%v text=%s", err, contentText(res))
+		}
+		var got contactData
+		if err := parseContent(res, &got); err != nil {
+			t.Fatalf("parse update: %v", err)
+		}
+		return got
+	}
+
+	got := update(map[string]any{"do_not_contact": true})
+	if !got.DoNotContact {
+		t.Error("expected DoNotContact to be set")
+	}
+	if got.Name != "Quiet Quinn" || got.Email != "quinn@example.com" {
+		t.Errorf("setting the flag changed other fields: %+v", got)
+	}
+
+	// Updating another field leaves the flag alone.
+	if got := update(map[string]any{"name": "Quinn"}); !got.DoNotContact {
+		t.Error("expected DoNotContact to survive an unrelated update")
+	}
+
+	if got := update(map[string]any{"do_not_contact": false}); got.DoNotContact {
+		t.Error("expected do_not_contact=false to clear the flag")
+	}
+}
+
+func TestServerUpdateStaleVersion(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	contact := models.NewContact("Versioned")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	company := models.NewCompany("Versioned Inc")
+	if err := store.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	for _, tool := range []struct {
+		name string
+		id   string
+	}{
+		{"update_contact", contact.ID.String()},
+		{"update_company", company.ID.String()},
+	} {
+		// The first update reads version 1 and succeeds; repeating it with
+		// the now stale version must be rejected.
+		for i, wantErr := range []bool{false, true} {
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{
+				Name:      tool.name,
+				Arguments: map[string]any{"id": tool.id, "name": fmt.Sprintf("Renamed %d", i), "version": 1},
+			})
+			if err != nil {
+				t.Fatalf("%s: %v", tool.name, err)
+			}
+			if result.IsError != wantErr {
+				t.Fatalf("%s call %d: isError=%v, want %v (text=%s)", tool.name, i, result.IsError, wantErr, contentText(result))
+			}
+			if wantErr && !strings.HasPrefix(contentText(result), "version conflict:") {
+				t.Errorf("%s stale version: text = %q, want a version conflict", tool.name, contentText(result))
+			}
+		}
+	}
+
+	got, err := store.GetContact(contact.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.Name != "Renamed 0" || got.Version != 2 {
+		t.Errorf("contact = %q v%d, want the first rename at v2", got.Name, got.Version)
+	}
+}
+
 func TestServerErrorOnMissingRequired(t *testing.T) {
 	store := newTestStore(t)
 	session := connectTestServer(t, store)
@@ -402,3 +579,271 @@ func parseContent(r *mcp.CallToolResult, v any) error {
 	}
 	return json.Unmarshal([]byte(text), v)
 }
+
+func TestServerGetContactExpanded(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	type idHolder struct {
+		ID string `json:"ID"`
+	}
+	add := func(tool, name string) string {
+		t.Helper()
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      tool,
+			Arguments: map[string]any{"name": name},
+		})
+		if err != nil || res.IsError {
+			t.Fatalf("%s %s: err=%v", tool, name, err)
+		}
+		var h idHolder
+		if err := parseContent(res, &h); err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		return h.ID
+	}
+	aliceID := add("add_contact", "Alice")
+	acmeID := add("add_company", "Acme")
+
+	linkResult, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "link",
+		Arguments: map[string]any{"source_id": aliceID, "target_id": acmeID, "type": "works_at"},
+	})
+	if err != nil || linkResult.IsError {
+		t.Fatalf("link: err=%v text=%s", err, contentText(linkResult))
+	}
+
+	noteResult, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "add_contact_note",
+		Arguments: map[string]any{"contact_id": aliceID, "content": "Met at the conference"},
+	})
+	if err != nil || noteResult.IsError {
+		t.Fatalf("add_contact_note: err=%v text=%s", err, contentText(noteResult))
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_contact",
+		Arguments: map[string]any{"id": aliceID[:8], "expand": true},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("get_contact expand: err=%v text=%s", err, contentText(result))
+	}
+
+	var keys map[string]json.RawMessage
+	if err := parseContent(result, &keys); err != nil {
+		t.Fatalf("parse expanded keys: %v", err)
+	}
+	// Like every other entity the tools return, the expanded view keeps the
+	// models' Go field names.
+	for _, k := range []string{"Contact", "Companies", "Relationships", "Notes", "Attachments"} {
+		if _, ok := keys[k]; !ok {
+			t.Errorf("expanded contact has no %q key: %s", k, contentText(result))
+		}
+	}
+	if len(keys) != 5 {
+		t.Errorf("expanded contact has %d keys, want 5: %s", len(keys), contentText(result))
+	}
+
+	var expanded struct {
+		Contact       struct{ Name string }
+		Companies     []struct{ Name string }
+		Relationships []struct {
+			OtherType string
+			OtherName string
+		}
+		Notes []struct{ Content string }
+	}
+	if err := parseContent(result, &expanded); err != nil {
+		t.Fatalf("parse expanded: %v", err)
+	}
+	if expanded.Contact.Name != "Alice" {
+		t.Errorf("Contact.Name = %q, want Alice", expanded.Contact.Name)
+	}
+	if len(expanded.Companies) != 1 || expanded.Companies[0].Name != "Acme" {
+		t.Errorf("Companies = %+v, want [Acme]", expanded.Companies)
+	}
+	if len(expanded.Relationships) != 1 || expanded.Relationships[0].OtherName != "Acme" {
+		t.Errorf("Relationships = %+v, want one to Acme", expanded.Relationships)
+	}
+	if len(expanded.Notes) != 1 || expanded.Notes[0].Content != "Met at the conference" {
+		t.Errorf("Notes = %+v, want the conference note", expanded.Notes)
+	}
+}
+
+func TestServerAddContactIdempotent(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	type idHolder struct {
+		ID string `json:"ID"`
+	}
+	call := func() string {
+		t.Helper()
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "add_contact",
+			Arguments: map[string]any{"name": "Retry Rita", "idempotency_key": "req-1"},
+		})
+		if err != nil || res.IsError {
+			t.Fatalf("add_contact: err=%v text=%s", err, contentText(res))
+		}
+		var h idHolder
+		if err := parseContent(res, &h); err != nil {
+			t.Fatalf("parse add_contact: %v", err)
+		}
+		return h.ID
+	}
+
+	first := call()
+	second := call()
+	if first != second {
+		t.Errorf("retry returned %s, want original %s", second, first)
+	}
+
+	contacts, err := store.ListContacts(nil)
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(contacts) != 1 {
+		t.Errorf("contacts = %d, want 1", len(contacts))
+	}
+}
+
+func TestServerAddContactIdempotentConcurrent(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	const calls = 8
+	ids := make(chan string, calls)
+	var wg sync.WaitGroup
+	for range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := session.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "add_contact",
+				Arguments: map[string]any{"name": "Racing Rita", "idempotency_key": "req-race"},
+			})
+			if err != nil || res.IsError {
+				// Losers may be told the winner is still creating; that's fine.
+				return
+			}
+			var h struct {
+				ID string `json:"ID"`
+			}
+			if err := parseContent(res, &h); err == nil {
+				ids <- h.ID
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	var first string
+	for id := range ids {
+		if first == "" {
+			first = id
+		} else if id != first {
+			t.Errorf("concurrent calls returned %s and %s, want one ID", first, id)
+		}
+	}
+
+	contacts, err := store.ListContacts(nil)
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(contacts) != 1 {
+		t.Errorf("contacts = %d, want 1", len(contacts))
+	}
+}
+
+func TestCreateIdempotentRecreatesDeletedEntity(t *testing.T) {
+	store := newTestStore(t)
+	srv := NewServer(store)
+
+	create := func() *models.Contact {
+		t.Helper()
+		c := models.NewContact("Phoenix")
+		got, err := createIdempotent(srv, storage.EntityContact, "req-again", c.ID, store.GetContact, storage.ErrContactNotFound,
+			func() (*models.Contact, error) { return c, store.CreateContact(c) })
+		if err != nil {
+			t.Fatalf("createIdempotent: %v", err)
+		}
+		return got
+	}
+
+	first := create()
+	if err := store.DeleteContact(first.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+	second := create()
+	if second.ID == first.ID {
+		t.Error("retry after delete returned the deleted contact")
+	}
+	if _, err := store.GetContact(second.ID); err != nil {
+		t.Errorf("GetContact(recreated): %v", err)
+	}
+}
+
+func TestCreateIdempotentReleasesKeyOnFailure(t *testing.T) {
+	store := newTestStore(t)
+	srv := NewServer(store)
+
+	get := func(uuid.UUID) (*models.Contact, error) { return nil, storage.ErrContactNotFound }
+	_, err := createIdempotent(srv, storage.EntityContact, "req-fail", uuid.New(), get, storage.ErrContactNotFound,
+		func() (*models.Contact, error) { return nil, errors.New("disk full") })
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("createIdempotent error = %v, want disk full", err)
+	}
+
+	if _, claimed, err := store.ClaimIdempotencyKey(storage.EntityContact, "req-fail", uuid.New()); err != nil || !claimed {
+		t.Errorf("claim after failed create = claimed:%v err:%v, want the key released", claimed, err)
+	}
+}
+
+func TestWaitInflight(t *testing.T) {
+	srv := NewServer(newTestStore(t))
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := srv.track(func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return textResult("done")
+	})
+	go func() { _, _ = handler(context.Background(), nil) }()
+	<-started
+
+	if srv.waitInflight(10 * time.Millisecond) {
+		t.Error("waitInflight returned true while a call was running")
+	}
+	close(release)
+	if !srv.waitInflight(time.Second) {
+		t.Error("waitInflight timed out after the call finished")
+	}
+}
+
+func TestTrackRejectsCallsAfterShutdown(t *testing.T) {
+	srv := NewServer(newTestStore(t))
+	if !srv.waitInflight(time.Second) {
+		t.Fatal("waitInflight timed out with no calls running")
+	}
+
+	ran := false
+	handler := srv.track(func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ran = true
+		return textResult("done")
+	})
+	res, err := handler(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if ran {
+		t.Error("handler ran after shutdown began")
+	}
+	if !res.IsError || !strings.Contains(contentText(res), "shutting down") {
+		t.Errorf("result = %s (isError %v), want a shutting down error", contentText(res), res.IsError)
+	}
+}
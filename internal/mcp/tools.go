@@ -1,10 +1,12 @@
 // ABOUTME: MCP tool handlers for CRM CRUD operations on contacts, companies, and relationships.
-// ABOUTME: Defines 12 tools with JSON schema input validation and helper functions for results.
+// ABOUTME: Registers every CRM tool and provides shared result and lookup helpers.
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -14,20 +16,54 @@ import (
 	"github.com/harperreed/crm/internal/storage"
 )
 
-// registerTools adds all 12 CRM tools to the MCP server.
+// toolEntry pairs a tool definition with the handler that serves it.
+type toolEntry struct {
+	tool    *mcp.Tool
+	handler mcp.ToolHandler
+}
+
+// toolEntries lists every CRM tool in registration order. Both the server
+// and Catalog read from here, so the published catalog cannot drift from
+// what is served.
+func (s *Server) toolEntries() []toolEntry {
+	return []toolEntry{
+		{addContactTool(), s.handleAddContact},
+		{listContactsTool(), s.handleListContacts},
+		{getContactTool(), s.handleGetContact},
+		{updateContactTool(), s.handleUpdateContact},
+		{deleteContactTool(), s.handleDeleteContact},
+		{addContactsBatchTool(), s.handleAddContactsBatch},
+		{addContactNoteTool(), s.handleAddContactNote},
+		{listContactNotesTool(), s.handleListContactNotes},
+		{deleteContactNoteTool(), s.handleDeleteContactNote},
+		{addCompanyTool(), s.handleAddCompany},
+		{listCompaniesTool(), s.handleListCompanies},
+		{getCompanyTool(), s.handleGetCompany},
+		{updateCompanyTool(), s.handleUpdateCompany},
+		{deleteCompanyTool(), s.handleDeleteCompany},
+		{addCompanyNoteTool(), s.handleAddCompanyNote},
+		{listCompanyNotesTool(), s.handleListCompanyNotes},
+		{deleteCompanyNoteTool(), s.handleDeleteCompanyNote},
+		{linkTool(), s.handleLink},
+		{unlinkTool(), s.handleUnlink},
+		{suggestColleaguesTool(), s.handleSuggestColleagues},
+		{resolveEmailTool(), s.handleResolveEmail},
+		{listContactsByDomainTool(), s.handleListContactsByDomain},
+		{linkDomainContactsTool(), s.handleLinkDomainContacts},
+		{attachFileTool(), s.handleAttachFile},
+		{listAttachmentsTool(), s.handleListAttachments},
+		{getAttachmentTool(), s.handleGetAttachment},
+		{setContactPhotoTool(), s.handleSetContactPhoto},
+		{getContactPhotoTool(), s.handleGetContactPhoto},
+		{serverInfoTool(), s.handleServerInfo},
+	}
+}
+
+// registerTools adds all CRM tools to the MCP server.
 func (s *Server) registerTools() {
-	s.server.AddTool(addContactTool(), s.handleAddContact)
-	s.server.AddTool(listContactsTool(), s.handleListContacts)
-	s.server.AddTool(getContactTool(), s.handleGetContact)
-	s.server.AddTool(updateContactTool(), s.handleUpdateContact)
-	s.server.AddTool(deleteContactTool(), s.handleDeleteContact)
-	s.server.AddTool(addCompanyTool(), s.handleAddCompany)
-	s.server.AddTool(listCompaniesTool(), s.handleListCompanies)
-	s.server.AddTool(getCompanyTool(), s.handleGetCompany)
-	s.server.AddTool(updateCompanyTool(), s.handleUpdateCompany)
-	s.server.AddTool(deleteCompanyTool(), s.handleDeleteCompany)
-	s.server.AddTool(linkTool(), s.handleLink)
-	s.server.AddTool(unlinkTool(), s.handleUnlink)
+	for _, e := range s.toolEntries() {
+		s.server.AddTool(e.tool, s.track(e.handler))
+	}
 }
 
 // --- result helpers ---
@@ -39,6 +75,13 @@ func errResult(msg string) (*mcp.CallToolResult, error) {
 	}, nil
 }
 
+// versionConflictResult reports a rejected update of a stale entity read.
+// The message starts with "version conflict" so clients can tell it apart
+// from other failures and re-read the entity before retrying.
+func versionConflictResult(entity string, version, current int) (*mcp.CallToolResult, error) {
+	return errResult(fmt.Sprintf("version conflict: %s is at version %d, not %d; get it again and reapply the change", entity, current, version))
+}
+
 func textResult(msg string) (*mcp.CallToolResult, error) {
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
@@ -71,6 +114,71 @@ func (s *Server) resolveCompany(idStr string) (*models.Company, error) {
 	return s.store.GetCompanyByPrefix(idStr)
 }
 
+// resolveEntity looks up a contact or company by full UUID or prefix string,
+// returning the entity type ("contact" or "company") and its ID.
+func (s *Server) resolveEntity(idStr string) (string, uuid.UUID, error) {
+	if c, err := s.resolveContact(idStr); err == nil {
+		return storage.EntityContact, c.ID, nil
+	}
+	if c, err := s.resolveCompany(idStr); err == nil {
+		return storage.EntityCompany, c.ID, nil
+	}
+	return "", uuid.Nil, fmt.Errorf("no contact or company found for %q", idStr)
+}
+
+// createIdempotent runs create for a new entity with ID newID, so that calls
+// retried with the same key return the entity the first call created
+// instead of a duplicate. The key is claimed before create runs, so of two
+// concurrent calls only one creates; the other gets the winner's entity, or
+// an error while the winner is still creating it. get loads an entity by ID
+// and returns notFound when it is gone. An empty key just runs create.
+func createIdempotent[T any](s *Server, entityType, key string, newID uuid.UUID,
+	get func(uuid.UUID) (T, error), notFound error, create func() (T, error)) (T, error) {
+	var zero T
+	if key == "" {
+		return create()
+	}
+
+	held, claimed, err := s.store.ClaimIdempotencyKey(entityType, key, newID)
+	if err != nil {
+		return zero, fmt.Errorf("claim idempotency key: %w", err)
+	}
+	if !claimed {
+		existing, err := get(held)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, notFound) {
+			return zero, fmt.Errorf("get %s: %w", entityType, err)
+		}
+		deleted, err := s.store.WasDeleted(entityType, held)
+		if err != nil {
+			return zero, fmt.Errorf("lookup deletion: %w", err)
+		}
+		if !deleted {
+			return zero, fmt.Errorf("a request with idempotency key %q is still creating this %s; retry shortly", key, entityType)
+		}
+		// The original was deleted since; create it again under the same key.
+		if err := s.store.ReleaseIdempotencyKey(entityType, key, held); err != nil {
+			return zero, fmt.Errorf("release idempotency key: %w", err)
+		}
+		if _, claimed, err = s.store.ClaimIdempotencyKey(entityType, key, newID); err != nil {
+			return zero, fmt.Errorf("claim idempotency key: %w", err)
+		}
+		if !claimed {
+			return zero, fmt.Errorf("a request with idempotency key %q is still creating this %s; retry shortly", key, entityType)
+		}
+	}
+
+	created, err := create()
+	if err != nil {
+		// Free the key so a retry can create the entity.
+		_ = s.store.ReleaseIdempotencyKey(entityType, key, newID)
+		return zero, err
+	}
+	return created, nil
+}
+
 // --- tool definitions ---
 
 func addContactTool() *mcp.Tool {
@@ -84,7 +192,9 @@ func addContactTool() *mcp.Tool {
 				"email":  {"type": "string", "description": "Email address"},
 				"phone":  {"type": "string", "description": "Phone number"},
 				"fields": {"type": "object", "description": "Additional key-value fields"},
-				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Tags for categorization"}
+				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Tags for categorization"},
+				"source": {"type": "string", "description": "Where the record came from, e.g. csv (default mcp)"},
+				"idempotency_key": {"type": "string", "description": "Client-chosen key; retrying with the same key returns the originally created record"}
 			},
 			"required": ["name"]
 		}`),
@@ -99,6 +209,7 @@ func listContactsTool() *mcp.Tool {
 			"type": "object",
 			"properties": {
 				"tag":    {"type": "string", "description": "Filter by tag"},
+				"source": {"type": "string", "description": "Filter by source, e.g. manual or csv"},
 				"search": {"type": "string", "description": "Full-text search query"},
 				"limit":  {"type": "integer", "description": "Maximum results (default 20)"}
 			}
@@ -113,7 +224,8 @@ func getContactTool() *mcp.Tool {
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
-				"id": {"type": "string", "description": "Contact UUID or prefix (min 6 chars)"}
+				"id":     {"type": "string", "description": "Contact UUID or prefix (min 6 chars)"},
+				"expand": {"type": "boolean", "description": "Also return linked companies, relationships with counterpart names, notes, and attachments"}
 			},
 			"required": ["id"]
 		}`),
@@ -132,7 +244,9 @@ func updateContactTool() *mcp.Tool {
 				"email":  {"type": "string", "description": "New email"},
 				"phone":  {"type": "string", "description": "New phone"},
 				"fields": {"type": "object", "description": "Fields to merge (keys are added/overwritten)"},
-				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Replacement tags"}
+				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Replacement tags"},
+				"do_not_contact": {"type": "boolean", "description": "Set true to mark the contact as not to be contacted, false to clear it"},
+				"version": {"type": "integer", "description": "Version the caller last read; the update fails with a version conflict if the contact has changed since"}
 			},
 			"required": ["id"]
 		}`),
@@ -163,7 +277,9 @@ func addCompanyTool() *mcp.Tool {
 				"name":   {"type": "string", "description": "Company name (required)"},
 				"domain": {"type": "string", "description": "Company domain/website"},
 				"fields": {"type": "object", "description": "Additional key-value fields"},
-				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Tags for categorization"}
+				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Tags for categorization"},
+				"source": {"type": "string", "description": "Where the record came from, e.g. csv (default mcp)"},
+				"idempotency_key": {"type": "string", "description": "Client-chosen key; retrying with the same key returns the originally created record"}
 			},
 			"required": ["name"]
 		}`),
@@ -178,6 +294,7 @@ func listCompaniesTool() *mcp.Tool {
 			"type": "object",
 			"properties": {
 				"tag":    {"type": "string", "description": "Filter by tag"},
+				"source": {"type": "string", "description": "Filter by source, e.g. manual or csv"},
 				"search": {"type": "string", "description": "Full-text search query"},
 				"limit":  {"type": "integer", "description": "Maximum results (default 20)"}
 			}
@@ -210,7 +327,8 @@ func updateCompanyTool() *mcp.Tool {
 				"name":   {"type": "string", "description": "New name"},
 				"domain": {"type": "string", "description": "New domain"},
 				"fields": {"type": "object", "description": "Fields to merge (keys are added/overwritten)"},
-				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Replacement tags"}
+				"tags":   {"type": "array", "items": {"type": "string"}, "description": "Replacement tags"},
+				"version": {"type": "integer", "description": "Version the caller last read; the update fails with a version conflict if the company has changed since"}
 			},
 			"required": ["id"]
 		}`),
@@ -234,7 +352,7 @@ func deleteCompanyTool() *mcp.Tool {
 func linkTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "link",
-		Description: "Create a relationship between two entities",
+		Description: "Create a relationship between two entities, or update the context of an existing one of the same type",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
@@ -264,19 +382,37 @@ func unlinkTool() *mcp.Tool {
 
 // --- tool handlers ---
 
+// addContactParams holds the arguments accepted by add_contact and by each
+// item of add_contacts_batch.
+type addContactParams struct {
+	Name   string         `json:"name"`
+	Email  string         `json:"email"`
+	Phone  string         `json:"phone"`
+	Fields map[string]any `json:"fields"`
+	Tags   []string       `json:"tags"`
+	Source string         `json:"source"`
+
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
 func (s *Server) handleAddContact(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	var params struct {
-		Name   string         `json:"name"`
-		Email  string         `json:"email"`
-		Phone  string         `json:"phone"`
-		Fields map[string]any `json:"fields"`
-		Tags   []string       `json:"tags"`
-	}
+	var params addContactParams
 	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
 		return errResult(fmt.Sprintf("invalid arguments: %v", err))
 	}
+
+	contact, err := s.addContact(params)
+	if err != nil {
+		return errResult(err.Error())
+	}
+	return jsonResult(contact)
+}
+
+// addContact validates params and creates the contact, honoring any
+// idempotency key by returning the contact an earlier call created.
+func (s *Server) addContact(params addContactParams) (*models.Contact, error) {
 	if params.Name == "" {
-		return errResult("name is required")
+		return nil, errors.New("name is required")
 	}
 
 	contact := models.NewContact(params.Name)
@@ -288,16 +424,22 @@ func (s *Server) handleAddContact(_ context.Context, req *mcp.CallToolRequest) (
 	if params.Tags != nil {
 		contact.Tags = params.Tags
 	}
+	contact.Source = cmp.Or(params.Source, models.SourceMCP)
 
-	if err := s.store.CreateContact(contact); err != nil {
-		return errResult(fmt.Sprintf("create contact: %v", err))
-	}
-	return jsonResult(contact)
+	return createIdempotent(s, storage.EntityContact, params.IdempotencyKey, contact.ID,
+		s.store.GetContact, storage.ErrContactNotFound,
+		func() (*models.Contact, error) {
+			if err := s.store.CreateContact(contact); err != nil {
+				return nil, fmt.Errorf("create contact: %w", err)
+			}
+			return contact, nil
+		})
 }
 
 func (s *Server) handleListContacts(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params struct {
 		Tag    *string `json:"tag"`
+		Source string  `json:"source"`
 		Search string  `json:"search"`
 		Limit  int     `json:"limit"`
 	}
@@ -312,6 +454,7 @@ func (s *Server) handleListContacts(_ context.Context, req *mcp.CallToolRequest)
 
 	contacts, err := s.store.ListContacts(&storage.ContactFilter{
 		Tag:    params.Tag,
+		Source: params.Source,
 		Search: params.Search,
 		Limit:  limit,
 	})
@@ -323,7 +466,8 @@ func (s *Server) handleListContacts(_ context.Context, req *mcp.CallToolRequest)
 
 func (s *Server) handleGetContact(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params struct {
-		ID string `json:"id"`
+		ID     string `json:"id"`
+		Expand bool   `json:"expand"`
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
 		return errResult(fmt.Sprintf("invalid arguments: %v", err))
@@ -336,7 +480,15 @@ func (s *Server) handleGetContact(_ context.Context, req *mcp.CallToolRequest) (
 	if err != nil {
 		return errResult(fmt.Sprintf("get contact: %v", err))
 	}
-	return jsonResult(contact)
+	if !params.Expand {
+		return jsonResult(contact)
+	}
+
+	expanded, err := s.store.GetContactExpanded(contact.ID)
+	if err != nil {
+		return errResult(fmt.Sprintf("get contact: %v", err))
+	}
+	return jsonResult(expanded)
 }
 
 func (s *Server) handleUpdateContact(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -347,6 +499,9 @@ func (s *Server) handleUpdateContact(_ context.Context, req *mcp.CallToolRequest
 		Phone  *string         `json:"phone"`
 		Fields map[string]any  `json:"fields"`
 		Tags   json.RawMessage `json:"tags"`
+
+		DoNotContact *bool `json:"do_not_contact"`
+		Version      *int  `json:"version"`
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
 		return errResult(fmt.Sprintf("invalid arguments: %v", err))
@@ -359,6 +514,10 @@ func (s *Server) handleUpdateContact(_ context.Context, req *mcp.CallToolRequest
 	if err != nil {
 		return errResult(fmt.Sprintf("get contact: %v", err))
 	}
+	current := contact.Version
+	if params.Version != nil {
+		contact.Version = *params.Version
+	}
 
 	if params.Name != nil {
 		contact.Name = *params.Name
@@ -381,9 +540,15 @@ func (s *Server) handleUpdateContact(_ context.Context, req *mcp.CallToolRequest
 		}
 		contact.Tags = tags
 	}
+	if params.DoNotContact != nil {
+		contact.DoNotContact = *params.DoNotContact
+	}
 
 	contact.Touch()
 	if err := s.store.UpdateContact(contact); err != nil {
+		if errors.Is(err, storage.ErrVersionConflict) {
+			return versionConflictResult("contact", contact.Version, current)
+		}
 		return errResult(fmt.Sprintf("update contact: %v", err))
 	}
 	return jsonResult(contact)
@@ -417,6 +582,9 @@ func (s *Server) handleAddCompany(_ context.Context, req *mcp.CallToolRequest) (
 		Domain string         `json:"domain"`
 		Fields map[string]any `json:"fields"`
 		Tags   []string       `json:"tags"`
+		Source string         `json:"source"`
+
+		IdempotencyKey string `json:"idempotency_key"`
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
 		return errResult(fmt.Sprintf("invalid arguments: %v", err))
@@ -433,16 +601,26 @@ func (s *Server) handleAddCompany(_ context.Context, req *mcp.CallToolRequest) (
 	if params.Tags != nil {
 		company.Tags = params.Tags
 	}
+	company.Source = cmp.Or(params.Source, models.SourceMCP)
 
-	if err := s.store.CreateCompany(company); err != nil {
-		return errResult(fmt.Sprintf("create company: %v", err))
+	result, err := createIdempotent(s, storage.EntityCompany, params.IdempotencyKey, company.ID,
+		s.store.GetCompany, storage.ErrCompanyNotFound,
+		func() (*models.Company, error) {
+			if err := s.store.CreateCompany(company); err != nil {
+				return nil, fmt.Errorf("create company: %w", err)
+			}
+			return company, nil
+		})
+	if err != nil {
+		return errResult(err.Error())
 	}
-	return jsonResult(company)
+	return jsonResult(result)
 }
 
 func (s *Server) handleListCompanies(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params struct {
 		Tag    *string `json:"tag"`
+		Source string  `json:"source"`
 		Search string  `json:"search"`
 		Limit  int     `json:"limit"`
 	}
@@ -457,6 +635,7 @@ func (s *Server) handleListCompanies(_ context.Context, req *mcp.CallToolRequest
 
 	companies, err := s.store.ListCompanies(&storage.CompanyFilter{
 		Tag:    params.Tag,
+		Source: params.Source,
 		Search: params.Search,
 		Limit:  limit,
 	})
@@ -491,6 +670,8 @@ func (s *Server) handleUpdateCompany(_ context.Context, req *mcp.CallToolRequest
 		Domain *string         `json:"domain"`
 		Fields map[string]any  `json:"fields"`
 		Tags   json.RawMessage `json:"tags"`
+
+		Version *int `json:"version"`
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
 		return errResult(fmt.Sprintf("invalid arguments: %v", err))
@@ -503,6 +684,10 @@ func (s *Server) handleUpdateCompany(_ context.Context, req *mcp.CallToolRequest
 	if err != nil {
 		return errResult(fmt.Sprintf("get company: %v", err))
 	}
+	current := company.Version
+	if params.Version != nil {
+		company.Version = *params.Version
+	}
 
 	if params.Name != nil {
 		company.Name = *params.Name
@@ -525,6 +710,9 @@ func (s *Server) handleUpdateCompany(_ context.Context, req *mcp.CallToolRequest
 
 	company.Touch()
 	if err := s.store.UpdateCompany(company); err != nil {
+		if errors.Is(err, storage.ErrVersionConflict) {
+			return versionConflictResult("company", company.Version, current)
+		}
 		return errResult(fmt.Sprintf("update company: %v", err))
 	}
 	return jsonResult(company)
@@ -576,7 +764,7 @@ func (s *Server) handleLink(_ context.Context, req *mcp.CallToolRequest) (*mcp.C
 	}
 
 	rel := models.NewRelationship(sourceID, targetID, params.Type, params.Context)
-	if err := s.store.CreateRelationship(rel); err != nil {
+	if err := s.store.CreateOrUpdateRelationship(rel); err != nil {
 		return errResult(fmt.Sprintf("create relationship: %v", err))
 	}
 	return jsonResult(rel)
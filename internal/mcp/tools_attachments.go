@@ -0,0 +1,210 @@
+// ABOUTME: MCP tools for attaching files to contacts and companies.
+// ABOUTME: Accepts base64 payloads or server-side path references, lists metadata, and reads inline data back.
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func attachFileTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "attach_file",
+		Description: "Attach a file to a contact or company, either inline (base64) or by path reference",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"entity_id":    {"type": "string", "description": "Contact or company UUID or prefix"},
+				"filename":     {"type": "string", "description": "File name (defaults to the base name of path)"},
+				"content_type": {"type": "string", "description": "MIME type, e.g. application/pdf"},
+				"data":         {"type": "string", "description": "Base64-encoded file contents (max 1 MiB)"},
+				"path":         {"type": "string", "description": "Path to a regular file on the server, for larger files; stored as an absolute path"}
+			},
+			"required": ["entity_id"]
+		}`),
+	}
+}
+
+func listAttachmentsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_attachments",
+		Description: "List attachment metadata for a contact or company",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"entity_id": {"type": "string", "description": "Contact or company UUID or prefix"}
+			},
+			"required": ["entity_id"]
+		}`),
+	}
+}
+
+func getAttachmentTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_attachment",
+		Description: "Get an attachment's metadata and, for inline attachments, its base64 data. Path attachments return only their path; the file is not read",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "string", "description": "Attachment UUID"}
+			},
+			"required": ["id"]
+		}`),
+	}
+}
+
+// attachmentContent is the get_attachment result. Data is set for inline
+// attachments and Path for path references.
+type attachmentContent struct {
+	ID          string `json:"id"`
+	EntityType  string `json:"entity_type"`
+	EntityID    string `json:"entity_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+	Path        string `json:"path,omitempty"`
+	Data        string `json:"data,omitempty"`
+}
+
+// errAttachPath is returned for any unusable attach_file path. It is the
+// same whatever went wrong, so callers can't probe which server paths exist.
+const errAttachPath = "path must be a readable regular file"
+
+func (s *Server) handleAttachFile(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		EntityID    string `json:"entity_id"`
+		Filename    string `json:"filename"`
+		ContentType string `json:"content_type"`
+		Data        string `json:"data"`
+		Path        string `json:"path"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.EntityID == "" {
+		return errResult("entity_id is required")
+	}
+	if (params.Data == "") == (params.Path == "") {
+		return errResult("exactly one of data or path is required")
+	}
+
+	entityType, entityID, err := s.resolveEntity(params.EntityID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+
+	filename := params.Filename
+	if filename == "" && params.Path != "" {
+		filename = filepath.Base(params.Path)
+	}
+	if filename == "" {
+		return errResult("filename is required when attaching data")
+	}
+
+	attachment := models.NewAttachment(entityType, entityID, filename)
+	attachment.ContentType = params.ContentType
+	if params.Path != "" {
+		path, size, err := regularFile(params.Path)
+		if err != nil {
+			return errResult(errAttachPath)
+		}
+		attachment.Path = path
+		attachment.Size = size
+	} else {
+		data, err := base64.StdEncoding.DecodeString(params.Data)
+		if err != nil {
+			return errResult(fmt.Sprintf("invalid base64 data: %v", err))
+		}
+		attachment.Data = data
+	}
+
+	if err := s.store.AddAttachment(attachment); err != nil {
+		return errResult(fmt.Sprintf("add attachment: %v", err))
+	}
+	return jsonResult(attachment)
+}
+
+func (s *Server) handleListAttachments(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		EntityID string `json:"entity_id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.EntityID == "" {
+		return errResult("entity_id is required")
+	}
+
+	_, entityID, err := s.resolveEntity(params.EntityID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+
+	attachments, err := s.store.ListAttachments(entityID)
+	if err != nil {
+		return errResult(fmt.Sprintf("list attachments: %v", err))
+	}
+	return jsonResult(attachments)
+}
+
+func (s *Server) handleGetAttachment(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	id, err := uuid.Parse(params.ID)
+	if err != nil {
+		return errResult("id must be an attachment UUID")
+	}
+
+	a, err := s.store.GetAttachment(id)
+	if err != nil {
+		return errResult(fmt.Sprintf("get attachment: %v", err))
+	}
+	result := attachmentContent{
+		ID:          a.ID.String(),
+		EntityType:  a.EntityType,
+		EntityID:    a.EntityID.String(),
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Path:        a.Path,
+	}
+	if a.Path == "" {
+		result.Data = base64.StdEncoding.EncodeToString(a.Data)
+	}
+	return jsonResult(result)
+}
+
+// regularFile resolves path to an absolute path and returns it with the
+// file's size, failing unless it names a regular file the server can open.
+func regularFile(path string) (string, int64, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", 0, err
+	}
+	f, err := os.Open(abs) //nolint:gosec // attaching a server-side file by path is the point of path mode
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return "", 0, fmt.Errorf("%s is not a regular file", abs)
+	}
+	return abs, info.Size(), nil
+}
@@ -0,0 +1,206 @@
+// ABOUTME: Tests for the attachment MCP tools.
+// ABOUTME: Covers base64 and path uploads, listing by entity, reading back, and argument validation.
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+func TestServerAttachFile(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	company := models.NewCompany("Acme")
+	if err := store.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "attach_file",
+		Arguments: map[string]any{
+			"entity_id":    company.ID.String()[:8],
+			"filename":     "proposal.txt",
+			"content_type": "text/plain",
+			"data":         base64.StdEncoding.EncodeToString([]byte("draft proposal")),
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool attach_file: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("attach_file returned error: %s", contentText(result))
+	}
+
+	var attached struct {
+		EntityType string `json:"EntityType"`
+		Size       int64  `json:"Size"`
+		Data       []byte `json:"Data"`
+	}
+	if err := parseContent(result, &attached); err != nil {
+		t.Fatalf("parse attach_file: %v", err)
+	}
+	if attached.EntityType != "company" {
+		t.Errorf("EntityType = %q, want %q", attached.EntityType, "company")
+	}
+	if attached.Size != int64(len("draft proposal")) {
+		t.Errorf("Size = %d, want %d", attached.Size, len("draft proposal"))
+	}
+	if attached.Data != nil {
+		t.Error("expected attachment bytes to be omitted from the result")
+	}
+
+	list, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_attachments",
+		Arguments: map[string]any{"entity_id": company.ID.String()},
+	})
+	if err != nil || list.IsError {
+		t.Fatalf("list_attachments: err=%v text=%s", err, contentText(list))
+	}
+	var listed []map[string]any
+	if err := parseContent(list, &listed); err != nil {
+		t.Fatalf("parse list_attachments: %v", err)
+	}
+	if len(listed) != 1 {
+		t.Errorf("list_attachments len = %d, want 1", len(listed))
+	}
+}
+
+func TestServerAttachFileRequiresOnePayload(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+
+	contact := models.NewContact("Alice")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "attach_file",
+		Arguments: map[string]any{
+			"entity_id": contact.ID.String(),
+			"filename":  "empty.txt",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true when neither data nor path is given")
+	}
+}
+
+func TestServerAttachFilePath(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	contact := models.NewContact("Alice")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "resume.pdf"), []byte("pdf bytes"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	t.Chdir(dir)
+
+	attach := func(path string) *mcp.CallToolResult {
+		t.Helper()
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "attach_file",
+			Arguments: map[string]any{"entity_id": contact.ID.String(), "path": path},
+		})
+		if err != nil {
+			t.Fatalf("CallTool attach_file: %v", err)
+		}
+		return res
+	}
+
+	res := attach("resume.pdf")
+	if res.IsError {
+		t.Fatalf("attach_file returned error: %s", contentText(res))
+	}
+	var attached struct {
+		Path string
+		Size int64
+	}
+	if err := parseContent(res, &attached); err != nil {
+		t.Fatalf("parse attach_file: %v", err)
+	}
+	if want := filepath.Join(dir, "resume.pdf"); attached.Path != want {
+		t.Errorf("Path = %q, want absolute %q", attached.Path, want)
+	}
+	if attached.Size != int64(len("pdf bytes")) {
+		t.Errorf("Size = %d, want %d", attached.Size, len("pdf bytes"))
+	}
+
+	// A directory and a missing file get the same error, so path mode can't
+	// be used to probe the server's filesystem.
+	for _, path := range []string{dir, filepath.Join(dir, "missing.pdf")} {
+		res := attach(path)
+		if !res.IsError || contentText(res) != errAttachPath {
+			t.Errorf("attach %s = %q (isError %v), want %q", path, contentText(res), res.IsError, errAttachPath)
+		}
+	}
+}
+
+func TestServerGetAttachment(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	contact := models.NewContact("Alice")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	inline := models.NewAttachment(storage.EntityContact, contact.ID, "notes.txt")
+	inline.ContentType = "text/plain"
+	inline.Data = []byte("hello")
+	byPath := models.NewAttachment(storage.EntityContact, contact.ID, "big.bin")
+	byPath.Path = "/srv/files/big.bin"
+	for _, a := range []*models.Attachment{inline, byPath} {
+		if err := store.AddAttachment(a); err != nil {
+			t.Fatalf("AddAttachment: %v", err)
+		}
+	}
+
+	get := func(id string) attachmentContent {
+		t.Helper()
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "get_attachment",
+			Arguments: map[string]any{"id": id},
+		})
+		if err != nil || res.IsError {
+			t.Fatalf("get_attachment: err=%v text=%s", err, contentText(res))
+		}
+		var got attachmentContent
+		if err := parseContent(res, &got); err != nil {
+			t.Fatalf("parse get_attachment: %v", err)
+		}
+		return got
+	}
+
+	got := get(inline.ID.String())
+	data, err := base64.StdEncoding.DecodeString(got.Data)
+	if err != nil || string(data) != "hello" {
+		t.Errorf("inline data = %q (err %v), want hello", data, err)
+	}
+	if got.Filename != "notes.txt" || got.ContentType != "text/plain" || got.Path != "" {
+		t.Errorf("inline attachment = %+v", got)
+	}
+
+	got = get(byPath.ID.String())
+	if got.Path != "/srv/files/big.bin" || got.Data != "" {
+		t.Errorf("path attachment = %+v, want its path and no data", got)
+	}
+}
@@ -0,0 +1,95 @@
+// ABOUTME: MCP tools for creating many records in a single call.
+// ABOUTME: Reports per-item success or failure so one bad row does not sink the batch.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxBatchSize caps the number of items accepted by a batch tool call.
+const maxBatchSize = 100
+
+// batchItemResult reports the outcome of one item in a batch call.
+type batchItemResult struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// batchResult summarizes a batch call.
+type batchResult struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []batchItemResult `json:"results"`
+}
+
+func addContactsBatchTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "add_contacts_batch",
+		Description: "Add many contacts at once, reporting success or failure for each item",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"contacts": {
+					"type": "array",
+					"description": "Contacts to add (max 100), each shaped like add_contact's arguments",
+					"items": {
+						"type": "object",
+						"properties": {
+							"name":            {"type": "string", "description": "Contact name (required)"},
+							"email":           {"type": "string", "description": "Email address"},
+							"phone":           {"type": "string", "description": "Phone number"},
+							"fields":          {"type": "object", "description": "Additional key-value fields"},
+							"tags":            {"type": "array", "items": {"type": "string"}, "description": "Tags for categorization"},
+							"source":          {"type": "string", "description": "Where the record came from, e.g. csv (default mcp)"},
+							"idempotency_key": {"type": "string", "description": "Client-chosen key; retrying with the same key returns the originally created record"}
+						},
+						"required": ["name"]
+					}
+				}
+			},
+			"required": ["contacts"]
+		}`),
+	}
+}
+
+func (s *Server) handleAddContactsBatch(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		Contacts []json.RawMessage `json:"contacts"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if len(params.Contacts) == 0 {
+		return errResult("contacts is required")
+	}
+	if len(params.Contacts) > maxBatchSize {
+		return errResult(fmt.Sprintf("too many contacts: %d (max %d)", len(params.Contacts), maxBatchSize))
+	}
+
+	result := batchResult{Results: make([]batchItemResult, 0, len(params.Contacts))}
+	for i, raw := range params.Contacts {
+		item := batchItemResult{Index: i}
+
+		var p addContactParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			item.Error = fmt.Sprintf("invalid contact: %v", err)
+		} else if contact, err := s.addContact(p); err != nil {
+			item.Error = err.Error()
+		} else {
+			item.ID = contact.ID.String()
+		}
+
+		if item.Error != "" {
+			result.Failed++
+		} else {
+			result.Created++
+		}
+		result.Results = append(result.Results, item)
+	}
+	return jsonResult(result)
+}
@@ -0,0 +1,80 @@
+// ABOUTME: Tests for the batch creation MCP tools.
+// ABOUTME: Verifies per-item results and that bad items do not fail the whole batch.
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAddContactsBatch(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "add_contacts_batch",
+		Arguments: map[string]any{
+			"contacts": []map[string]any{
+				{"name": "Alice", "email": "alice@example.com"},
+				{"email": "nameless@example.com"},
+				{"name": "Bob", "tags": []string{"vip"}},
+			},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("add_contacts_batch: err=%v text=%s", err, contentText(result))
+	}
+
+	var got batchResult
+	if err := parseContent(result, &got); err != nil {
+		t.Fatalf("parse batch result: %v", err)
+	}
+	if got.Created != 2 || got.Failed != 1 {
+		t.Errorf("created=%d failed=%d, want 2 and 1", got.Created, got.Failed)
+	}
+	if len(got.Results) != 3 {
+		t.Fatalf("results len = %d, want 3", len(got.Results))
+	}
+	if got.Results[0].ID == "" || got.Results[2].ID == "" {
+		t.Errorf("expected IDs for valid items, got %+v", got.Results)
+	}
+	if got.Results[1].Error == "" || got.Results[1].ID != "" {
+		t.Errorf("expected error for nameless item, got %+v", got.Results[1])
+	}
+
+	contacts, err := store.ListContacts(nil)
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Errorf("stored contacts = %d, want 2", len(contacts))
+	}
+}
+
+func TestAddContactsBatchLimits(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+
+	tooMany := make([]map[string]any, maxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"name": "Someone"}
+	}
+
+	for name, contacts := range map[string][]map[string]any{
+		"empty":    {},
+		"too many": tooMany,
+	} {
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "add_contacts_batch",
+			Arguments: map[string]any{"contacts": contacts},
+		})
+		if err != nil {
+			t.Fatalf("%s: CallTool: %v", name, err)
+		}
+		if !result.IsError {
+			t.Errorf("%s: expected error result", name)
+		}
+	}
+}
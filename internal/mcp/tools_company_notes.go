@@ -0,0 +1,122 @@
+// ABOUTME: MCP tools for the running notes history kept on companies.
+// ABOUTME: Adds, lists, and deletes timestamped company notes.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func addCompanyNoteTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "add_company_note",
+		Description: "Append a timestamped note to a company's notes history",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"company_id": {"type": "string", "description": "Company UUID or prefix"},
+				"content":    {"type": "string", "description": "Note text"}
+			},
+			"required": ["company_id", "content"]
+		}`),
+	}
+}
+
+func listCompanyNotesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_company_notes",
+		Description: "List a company's notes, oldest first",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"company_id": {"type": "string", "description": "Company UUID or prefix"}
+			},
+			"required": ["company_id"]
+		}`),
+	}
+}
+
+func deleteCompanyNoteTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "delete_company_note",
+		Description: "Delete a company note by ID",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "string", "description": "Note UUID"}
+			},
+			"required": ["id"]
+		}`),
+	}
+}
+
+func (s *Server) handleAddCompanyNote(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		CompanyID string `json:"company_id"`
+		Content   string `json:"content"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.CompanyID == "" {
+		return errResult("company_id is required")
+	}
+
+	company, err := s.resolveCompany(params.CompanyID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+
+	note := models.NewCompanyNote(company.ID, params.Content)
+	if err := s.store.AddCompanyNote(note); err != nil {
+		return errResult(fmt.Sprintf("add company note: %v", err))
+	}
+	return jsonResult(note)
+}
+
+func (s *Server) handleListCompanyNotes(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		CompanyID string `json:"company_id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.CompanyID == "" {
+		return errResult("company_id is required")
+	}
+
+	company, err := s.resolveCompany(params.CompanyID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+
+	notes, err := s.store.ListCompanyNotes(company.ID)
+	if err != nil {
+		return errResult(fmt.Sprintf("list company notes: %v", err))
+	}
+	return jsonResult(notes)
+}
+
+func (s *Server) handleDeleteCompanyNote(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	id, err := uuid.Parse(params.ID)
+	if err != nil {
+		return errResult("id must be a note UUID")
+	}
+
+	if err := s.store.DeleteCompanyNote(id); err != nil {
+		return errResult(fmt.Sprintf("delete company note: %v", err))
+	}
+	return textResult(fmt.Sprintf("deleted company note %s", id))
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Tests for the company notes MCP tools.
+// ABOUTME: Covers adding by ID prefix, listing, deleting, and rejecting blank notes.
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestServerCompanyNotes(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	company := models.NewCompany("Acme")
+	if err := store.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	add, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "add_company_note",
+		Arguments: map[string]any{
+			"company_id": company.ID.String()[:8],
+			"content":    "Budget approved for Q3",
+		},
+	})
+	if err != nil || add.IsError {
+		t.Fatalf("add_company_note: err=%v text=%s", err, contentText(add))
+	}
+	var added struct {
+		ID string `json:"ID"`
+	}
+	if err := parseContent(add, &added); err != nil {
+		t.Fatalf("parse add_company_note: %v", err)
+	}
+
+	list, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_company_notes",
+		Arguments: map[string]any{"company_id": company.ID.String()},
+	})
+	if err != nil || list.IsError {
+		t.Fatalf("list_company_notes: err=%v text=%s", err, contentText(list))
+	}
+	var notes []struct {
+		Content string `json:"Content"`
+	}
+	if err := parseContent(list, &notes); err != nil {
+		t.Fatalf("parse list_company_notes: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Content != "Budget approved for Q3" {
+		t.Errorf("list_company_notes = %+v", notes)
+	}
+
+	del, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "delete_company_note",
+		Arguments: map[string]any{"id": added.ID},
+	})
+	if err != nil || del.IsError {
+		t.Fatalf("delete_company_note: err=%v text=%s", err, contentText(del))
+	}
+}
+
+func TestServerAddCompanyNoteRejectsBlank(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+
+	company := models.NewCompany("Acme")
+	if err := store.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "add_company_note",
+		Arguments: map[string]any{"company_id": company.ID.String(), "content": " "},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected an error for a blank note")
+	}
+}
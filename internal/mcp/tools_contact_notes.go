@@ -0,0 +1,122 @@
+// ABOUTME: MCP tools for the running notes history kept on contacts.
+// ABOUTME: Adds, lists, and deletes timestamped contact notes.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func addContactNoteTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "add_contact_note",
+		Description: "Append a timestamped note to a contact's notes history",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"contact_id": {"type": "string", "description": "Contact UUID or prefix"},
+				"content":    {"type": "string", "description": "Note text"}
+			},
+			"required": ["contact_id", "content"]
+		}`),
+	}
+}
+
+func listContactNotesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_contact_notes",
+		Description: "List a contact's notes, oldest first",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"contact_id": {"type": "string", "description": "Contact UUID or prefix"}
+			},
+			"required": ["contact_id"]
+		}`),
+	}
+}
+
+func deleteContactNoteTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "delete_contact_note",
+		Description: "Delete a contact note by ID",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "string", "description": "Note UUID"}
+			},
+			"required": ["id"]
+		}`),
+	}
+}
+
+func (s *Server) handleAddContactNote(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		ContactID string `json:"contact_id"`
+		Content   string `json:"content"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.ContactID == "" {
+		return errResult("contact_id is required")
+	}
+
+	contact, err := s.resolveContact(params.ContactID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+
+	note := models.NewContactNote(contact.ID, params.Content)
+	if err := s.store.AddContactNote(note); err != nil {
+		return errResult(fmt.Sprintf("add contact note: %v", err))
+	}
+	return jsonResult(note)
+}
+
+func (s *Server) handleListContactNotes(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		ContactID string `json:"contact_id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.ContactID == "" {
+		return errResult("contact_id is required")
+	}
+
+	contact, err := s.resolveContact(params.ContactID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+
+	notes, err := s.store.ListContactNotes(contact.ID)
+	if err != nil {
+		return errResult(fmt.Sprintf("list contact notes: %v", err))
+	}
+	return jsonResult(notes)
+}
+
+func (s *Server) handleDeleteContactNote(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	id, err := uuid.Parse(params.ID)
+	if err != nil {
+		return errResult("id must be a note UUID")
+	}
+
+	if err := s.store.DeleteContactNote(id); err != nil {
+		return errResult(fmt.Sprintf("delete contact note: %v", err))
+	}
+	return textResult(fmt.Sprintf("deleted contact note %s", id))
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Tests for the contact notes MCP tools.
+// ABOUTME: Covers adding by ID prefix, listing, deleting, and rejecting blank notes.
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestServerContactNotes(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	contact := models.NewContact("Alice")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	add, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "add_contact_note",
+		Arguments: map[string]any{
+			"contact_id": contact.ID.String()[:8],
+			"content":    "Prefers morning calls",
+		},
+	})
+	if err != nil || add.IsError {
+		t.Fatalf("add_contact_note: err=%v text=%s", err, contentText(add))
+	}
+	var added struct {
+		ID string `json:"ID"`
+	}
+	if err := parseContent(add, &added); err != nil {
+		t.Fatalf("parse add_contact_note: %v", err)
+	}
+
+	list, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_contact_notes",
+		Arguments: map[string]any{"contact_id": contact.ID.String()},
+	})
+	if err != nil || list.IsError {
+		t.Fatalf("list_contact_notes: err=%v text=%s", err, contentText(list))
+	}
+	var notes []struct {
+		Content string `json:"Content"`
+	}
+	if err := parseContent(list, &notes); err != nil {
+		t.Fatalf("parse list_contact_notes: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Content != "Prefers morning calls" {
+		t.Errorf("list_contact_notes = %+v", notes)
+	}
+
+	del, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "delete_contact_note",
+		Arguments: map[string]any{"id": added.ID},
+	})
+	if err != nil || del.IsError {
+		t.Fatalf("delete_contact_note: err=%v text=%s", err, contentText(del))
+	}
+}
+
+func TestServerAddContactNoteRejectsBlank(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+
+	contact := models.NewContact("Alice")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "add_contact_note",
+		Arguments: map[string]any{"contact_id": contact.ID.String(), "content": " "},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected an error for a blank note")
+	}
+}
@@ -0,0 +1,92 @@
+// ABOUTME: MCP tools that find contacts by email domain and link them to the matching company.
+// ABOUTME: Surfaces people who belong to an account but were never associated with it.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+func listContactsByDomainTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_contacts_by_domain",
+		Description: "List contacts whose email address is at a domain, whether or not they are linked to a company",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"domain": {"type": "string", "description": "Email domain, e.g. acme.com"}
+			},
+			"required": ["domain"]
+		}`),
+	}
+}
+
+func linkDomainContactsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "link_domain_contacts",
+		Description: "Link every contact whose email is at a company's domain to that company as works_at, skipping contacts already related to it",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"company_id": {"type": "string", "description": "Company UUID or prefix"}
+			},
+			"required": ["company_id"]
+		}`),
+	}
+}
+
+func (s *Server) handleListContactsByDomain(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		Domain string `json:"domain"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if storage.NormalizeDomain(params.Domain) == "" {
+		return errResult("domain is required")
+	}
+
+	contacts, err := s.store.ListContactsByEmailDomain(params.Domain)
+	if err != nil {
+		return errResult(fmt.Sprintf("list contacts by domain: %v", err))
+	}
+	if contacts == nil {
+		contacts = []*models.Contact{}
+	}
+	return jsonResult(contacts)
+}
+
+func (s *Server) handleLinkDomainContacts(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		CompanyID string `json:"company_id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.CompanyID == "" {
+		return errResult("company_id is required")
+	}
+
+	company, err := s.resolveCompany(params.CompanyID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+
+	linked, err := storage.LinkDomainContacts(s.store, company.ID)
+	if err != nil {
+		return errResult(fmt.Sprintf("link domain contacts: %v", err))
+	}
+	if linked == nil {
+		linked = []*models.Contact{}
+	}
+	return jsonResult(map[string]any{
+		"company_id": company.ID,
+		"linked":     linked,
+	})
+}
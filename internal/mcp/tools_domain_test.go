@@ -0,0 +1,74 @@
+// ABOUTME: Tests for the list_contacts_by_domain and link_domain_contacts MCP tools.
+// ABOUTME: Covers domain matching, bulk linking to a company, and missing-domain errors.
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestDomainContactTools(t *testing.T) {
+	store := newTestStore(t)
+	acme := models.NewCompany("Acme")
+	acme.Domain = "acme.com"
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	jane := models.NewContact("Jane")
+	jane.Email = "jane@acme.com"
+	if err := store.CreateContact(jane); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_contacts_by_domain",
+		Arguments: map[string]any{"domain": "ACME.com"},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("list_contacts_by_domain: err=%v text=%s", err, contentText(result))
+	}
+	var contacts []*models.Contact
+	if err := parseContent(result, &contacts); err != nil {
+		t.Fatalf("parse contacts: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].ID != jane.ID {
+		t.Errorf("contacts = %v, want Jane", contacts)
+	}
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "link_domain_contacts",
+		Arguments: map[string]any{"company_id": acme.ID.String()},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("link_domain_contacts: err=%v text=%s", err, contentText(result))
+	}
+	var linked struct {
+		CompanyID uuid.UUID         `json:"company_id"`
+		Linked    []*models.Contact `json:"linked"`
+	}
+	if err := parseContent(result, &linked); err != nil {
+		t.Fatalf("parse linked: %v", err)
+	}
+	if linked.CompanyID != acme.ID || len(linked.Linked) != 1 || linked.Linked[0].ID != jane.ID {
+		t.Errorf("linked = %+v, want Jane linked to Acme", linked)
+	}
+
+	bare := models.NewCompany("Bare")
+	if err := store.CreateCompany(bare); err != nil {
+		t.Fatalf("CreateCompany bare: %v", err)
+	}
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "link_domain_contacts",
+		Arguments: map[string]any{"company_id": bare.ID.String()},
+	})
+	if err != nil || !result.IsError {
+		t.Errorf("link_domain_contacts without domain: err=%v, want tool error", err)
+	}
+}
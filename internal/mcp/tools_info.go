@@ -0,0 +1,49 @@
+// ABOUTME: MCP server_info tool for connectivity checks and diagnostics.
+// ABOUTME: Reports the app and schema versions, storage backend and location, and per-entity record counts.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/storage"
+)
+
+func serverInfoTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "server_info",
+		Description: "Report server and schema versions, storage location, and record counts (read-only health check)",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {}}`),
+	}
+}
+
+func (s *Server) handleServerInfo(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stats, err := s.store.Stats()
+	if err != nil {
+		return errResult(fmt.Sprintf("stats: %v", err))
+	}
+	version, hasVersion, err := s.store.SchemaVersion()
+	if err != nil {
+		return errResult(fmt.Sprintf("schema version: %v", err))
+	}
+
+	info := struct {
+		Version       string         `json:"version"`
+		SchemaVersion *int           `json:"schema_version"` // null for backends without one
+		Backend       string         `json:"backend,omitempty"`
+		StoragePath   string         `json:"storage_path,omitempty"`
+		Counts        *storage.Stats `json:"counts"`
+	}{
+		Version:     s.version,
+		Backend:     s.backend,
+		StoragePath: s.storagePath,
+		Counts:      stats,
+	}
+	if hasVersion {
+		info.SchemaVersion = &version
+	}
+	return jsonResult(info)
+}
@@ -0,0 +1,83 @@
+// ABOUTME: Tests for the server_info MCP tool.
+// ABOUTME: Verifies app and schema versions, storage location, and record counts are reported.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+func TestServerInfo(t *testing.T) {
+	store := newTestStore(t)
+	if err := store.CreateContact(models.NewContact("Alice")); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	session := connectTestServer(t, store, WithVersion("9.9.9"), WithStorageLocation("sqlite", "/data/crm.db"))
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "server_info",
+		Arguments: map[string]any{},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("server_info: err=%v text=%s", err, contentText(result))
+	}
+
+	var info struct {
+		Version       string `json:"version"`
+		SchemaVersion *int   `json:"schema_version"`
+		Backend       string `json:"backend"`
+		StoragePath   string `json:"storage_path"`
+		Counts        struct {
+			Contacts int `json:"contacts"`
+		} `json:"counts"`
+	}
+	if err := parseContent(result, &info); err != nil {
+		t.Fatalf("parse server_info: %v", err)
+	}
+	if info.Version != "9.9.9" {
+		t.Errorf("version = %q, want %q", info.Version, "9.9.9")
+	}
+	want, _, err := store.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+	if info.SchemaVersion == nil || *info.SchemaVersion != want || want < 1 {
+		t.Errorf("schema_version = %v, want %d", info.SchemaVersion, want)
+	}
+	if info.Backend != "sqlite" || info.StoragePath != "/data/crm.db" {
+		t.Errorf("storage = %s %s, want sqlite /data/crm.db", info.Backend, info.StoragePath)
+	}
+	if info.Counts.Contacts != 1 {
+		t.Errorf("counts.contacts = %d, want 1", info.Counts.Contacts)
+	}
+}
+
+func TestServerInfoMarkdownSchemaVersion(t *testing.T) {
+	store, err := storage.NewMarkdownStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewMarkdownStore: %v", err)
+	}
+	session := connectTestServer(t, store)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "server_info",
+		Arguments: map[string]any{},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("server_info: err=%v text=%s", err, contentText(result))
+	}
+	var keys map[string]json.RawMessage
+	if err := parseContent(result, &keys); err != nil {
+		t.Fatalf("parse server_info: %v", err)
+	}
+	if v, ok := keys["schema_version"]; !ok || string(v) != "null" {
+		t.Errorf("schema_version = %s (present %v), want null", v, ok)
+	}
+}
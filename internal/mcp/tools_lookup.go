@@ -0,0 +1,111 @@
+// ABOUTME: MCP tool that resolves an email address to an existing contact and company.
+// ABOUTME: Used before creating records from an incoming email to avoid duplicates.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/crm/internal/storage"
+)
+
+// freemailDomains are consumer email providers that never identify a company.
+var freemailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "hotmail.com": true,
+	"outlook.com": true, "live.com": true, "icloud.com": true, "me.com": true,
+	"aol.com": true, "proton.me": true, "protonmail.com": true, "fastmail.com": true,
+}
+
+func resolveEmailTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "resolve_email",
+		Description: "Find the contact with an email address and the company its domain maps to, suggesting what to create if either is missing",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"email": {"type": "string", "description": "Email address to resolve"}
+			},
+			"required": ["email"]
+		}`),
+	}
+}
+
+// emailResolution is the result of resolve_email.
+type emailResolution struct {
+	Email       string          `json:"email"`
+	Domain      string          `json:"domain"`
+	Contact     *models.Contact `json:"contact"`
+	Company     *models.Company `json:"company"`
+	Suggestions []string        `json:"suggestions"`
+}
+
+func (s *Server) handleResolveEmail(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	email := storage.NormalizeEmail(params.Email)
+	domain := storage.EmailDomain(email)
+	if domain == "" {
+		return errResult("email must be an address like name@example.com")
+	}
+
+	res := emailResolution{Email: email, Domain: domain, Suggestions: []string{}}
+
+	contact, err := s.store.GetContactByEmail(email)
+	switch {
+	case err == nil:
+		res.Contact = contact
+	case !errors.Is(err, storage.ErrContactNotFound):
+		return errResult(fmt.Sprintf("look up contact: %v", err))
+	}
+
+	if !freemailDomains[domain] {
+		company, err := s.companyForDomain(domain)
+		switch {
+		case err == nil:
+			res.Company = company
+		case !errors.Is(err, storage.ErrCompanyNotFound):
+			return errResult(fmt.Sprintf("look up company: %v", err))
+		}
+	}
+
+	if res.Contact == nil {
+		res.Suggestions = append(res.Suggestions, fmt.Sprintf("No contact has this email; create one with add_contact(email: %q).", email))
+	}
+	switch {
+	case freemailDomains[domain]:
+		res.Suggestions = append(res.Suggestions, fmt.Sprintf("%s is a personal email provider, so no company is inferred.", domain))
+	case res.Company == nil:
+		res.Suggestions = append(res.Suggestions, fmt.Sprintf("No company uses %s; create one with add_company(domain: %q).", domain, domain))
+	case res.Contact != nil:
+		// Both exist; nothing to suggest.
+	default:
+		res.Suggestions = append(res.Suggestions, fmt.Sprintf("After creating the contact, link it to %s with type \"works_at\".", res.Company.Name))
+	}
+	return jsonResult(res)
+}
+
+// companyForDomain finds the company for domain, falling back to parent
+// domains so "eng.acme.com" resolves to a company registered as "acme.com".
+// Returns ErrCompanyNotFound when no domain in the chain matches.
+func (s *Server) companyForDomain(domain string) (*models.Company, error) {
+	for d := domain; strings.Contains(d, "."); d = d[strings.Index(d, ".")+1:] {
+		company, err := s.store.GetCompanyByDomain(d)
+		if err == nil {
+			return company, nil
+		}
+		if !errors.Is(err, storage.ErrCompanyNotFound) {
+			return nil, err
+		}
+	}
+	return nil, storage.ErrCompanyNotFound
+}
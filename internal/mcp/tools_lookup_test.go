@@ -0,0 +1,86 @@
+// ABOUTME: Tests for the resolve_email MCP tool.
+// ABOUTME: Covers matched contacts, parent-domain company matches, and freemail handling.
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func callResolveEmail(t *testing.T, session *mcp.ClientSession, email string) emailResolution {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "resolve_email",
+		Arguments: map[string]any{"email": email},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("resolve_email(%s): err=%v text=%s", email, err, contentText(result))
+	}
+	var res emailResolution
+	if err := parseContent(result, &res); err != nil {
+		t.Fatalf("parse resolve_email: %v", err)
+	}
+	return res
+}
+
+func TestResolveEmail(t *testing.T) {
+	store := newTestStore(t)
+	jane := models.NewContact("Jane")
+	jane.Email = "jane@acme.com"
+	if err := store.CreateContact(jane); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	acme := models.NewCompany("Acme")
+	acme.Domain = "acme.com"
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	session := connectTestServer(t, store)
+
+	res := callResolveEmail(t, session, "Jane@Acme.com")
+	if res.Contact == nil || res.Contact.ID != jane.ID {
+		t.Errorf("contact = %v, want Jane", res.Contact)
+	}
+	if res.Company == nil || res.Company.ID != acme.ID {
+		t.Errorf("company = %v, want Acme", res.Company)
+	}
+	if len(res.Suggestions) != 0 {
+		t.Errorf("suggestions = %v, want none", res.Suggestions)
+	}
+
+	res = callResolveEmail(t, session, "bob@eng.acme.com")
+	if res.Contact != nil {
+		t.Errorf("contact = %v, want nil", res.Contact)
+	}
+	if res.Company == nil || res.Company.ID != acme.ID {
+		t.Errorf("parent-domain company = %v, want Acme", res.Company)
+	}
+	if len(res.Suggestions) != 2 {
+		t.Errorf("suggestions = %v, want create-contact and link hints", res.Suggestions)
+	}
+
+	res = callResolveEmail(t, session, "someone@gmail.com")
+	if res.Company != nil {
+		t.Errorf("freemail company = %v, want nil", res.Company)
+	}
+}
+
+func TestResolveEmailInvalid(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "resolve_email",
+		Arguments: map[string]any{"email": "not-an-email"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error for address without a domain")
+	}
+}
@@ -0,0 +1,108 @@
+// ABOUTME: MCP tools for setting and fetching contact photos.
+// ABOUTME: Photos travel as base64 and are kept out of contact records and listings.
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func setContactPhotoTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "set_contact_photo",
+		Description: "Set or remove a contact's photo",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"contact_id":   {"type": "string", "description": "Contact UUID or prefix"},
+				"content_type": {"type": "string", "description": "Image MIME type, e.g. image/jpeg"},
+				"data":         {"type": "string", "description": "Base64-encoded image (max 256 KiB); empty removes the photo"}
+			},
+			"required": ["contact_id"]
+		}`),
+	}
+}
+
+func getContactPhotoTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_contact_photo",
+		Description: "Get a contact's photo as base64",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"contact_id": {"type": "string", "description": "Contact UUID or prefix"}
+			},
+			"required": ["contact_id"]
+		}`),
+	}
+}
+
+// contactPhoto is the get_contact_photo result.
+type contactPhoto struct {
+	ContactID   string `json:"contact_id"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	Data        string `json:"data"`
+}
+
+func (s *Server) handleSetContactPhoto(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		ContactID   string `json:"contact_id"`
+		ContentType string `json:"content_type"`
+		Data        string `json:"data"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.ContactID == "" {
+		return errResult("contact_id is required")
+	}
+
+	contact, err := s.resolveContact(params.ContactID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+	data, err := base64.StdEncoding.DecodeString(params.Data)
+	if err != nil {
+		return errResult(fmt.Sprintf("invalid base64 data: %v", err))
+	}
+
+	if err := s.store.SetContactPhoto(contact.ID, params.ContentType, data); err != nil {
+		return errResult(fmt.Sprintf("set contact photo: %v", err))
+	}
+	if len(data) == 0 {
+		return textResult(fmt.Sprintf("Removed photo for %s", contact.Name))
+	}
+	return textResult(fmt.Sprintf("Set photo for %s (%d bytes)", contact.Name, len(data)))
+}
+
+func (s *Server) handleGetContactPhoto(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		ContactID string `json:"contact_id"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.ContactID == "" {
+		return errResult("contact_id is required")
+	}
+
+	contact, err := s.resolveContact(params.ContactID)
+	if err != nil {
+		return errResult(err.Error())
+	}
+	data, contentType, err := s.store.GetContactPhoto(contact.ID)
+	if err != nil {
+		return errResult(fmt.Sprintf("get contact photo: %v", err))
+	}
+	return jsonResult(contactPhoto{
+		ContactID:   contact.ID.String(),
+		ContentType: contentType,
+		Size:        len(data),
+		Data:        base64.StdEncoding.EncodeToString(data),
+	})
+}
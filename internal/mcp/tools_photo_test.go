@@ -0,0 +1,99 @@
+// ABOUTME: Tests for the contact photo MCP tools.
+// ABOUTME: Covers a base64 round trip, removal, and rejecting non-image content types.
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestServerContactPhoto(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+	ctx := context.Background()
+
+	contact := models.NewContact("Alice")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("gif-bytes"))
+	set, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "set_contact_photo",
+		Arguments: map[string]any{
+			"contact_id":   contact.ID.String()[:8],
+			"content_type": "image/gif",
+			"data":         encoded,
+		},
+	})
+	if err != nil || set.IsError {
+		t.Fatalf("set_contact_photo: err=%v text=%s", err, contentText(set))
+	}
+
+	get, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_contact_photo",
+		Arguments: map[string]any{"contact_id": contact.ID.String()},
+	})
+	if err != nil || get.IsError {
+		t.Fatalf("get_contact_photo: err=%v text=%s", err, contentText(get))
+	}
+	var photo struct {
+		ContentType string `json:"content_type"`
+		Size        int    `json:"size"`
+		Data        string `json:"data"`
+	}
+	if err := parseContent(get, &photo); err != nil {
+		t.Fatalf("parse get_contact_photo: %v", err)
+	}
+	if photo.ContentType != "image/gif" || photo.Size != len("gif-bytes") || photo.Data != encoded {
+		t.Errorf("get_contact_photo = %+v", photo)
+	}
+
+	cleared, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "set_contact_photo",
+		Arguments: map[string]any{"contact_id": contact.ID.String()},
+	})
+	if err != nil || cleared.IsError {
+		t.Fatalf("clear photo: err=%v text=%s", err, contentText(cleared))
+	}
+	gone, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_contact_photo",
+		Arguments: map[string]any{"contact_id": contact.ID.String()},
+	})
+	if err != nil {
+		t.Fatalf("get_contact_photo after clear: %v", err)
+	}
+	if !gone.IsError {
+		t.Error("expected an error for a contact without a photo")
+	}
+}
+
+func TestServerSetContactPhotoRejectsNonImage(t *testing.T) {
+	store := newTestStore(t)
+	session := connectTestServer(t, store)
+
+	contact := models.NewContact("Alice")
+	if err := store.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "set_contact_photo",
+		Arguments: map[string]any{
+			"contact_id":   contact.ID.String(),
+			"content_type": "text/plain",
+			"data":         base64.StdEncoding.EncodeToString([]byte("hi")),
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected an error for a non-image content type")
+	}
+}
@@ -0,0 +1,39 @@
+// ABOUTME: MCP tool that proposes relationships inferred from existing data.
+// ABOUTME: Suggests "colleague" links between contacts at the same company.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func suggestColleaguesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "suggest_colleagues",
+		Description: "Suggest colleague relationships between contacts linked to the same company who are not yet related. Nothing is created; use link to accept a suggestion.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"limit": {"type": "integer", "description": "Max suggestions to return (default 50)"}
+			}
+		}`),
+	}
+}
+
+func (s *Server) handleSuggestColleagues(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		Limit int `json:"limit"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errResult(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	suggestions, err := s.store.SuggestColleagueRelationships(params.Limit)
+	if err != nil {
+		return errResult(fmt.Sprintf("suggest colleagues: %v", err))
+	}
+	return jsonResult(suggestions)
+}
@@ -0,0 +1,67 @@
+// ABOUTME: Tests for the suggest_colleagues MCP tool.
+// ABOUTME: Verifies suggestions are returned for contacts sharing a company.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestSuggestColleagues(t *testing.T) {
+	store := newTestStore(t)
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	acme := models.NewCompany("Acme")
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateRelationship(models.NewRelationship(c.ID, acme.ID, "works_at", "")); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+	session := connectTestServer(t, store)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "suggest_colleagues",
+		Arguments: map[string]any{},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("suggest_colleagues: err=%v text=%s", err, contentText(result))
+	}
+
+	var keys []map[string]json.RawMessage
+	if err := parseContent(result, &keys); err != nil || len(keys) != 1 {
+		t.Fatalf("parse suggestion keys: %v (%d suggestions)", err, len(keys))
+	}
+	for _, k := range []string{"company_id", "company_name", "source_id", "source_name", "target_id", "target_name"} {
+		if _, ok := keys[0][k]; !ok {
+			t.Errorf("suggestion has no %q key: %s", k, contentText(result))
+		}
+	}
+	if len(keys[0]) != 6 {
+		t.Errorf("suggestion has %d keys, want 6: %s", len(keys[0]), contentText(result))
+	}
+
+	var got []struct {
+		CompanyName string `json:"company_name"`
+		SourceName  string `json:"source_name"`
+		TargetName  string `json:"target_name"`
+	}
+	if err := parseContent(result, &got); err != nil {
+		t.Fatalf("parse suggestions: %v", err)
+	}
+	if len(got) != 1 || got[0].CompanyName != "Acme" || got[0].SourceName != "Alice" || got[0].TargetName != "Bob" {
+		t.Errorf("suggestions = %+v, want Alice-Bob at Acme", got)
+	}
+}
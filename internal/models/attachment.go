@@ -0,0 +1,36 @@
+// ABOUTME: Attachment model representing a file associated with a CRM entity.
+// ABOUTME: Stores either a filesystem path reference or an inline blob.
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a document (proposal, contract, etc.) linked to a contact or company.
+// Exactly one of Path or Data is set: Path references a file on disk, Data holds
+// the file contents inline.
+type Attachment struct {
+	ID          uuid.UUID
+	EntityType  string // "contact" or "company"
+	EntityID    uuid.UUID
+	Filename    string
+	ContentType string
+	Size        int64
+	Path        string
+	Data        []byte `json:"-"`
+	CreatedAt   time.Time
+}
+
+// NewAttachment creates an Attachment for the given entity, generating a UUID
+// and setting CreatedAt.
+func NewAttachment(entityType string, entityID uuid.UUID, filename string) *Attachment {
+	return &Attachment{
+		ID:         uuid.New(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Filename:   filename,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
@@ -0,0 +1,36 @@
+// ABOUTME: Tests for the Attachment model struct and its constructor.
+// ABOUTME: Verifies ID generation, field assignment, and timestamp initialization.
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewAttachment(t *testing.T) {
+	entityID := uuid.New()
+	a := NewAttachment("contact", entityID, "proposal.pdf")
+
+	if a == nil {
+		t.Fatal("NewAttachment returned nil")
+	}
+	if a.ID == uuid.Nil {
+		t.Error("expected non-nil UUID, got uuid.Nil")
+	}
+	if a.EntityType != "contact" {
+		t.Errorf("expected EntityType %q, got %q", "contact", a.EntityType)
+	}
+	if a.EntityID != entityID {
+		t.Errorf("expected EntityID %v, got %v", entityID, a.EntityID)
+	}
+	if a.Filename != "proposal.pdf" {
+		t.Errorf("expected Filename %q, got %q", "proposal.pdf", a.Filename)
+	}
+	if a.Path != "" || a.Data != nil {
+		t.Error("expected empty Path and nil Data on creation")
+	}
+	if a.CreatedAt.IsZero() {
+		t.Error("expected CreatedAt to be set, got zero time")
+	}
+}
@@ -17,12 +17,21 @@ type Company struct {
 	Tags      []string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Source records where the company came from, e.g. "manual" or "csv".
+	// Empty means unknown.
+	Source string
+
+	// Version counts saved revisions, starting at 1. Updates must carry the
+	// version they read; a stale version is rejected instead of overwriting
+	// someone else's change.
+	Version int
 }
 
 // NewCompany creates a Company with the given name, generating a UUID
-// and initializing Fields, Tags, and timestamps.
+// and initializing Fields, Tags, timestamps, and Version.
 func NewCompany(name string) *Company {
-	now := time.Now()
+	now := time.Now().UTC()
 	return &Company{
 		ID:        uuid.New(),
 		Name:      name,
@@ -30,10 +39,11 @@ func NewCompany(name string) *Company {
 		Tags:      []string{},
 		CreatedAt: now,
 		UpdatedAt: now,
+		Version:   1,
 	}
 }
 
 // Touch updates the UpdatedAt timestamp to the current time.
 func (c *Company) Touch() {
-	c.UpdatedAt = time.Now()
+	c.UpdatedAt = time.Now().UTC()
 }
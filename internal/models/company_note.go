@@ -0,0 +1,28 @@
+// ABOUTME: CompanyNote model for the running log of notes kept on a company.
+// ABOUTME: Notes are append-only entries; each is added or deleted as a whole.
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompanyNote is one timestamped entry in a company's notes history.
+type CompanyNote struct {
+	ID        uuid.UUID
+	CompanyID uuid.UUID
+	Content   string
+	CreatedAt time.Time
+}
+
+// NewCompanyNote creates a CompanyNote for the given company, generating a
+// UUID and setting CreatedAt.
+func NewCompanyNote(companyID uuid.UUID, content string) *CompanyNote {
+	return &CompanyNote{
+		ID:        uuid.New(),
+		CompanyID: companyID,
+		Content:   content,
+		CreatedAt: time.Now().UTC(),
+	}
+}
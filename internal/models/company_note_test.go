@@ -0,0 +1,29 @@
+// ABOUTME: Tests for the CompanyNote model.
+// ABOUTME: Verifies the constructor fills in ID, company, content, and timestamp.
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewCompanyNote(t *testing.T) {
+	companyID := uuid.New()
+	before := time.Now().UTC()
+	n := NewCompanyNote(companyID, "Renewal due in March")
+
+	if n.ID == uuid.Nil {
+		t.Error("expected non-nil ID")
+	}
+	if n.CompanyID != companyID {
+		t.Errorf("CompanyID = %v, want %v", n.CompanyID, companyID)
+	}
+	if n.Content != "Renewal due in March" {
+		t.Errorf("Content = %q, want %q", n.Content, "Renewal due in March")
+	}
+	if n.CreatedAt.Before(before) {
+		t.Errorf("CreatedAt %v is before %v", n.CreatedAt, before)
+	}
+}
@@ -18,12 +18,32 @@ type Contact struct {
 	Tags      []string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// DoNotContact marks someone who has opted out or should not be
+	// reached out to. The record is kept; outreach views skip or flag it.
+	DoNotContact bool
+
+	// Source records where the contact came from, e.g. "manual" or "csv".
+	// Empty means unknown.
+	Source string
+
+	// Version counts saved revisions, starting at 1. Updates must carry the
+	// version they read; a stale version is rejected instead of overwriting
+	// someone else's change.
+	Version int
 }
 
+// Sources recorded on contacts and companies created through the CLI and
+// the MCP server. Importers use their own names, such as "csv".
+const (
+	SourceManual = "manual"
+	SourceMCP    = "mcp"
+)
+
 // NewContact creates a Contact with the given name, generating a UUID
-// and initializing Fields, Tags, and timestamps.
+// and initializing Fields, Tags, timestamps, and Version.
 func NewContact(name string) *Contact {
-	now := time.Now()
+	now := time.Now().UTC()
 	return &Contact{
 		ID:        uuid.New(),
 		Name:      name,
@@ -31,10 +51,11 @@ func NewContact(name string) *Contact {
 		Tags:      []string{},
 		CreatedAt: now,
 		UpdatedAt: now,
+		Version:   1,
 	}
 }
 
 // Touch updates the UpdatedAt timestamp to the current time.
 func (c *Contact) Touch() {
-	c.UpdatedAt = time.Now()
+	c.UpdatedAt = time.Now().UTC()
 }
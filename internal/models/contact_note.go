@@ -0,0 +1,28 @@
+// ABOUTME: ContactNote model for the running log of notes kept on a contact.
+// ABOUTME: Notes are append-only entries; each is added or deleted as a whole.
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContactNote is one timestamped entry in a contact's notes history.
+type ContactNote struct {
+	ID        uuid.UUID
+	ContactID uuid.UUID
+	Content   string
+	CreatedAt time.Time
+}
+
+// NewContactNote creates a ContactNote for the given contact, generating a
+// UUID and setting CreatedAt.
+func NewContactNote(contactID uuid.UUID, content string) *ContactNote {
+	return &ContactNote{
+		ID:        uuid.New(),
+		ContactID: contactID,
+		Content:   content,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,29 @@
+// ABOUTME: Tests for the ContactNote model.
+// ABOUTME: Verifies the constructor fills in ID, contact, content, and timestamp.
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewContactNote(t *testing.T) {
+	contactID := uuid.New()
+	before := time.Now().UTC()
+	n := NewContactNote(contactID, "Prefers morning calls")
+
+	if n.ID == uuid.Nil {
+		t.Error("expected non-nil ID")
+	}
+	if n.ContactID != contactID {
+		t.Errorf("ContactID = %v, want %v", n.ContactID, contactID)
+	}
+	if n.Content != "Prefers morning calls" {
+		t.Errorf("Content = %q, want %q", n.Content, "Prefers morning calls")
+	}
+	if n.CreatedAt.Before(before) {
+		t.Errorf("CreatedAt %v is before %v", n.CreatedAt, before)
+	}
+}
@@ -60,6 +60,10 @@ func TestNewContact(t *testing.T) {
 	if c.CreatedAt != c.UpdatedAt {
 		t.Error("expected CreatedAt and UpdatedAt to be equal on creation")
 	}
+
+	if c.CreatedAt.Location() != time.UTC {
+		t.Errorf("expected CreatedAt in UTC, got %v", c.CreatedAt.Location())
+	}
 }
 
 func TestNewContact_UniqueIDs(t *testing.T) {
@@ -27,6 +27,6 @@ func NewRelationship(sourceID, targetID uuid.UUID, relType, context string) *Rel
 		TargetID:  targetID,
 		Type:      relType,
 		Context:   context,
-		CreatedAt: time.Now(),
+		CreatedAt: time.Now().UTC(),
 	}
 }
@@ -0,0 +1,42 @@
+// ABOUTME: Backend-independent attachment and contact photo validation shared by all storage implementations.
+// ABOUTME: Enforces the path-or-blob invariant, the inline blob size limit, and photo type and size limits.
+package storage
+
+import (
+	"strings"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+// validateAttachment checks that exactly one of Path or Data is set and that
+// inline data fits under MaxAttachmentBlobSize. For inline data, Size is set
+// from the payload length.
+func validateAttachment(a *models.Attachment) error {
+	hasPath := a.Path != ""
+	hasData := len(a.Data) > 0
+	if hasPath == hasData {
+		return ErrInvalidAttachment
+	}
+	if hasData {
+		if len(a.Data) > MaxAttachmentBlobSize {
+			return ErrAttachmentTooLarge
+		}
+		a.Size = int64(len(a.Data))
+	}
+	return nil
+}
+
+// validatePhoto checks that a contact photo is an image no larger than
+// MaxContactPhotoSize. Empty data clears a photo and is always valid.
+func validatePhoto(contentType string, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return ErrInvalidPhoto
+	}
+	if len(data) > MaxContactPhotoSize {
+		return ErrPhotoTooLarge
+	}
+	return nil
+}
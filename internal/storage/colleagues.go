@@ -0,0 +1,124 @@
+// ABOUTME: Backend-independent pairing logic for colleague relationship suggestions.
+// ABOUTME: Groups contacts by shared company and proposes links for pairs not yet related.
+package storage
+
+import (
+	"github.com/google/uuid"
+)
+
+// DefaultSuggestionLimit caps colleague suggestions when no limit is given.
+const DefaultSuggestionLimit = 50
+
+// ColleagueSuggestion proposes a "colleague" relationship between two
+// contacts linked to the same company. Suggestions are not stored; callers
+// create the relationship if they accept one.
+type ColleagueSuggestion struct {
+	CompanyID   uuid.UUID `json:"company_id"`
+	CompanyName string    `json:"company_name"`
+	SourceID    uuid.UUID `json:"source_id"`
+	SourceName  string    `json:"source_name"`
+	TargetID    uuid.UUID `json:"target_id"`
+	TargetName  string    `json:"target_name"`
+}
+
+// companyMember is one contact's link to one company.
+type companyMember struct {
+	companyID   uuid.UUID
+	companyName string
+	contactID   uuid.UUID
+	contactName string
+}
+
+// pairKey returns an order-independent key for a pair of entity IDs.
+func pairKey(a, b uuid.UUID) [2]uuid.UUID {
+	if b.String() < a.String() {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+// suggestionCheckBatch is how many candidate pairs are checked for existing
+// relationships per RelationshipExistsMany call.
+const suggestionCheckBatch = 200
+
+// buildColleagueSuggestions pairs up contacts within each company, in the
+// order members are given, skipping pairs that exists reports as already
+// related and pairs already suggested through another company. Candidates
+// are checked in batches and generation stops once limit suggestions exist,
+// so large companies never expand into every possible pair.
+func buildColleagueSuggestions(
+	members []companyMember,
+	exists func([][2]uuid.UUID) (map[[2]uuid.UUID]bool, error),
+	limit int,
+) ([]*ColleagueSuggestion, error) {
+	if limit <= 0 {
+		limit = DefaultSuggestionLimit
+	}
+
+	var companyOrder []uuid.UUID
+	byCompany := make(map[uuid.UUID][]companyMember)
+	for _, m := range members {
+		if _, ok := byCompany[m.companyID]; !ok {
+			companyOrder = append(companyOrder, m.companyID)
+		}
+		byCompany[m.companyID] = append(byCompany[m.companyID], m)
+	}
+
+	var suggestions []*ColleagueSuggestion
+	var batch []*ColleagueSuggestion
+	flush := func() error {
+		pairs := make([][2]uuid.UUID, len(batch))
+		for i, sg := range batch {
+			pairs[i] = [2]uuid.UUID{sg.SourceID, sg.TargetID}
+		}
+		related, err := exists(pairs)
+		if err != nil {
+			return err
+		}
+		for i, sg := range batch {
+			if !related[pairs[i]] && len(suggestions) < limit {
+				suggestions = append(suggestions, sg)
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	seen := make(map[[2]uuid.UUID]bool)
+	for _, companyID := range companyOrder {
+		group := byCompany[companyID]
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				a, b := group[i], group[j]
+				key := pairKey(a.contactID, b.contactID)
+				if a.contactID == b.contactID || seen[key] {
+					continue
+				}
+				seen[key] = true
+				batch = append(batch, &ColleagueSuggestion{
+					CompanyID:   companyID,
+					CompanyName: a.companyName,
+					SourceID:    a.contactID,
+					SourceName:  a.contactName,
+					TargetID:    b.contactID,
+					TargetName:  b.contactName,
+				})
+				if len(batch) < suggestionCheckBatch {
+					continue
+				}
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				if len(suggestions) >= limit {
+					return suggestions, nil
+				}
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	return suggestions, nil
+}
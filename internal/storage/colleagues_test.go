@@ -0,0 +1,62 @@
+// ABOUTME: Tests for backend-independent colleague suggestion pairing.
+// ABOUTME: Verifies batched existence checks, deduplication across companies, and the limit.
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildColleagueSuggestionsBatching(t *testing.T) {
+	// 30 members yield 435 candidate pairs, more than one check batch.
+	company := uuid.New()
+	var members []companyMember
+	for i := 0; i < 30; i++ {
+		members = append(members, companyMember{
+			companyID:   company,
+			companyName: "Acme",
+			contactID:   uuid.New(),
+			contactName: fmt.Sprintf("Person %02d", i),
+		})
+	}
+	// The same two people at a second company must not be suggested twice.
+	other := uuid.New()
+	members = append(members,
+		companyMember{companyID: other, companyName: "Other", contactID: members[0].contactID},
+		companyMember{companyID: other, companyName: "Other", contactID: members[1].contactID},
+	)
+
+	related := pairKey(members[0].contactID, members[2].contactID)
+	var calls, checked int
+	exists := func(pairs [][2]uuid.UUID) (map[[2]uuid.UUID]bool, error) {
+		calls++
+		checked += len(pairs)
+		out := make(map[[2]uuid.UUID]bool, len(pairs))
+		for _, p := range pairs {
+			out[p] = pairKey(p[0], p[1]) == related
+		}
+		return out, nil
+	}
+
+	got, err := buildColleagueSuggestions(members, exists, 1000)
+	if err != nil {
+		t.Fatalf("buildColleagueSuggestions: %v", err)
+	}
+	if len(got) != 434 {
+		t.Errorf("suggestions = %d, want 434 (435 pairs minus one related)", len(got))
+	}
+	if calls != 3 || checked != 435 {
+		t.Errorf("exists called %d times for %d pairs, want 3 calls for 435", calls, checked)
+	}
+
+	calls = 0
+	got, err = buildColleagueSuggestions(members, exists, 5)
+	if err != nil {
+		t.Fatalf("buildColleagueSuggestions(limit 5): %v", err)
+	}
+	if len(got) != 5 || calls != 1 {
+		t.Errorf("limit 5: got %d suggestions in %d calls, want 5 in 1", len(got), calls)
+	}
+}
@@ -0,0 +1,58 @@
+// ABOUTME: Backend-independent linking of contacts to a company by email domain.
+// ABOUTME: Finds contacts at the company's domain that are not yet related to it and links them as works_at.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// LinkDomainContacts links every contact whose email is at the company's
+// domain to that company with a works_at relationship, skipping contacts
+// already related to it in any way. It returns the contacts it linked, and
+// ErrCompanyDomainRequired if the company has no domain.
+func LinkDomainContacts(s Storage, companyID uuid.UUID) ([]*models.Contact, error) {
+	company, err := s.GetCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+	if NormalizeDomain(company.Domain) == "" {
+		return nil, ErrCompanyDomainRequired
+	}
+
+	contacts, err := s.ListContactsByEmailDomain(company.Domain)
+	if err != nil {
+		return nil, err
+	}
+	var linked []*models.Contact
+	for _, c := range contacts {
+		related, err := relatedTo(s, c.ID, company.ID)
+		if err != nil {
+			return linked, err
+		}
+		if related {
+			continue
+		}
+		if err := s.CreateRelationship(models.NewRelationship(c.ID, company.ID, worksAtType, "")); err != nil {
+			return linked, fmt.Errorf("link %s: %w", c.Name, err)
+		}
+		linked = append(linked, c)
+	}
+	return linked, nil
+}
+
+// relatedTo reports whether any relationship joins a and b.
+func relatedTo(s Storage, a, b uuid.UUID) (bool, error) {
+	rels, err := s.ListRelationships(a)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range rels {
+		if r.SourceID == b || r.TargetID == b {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,60 @@
+// ABOUTME: Tests for linking contacts to a company by email domain.
+// ABOUTME: Verifies new works_at links, skipping already-related contacts, and the missing-domain error.
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestLinkDomainContacts(t *testing.T) {
+	store := newTestStore(t)
+
+	acme := models.NewCompany("Acme")
+	acme.Domain = "acme.com"
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	contacts := map[string]*models.Contact{}
+	for name, email := range map[string]string{
+		"Jane":    "jane@acme.com",
+		"Founder": "founder@acme.com",
+		"Other":   "other@example.com",
+	} {
+		c := models.NewContact(name)
+		c.Email = email
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact %s: %v", name, err)
+		}
+		contacts[name] = c
+	}
+	if err := store.CreateRelationship(models.NewRelationship(acme.ID, contacts["Founder"].ID, "founded_by", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+
+	linked, err := LinkDomainContacts(store, acme.ID)
+	if err != nil {
+		t.Fatalf("LinkDomainContacts: %v", err)
+	}
+	if len(linked) != 1 || linked[0].ID != contacts["Jane"].ID {
+		t.Fatalf("linked = %v, want only Jane", linked)
+	}
+	rels, err := store.ListRelationships(contacts["Jane"].ID)
+	if err != nil || len(rels) != 1 || rels[0].Type != "works_at" || rels[0].TargetID != acme.ID {
+		t.Errorf("Jane relationships = %+v (err %v), want works_at Acme", rels, err)
+	}
+
+	if again, err := LinkDomainContacts(store, acme.ID); err != nil || len(again) != 0 {
+		t.Errorf("second run linked %d (err %v), want 0", len(again), err)
+	}
+
+	bare := models.NewCompany("Bare")
+	if err := store.CreateCompany(bare); err != nil {
+		t.Fatalf("CreateCompany bare: %v", err)
+	}
+	if _, err := LinkDomainContacts(store, bare.ID); !errors.Is(err, ErrCompanyDomainRequired) {
+		t.Errorf("no domain: err = %v, want ErrCompanyDomainRequired", err)
+	}
+}
@@ -4,40 +4,117 @@ package storage
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/crm/internal/models"
 )
 
 var (
-	ErrContactNotFound      = errors.New("contact not found")
-	ErrCompanyNotFound      = errors.New("company not found")
-	ErrRelationshipNotFound = errors.New("relationship not found")
-	ErrPrefixTooShort       = errors.New("prefix must be at least 6 characters")
-	ErrAmbiguousPrefix      = errors.New("prefix matches multiple records")
+	ErrContactNotFound       = errors.New("contact not found")
+	ErrCompanyNotFound       = errors.New("company not found")
+	ErrRelationshipNotFound  = errors.New("relationship not found")
+	ErrPrefixTooShort        = errors.New("prefix must be at least 6 characters")
+	ErrAmbiguousPrefix       = errors.New("prefix matches multiple records")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment exceeds inline size limit; store a path reference instead")
+	ErrInvalidAttachment     = errors.New("attachment must have exactly one of path or data")
+	ErrSelfRelationship      = errors.New("relationship cannot link an entity to itself")
+	ErrEndpointNotFound      = errors.New("relationship endpoint not found")
+	ErrDuplicateRelationship = errors.New("relationship of this type already links these entities")
+	ErrVersionConflict       = errors.New("record was modified since it was read")
+	ErrNameRequired          = errors.New("name is required")
+	ErrPhotoNotFound         = errors.New("contact has no photo")
+	ErrPhotoTooLarge         = errors.New("photo exceeds size limit")
+	ErrInvalidPhoto          = errors.New("photo content type must be an image/ type")
+	ErrNoteNotFound          = errors.New("note not found")
+	ErrNoteContentRequired   = errors.New("note content is required")
+	ErrSettingKeyRequired    = errors.New("setting key is required")
+	ErrDuplicateEmail        = errors.New("another contact already uses this email")
+	ErrCompanyDomainRequired = errors.New("company has no domain")
 )
 
+// MaxAttachmentBlobSize is the largest attachment, in bytes, that may be
+// stored inline. Larger files must be referenced by path.
+const MaxAttachmentBlobSize = 1 << 20
+
+// MaxContactPhotoSize is the largest contact photo, in bytes.
+const MaxContactPhotoSize = 256 << 10
+
 // Storage defines the contract that all CRM data backends must satisfy.
 type Storage interface {
 	CreateContact(contact *models.Contact) error
 	GetContact(id uuid.UUID) (*models.Contact, error)
+	GetContactsByIDs(ids []uuid.UUID) (map[uuid.UUID]*models.Contact, error)
 	GetContactByPrefix(prefix string) (*models.Contact, error)
+	GetContactExpanded(id uuid.UUID) (*ContactExpanded, error)
+	GetContactByEmail(email string) (*models.Contact, error)
+	ListContactsByEmailDomain(domain string) ([]*models.Contact, error)
+	GetOrCreateContact(name, email string, companyID *uuid.UUID) (*models.Contact, bool, error)
 	ListContacts(filter *ContactFilter) ([]*models.Contact, error)
+	GetAllContacts() ([]*models.Contact, error)
+	ForEachContact(fn func(*models.Contact) error) error
+	ListContactsAfter(cursor Cursor, limit int) ([]*models.Contact, Cursor, error)
+	GetContactsModifiedSince(since time.Time) ([]*models.Contact, error)
 	UpdateContact(contact *models.Contact) error
 	DeleteContact(id uuid.UUID) error
+	SetContactPhoto(id uuid.UUID, contentType string, data []byte) error
+	GetContactPhoto(id uuid.UUID) ([]byte, string, error)
 
 	CreateCompany(company *models.Company) error
 	GetCompany(id uuid.UUID) (*models.Company, error)
+	GetCompaniesByIDs(ids []uuid.UUID) (map[uuid.UUID]*models.Company, error)
 	GetCompanyByPrefix(prefix string) (*models.Company, error)
+	GetCompanyByDomain(domain string) (*models.Company, error)
+	GetOrCreateCompany(name string) (*models.Company, bool, error)
 	ListCompanies(filter *CompanyFilter) ([]*models.Company, error)
+	GetAllCompanies() ([]*models.Company, error)
+	ForEachCompany(fn func(*models.Company) error) error
+	ListCompaniesAfter(cursor Cursor, limit int) ([]*models.Company, Cursor, error)
+	GetCompaniesModifiedSince(since time.Time) ([]*models.Company, error)
 	UpdateCompany(company *models.Company) error
 	DeleteCompany(id uuid.UUID) error
+	ReassignCompanyContacts(from, to uuid.UUID) (int, error)
 
 	CreateRelationship(rel *models.Relationship) error
+	CreateOrUpdateRelationship(rel *models.Relationship) error
 	ListRelationships(entityID uuid.UUID) ([]*models.Relationship, error)
 	DeleteRelationship(id uuid.UUID) error
+	RelationshipExistsMany(pairs [][2]uuid.UUID) (map[[2]uuid.UUID]bool, error)
+	SuggestColleagueRelationships(limit int) ([]*ColleagueSuggestion, error)
+
+	AddAttachment(a *models.Attachment) error
+	GetAttachment(id uuid.UUID) (*models.Attachment, error)
+	ListAttachments(entityID uuid.UUID) ([]*models.Attachment, error)
+	DeleteAttachment(id uuid.UUID) error
+
+	AddCompanyNote(n *models.CompanyNote) error
+	ListCompanyNotes(companyID uuid.UUID) ([]*models.CompanyNote, error)
+	DeleteCompanyNote(id uuid.UUID) error
+
+	AddContactNote(n *models.ContactNote) error
+	ListContactNotes(contactID uuid.UUID) ([]*models.ContactNote, error)
+	DeleteContactNote(id uuid.UUID) error
+
+	GetSetting(key string) (string, bool, error)
+	SetSetting(key, value string) error
+	ListSettings() (map[string]string, error)
+
+	ListRecent(entityType string, limit int) ([]uuid.UUID, error)
+	ListDeletionsSince(since time.Time) ([]Deletion, error)
+	WasDeleted(entityType string, id uuid.UUID) (bool, error)
+	ReadChangeFeed(afterSeq int64, limit int) ([]ChangeEvent, error)
+
+	ClaimIdempotencyKey(entityType, key string, id uuid.UUID) (uuid.UUID, bool, error)
+	ReleaseIdempotencyKey(entityType, key string, id uuid.UUID) error
+
+	ListContactsWithoutCompany() ([]*models.Contact, error)
+	ListEmptyCompanies() ([]*models.Company, error)
 
 	Search(query string) (*SearchResults, error)
+	Stats() (*Stats, error)
+	SchemaVersion() (int, bool, error)
+	CheckIntegrity() (*IntegrityReport, error)
 
 	Close() error
 }
@@ -45,6 +122,7 @@ type Storage interface {
 // ContactFilter controls which contacts are returned by ListContacts.
 type ContactFilter struct {
 	Tag    *string
+	Source string // exact match on Contact.Source when non-empty
 	Search string
 	Limit  int
 }
@@ -52,6 +130,7 @@ type ContactFilter struct {
 // CompanyFilter controls which companies are returned by ListCompanies.
 type CompanyFilter struct {
 	Tag    *string
+	Source string // exact match on Company.Source when non-empty
 	Search string
 	Limit  int
 }
@@ -61,3 +140,80 @@ type SearchResults struct {
 	Contacts  []*models.Contact
 	Companies []*models.Company
 }
+
+// ContactExpanded bundles a contact with the records a detail view needs,
+// so callers can load it in one call instead of several round-trips.
+type ContactExpanded struct {
+	Contact       *models.Contact
+	Companies     []*models.Company
+	Relationships []*RelatedEntity
+	Notes         []*models.ContactNote
+	Attachments   []*models.Attachment
+}
+
+// RelatedEntity pairs a relationship with the entity on its other end.
+// OtherType is empty when the counterpart no longer exists.
+type RelatedEntity struct {
+	Relationship *models.Relationship
+	OtherID      uuid.UUID
+	OtherType    string
+	OtherName    string
+}
+
+// Entity type names used by attachments, access tracking, idempotency keys,
+// and deletion records.
+const (
+	EntityContact = "contact"
+	EntityCompany = "company"
+)
+
+// Deletion records that a contact or company was deleted, so incremental
+// consumers can drop their copy.
+type Deletion struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+// Change feed operations recorded in ChangeEvent.Op.
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// ChangeEvent is one create, update, or delete of a contact or company.
+// Seq increases with every event, so consumers resume from the last Seq
+// they processed rather than from a timestamp.
+type ChangeEvent struct {
+	Seq        int64     `json:"seq"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	Op         string    `json:"op"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// Stats holds per-entity record counts for a storage backend.
+type Stats struct {
+	Contacts      int `json:"contacts"`
+	Companies     int `json:"companies"`
+	Relationships int `json:"relationships"`
+	Attachments   int `json:"attachments"`
+}
+
+// IntegrityReport lists records whose references point at contacts or
+// companies that no longer exist, and redundant relationships: every
+// relationship but the newest of each type between the same pair.
+type IntegrityReport struct {
+	DanglingRelationships  []uuid.UUID `json:"dangling_relationships"`
+	OrphanedAttachments    []uuid.UUID `json:"orphaned_attachments"`
+	DuplicateRelationships []uuid.UUID `json:"duplicate_relationships"`
+	OrphanedCompanyNotes   []uuid.UUID `json:"orphaned_company_notes"`
+	OrphanedContactNotes   []uuid.UUID `json:"orphaned_contact_notes"`
+}
+
+// Total returns the number of broken or redundant records found.
+func (r *IntegrityReport) Total() int {
+	return len(r.DanglingRelationships) + len(r.OrphanedAttachments) + len(r.DuplicateRelationships) +
+		len(r.OrphanedCompanyNotes) + len(r.OrphanedContactNotes)
+}
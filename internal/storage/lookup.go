@@ -0,0 +1,84 @@
+// ABOUTME: Backend-independent normalization for email, domain, and phone lookups.
+// ABOUTME: Lets lookups and searches match despite case, URL, and punctuation noise.
+package storage
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeEmail lowercases and trims an email address for comparison.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeDomain reduces a domain or website value to a bare lowercase host,
+// so "https://www.Acme.com/about" and "acme.com" compare equal.
+func NormalizeDomain(domain string) string {
+	d := strings.ToLower(strings.TrimSpace(domain))
+	if i := strings.Index(d, "://"); i >= 0 {
+		d = d[i+3:]
+	}
+	if i := strings.IndexAny(d, "/?#"); i >= 0 {
+		d = d[:i]
+	}
+	if i := strings.LastIndex(d, ":"); i >= 0 {
+		d = d[:i]
+	}
+	d = strings.TrimPrefix(d, "www.")
+	return strings.TrimSuffix(d, ".")
+}
+
+// EmailDomain returns the normalized domain part of an email address, or ""
+// if the address has no domain.
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return NormalizeDomain(email[at+1:])
+}
+
+// worksAtType is the relationship type GetOrCreateContact uses to link a
+// new contact to its company.
+const worksAtType = "works_at"
+
+// minPhoneQueryDigits is the fewest digits a search must contain before it
+// is also matched against phone numbers.
+const minPhoneQueryDigits = 4
+
+// NormalizePhone keeps only the digits of a phone number, so "(555) 123-4567"
+// and "5551234567" compare equal.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// phoneSearchKey returns the digits of phone in reverse order. Contacts
+// store this key in an indexed column, so matching the trailing digits of
+// a number, which is how callers and users usually give one, is a prefix
+// range scan instead of a full-table LIKE.
+func phoneSearchKey(phone string) string {
+	digits := []byte(NormalizePhone(phone))
+	slices.Reverse(digits)
+	return string(digits)
+}
+
+// phoneSearchDigits returns the digits of query when it looks like a phone
+// number: only digits and common separators, with at least
+// minPhoneQueryDigits digits. Otherwise it returns "".
+func phoneSearchDigits(query string) string {
+	if strings.TrimLeft(query, "0123456789 -().+/") != "" {
+		return ""
+	}
+	digits := NormalizePhone(query)
+	if len(digits) < minPhoneQueryDigits {
+		return ""
+	}
+	return digits
+}
@@ -0,0 +1,51 @@
+// ABOUTME: Tests for email, domain, and phone normalization helpers.
+// ABOUTME: Covers case folding, URL stripping, email domain extraction, and phone digits.
+package storage
+
+import "testing"
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := map[string]string{
+		"acme.com":                   "acme.com",
+		"  ACME.com ":                "acme.com",
+		"https://www.acme.com/about": "acme.com",
+		"http://acme.com:8080":       "acme.com",
+		"www.acme.com.":              "acme.com",
+		"eng.acme.com?x=1":           "eng.acme.com",
+		"":                           "",
+	}
+	for in, want := range tests {
+		if got := NormalizeDomain(in); got != want {
+			t.Errorf("NormalizeDomain(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEmailDomain(t *testing.T) {
+	tests := map[string]string{
+		"jane@Acme.com":      "acme.com",
+		"odd@name@acme.com":  "acme.com",
+		"no-at-sign.example": "",
+	}
+	for in, want := range tests {
+		if got := EmailDomain(in); got != want {
+			t.Errorf("EmailDomain(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPhoneSearchDigits(t *testing.T) {
+	tests := map[string]string{
+		"555-1234":          "5551234",
+		"(555) 123-4567":    "5551234567",
+		"+1 555.123.4567":   "15551234567",
+		"555":               "",
+		"Alice 5551234":     "",
+		"alice@example.com": "",
+	}
+	for in, want := range tests {
+		if got := phoneSearchDigits(in); got != want {
+			t.Errorf("phoneSearchDigits(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -5,6 +5,8 @@ package storage
 import (
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/harperreed/mdstore"
 )
@@ -15,11 +17,25 @@ var _ Storage = (*MarkdownStore)(nil)
 // MarkdownStore implements Storage using markdown files on disk.
 type MarkdownStore struct {
 	dataDir string
+	opts    Options
+
+	// idempotencyMu makes claiming an idempotency key a single step for
+	// callers sharing this store.
+	idempotencyMu sync.Mutex
+
+	// changeLogMu makes numbering and appending a change feed event a single
+	// step, so concurrent writes never share a sequence number.
+	changeLogMu sync.Mutex
 }
 
-// NewMarkdownStore creates a new MarkdownStore backed by the given directory.
-// It creates the dataDir, contacts/, and companies/ subdirectories if needed.
+// NewMarkdownStore creates a new MarkdownStore with default options.
 func NewMarkdownStore(dataDir string) (*MarkdownStore, error) {
+	return NewMarkdownStoreWithOptions(dataDir, Options{})
+}
+
+// NewMarkdownStoreWithOptions creates a new MarkdownStore backed by the given
+// directory. It creates the dataDir, contacts/, and companies/ subdirectories if needed.
+func NewMarkdownStoreWithOptions(dataDir string, opts Options) (*MarkdownStore, error) {
 	for _, dir := range []string{
 		dataDir,
 		filepath.Join(dataDir, "contacts"),
@@ -29,7 +45,11 @@ func NewMarkdownStore(dataDir string) (*MarkdownStore, error) {
 			return nil, err
 		}
 	}
-	return &MarkdownStore{dataDir: dataDir}, nil
+	store := &MarkdownStore{dataDir: dataDir, opts: opts}
+	if err := store.pruneChangeLogs(); err != nil {
+		return nil, err
+	}
+	return store, nil
 }
 
 // Close is a no-op for the file-based backend.
@@ -50,6 +70,72 @@ func (s *MarkdownStore) relationshipsFile() string {
 	return filepath.Join(s.dataDir, "_relationships.yaml")
 }
 
+// attachmentsFile returns the path to the attachment metadata YAML file.
+func (s *MarkdownStore) attachmentsFile() string {
+	return filepath.Join(s.dataDir, "_attachments.yaml")
+}
+
+// attachmentsDir returns the path to the directory holding inline attachment blobs.
+func (s *MarkdownStore) attachmentsDir() string {
+	return filepath.Join(s.dataDir, "attachments")
+}
+
+// notesFile returns the path to the YAML file holding k's notes.
+func (s *MarkdownStore) notesFile(k noteKind) string {
+	return filepath.Join(s.dataDir, k.file)
+}
+
+// settingsFile returns the path to the settings YAML file.
+func (s *MarkdownStore) settingsFile() string {
+	return filepath.Join(s.dataDir, "_settings.yaml")
+}
+
+// photoTypesFile returns the path to the YAML map of contact ID to photo content type.
+func (s *MarkdownStore) photoTypesFile() string {
+	return filepath.Join(s.dataDir, "_photos.yaml")
+}
+
+// photosDir returns the path to the directory holding contact photos.
+func (s *MarkdownStore) photosDir() string {
+	return filepath.Join(s.dataDir, "photos")
+}
+
+// recentFile returns the path to the recent-access YAML file.
+func (s *MarkdownStore) recentFile() string {
+	return filepath.Join(s.dataDir, "_recent.yaml")
+}
+
+// idempotencyFile returns the path to the idempotency key YAML file.
+func (s *MarkdownStore) idempotencyFile() string {
+	return filepath.Join(s.dataDir, "_idempotency.yaml")
+}
+
+// deletionsFile returns the path to the deletion log YAML file.
+func (s *MarkdownStore) deletionsFile() string {
+	return filepath.Join(s.dataDir, "_deletions.yaml")
+}
+
+// changesFile returns the path to the change feed YAML file.
+func (s *MarkdownStore) changesFile() string {
+	return filepath.Join(s.dataDir, "_changes.yaml")
+}
+
+// formatTime renders t in UTC for storage, so timestamps written from
+// different zones sort and compare consistently.
+func formatTime(t time.Time) string {
+	return mdstore.FormatTime(t.UTC())
+}
+
+// parseTime reads a stored timestamp and returns it in UTC, whatever zone
+// offset the file was written with.
+func parseTime(s string) (time.Time, error) {
+	t, err := mdstore.ParseTime(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
 // slugForName generates a filename-safe slug, appending a UUID prefix on collision.
 func slugForName(name, id, dir string) string {
 	base := mdstore.Slugify(name)
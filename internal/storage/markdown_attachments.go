@@ -0,0 +1,167 @@
+// ABOUTME: Markdown storage operations for attachments.
+// ABOUTME: Keeps metadata in _attachments.yaml and inline blobs as files under attachments/.
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/mdstore"
+)
+
+// attachmentEntry is the YAML representation of attachment metadata.
+type attachmentEntry struct {
+	ID          string `yaml:"id"`
+	EntityType  string `yaml:"entity_type"`
+	EntityID    string `yaml:"entity_id"`
+	Filename    string `yaml:"filename"`
+	ContentType string `yaml:"content_type,omitempty"`
+	Size        int64  `yaml:"size"`
+	Path        string `yaml:"path,omitempty"`
+	CreatedAt   string `yaml:"created_at"`
+}
+
+// attachmentToEntry converts a models.Attachment to its YAML entry.
+func attachmentToEntry(a *models.Attachment) attachmentEntry {
+	return attachmentEntry{
+		ID:          a.ID.String(),
+		EntityType:  a.EntityType,
+		EntityID:    a.EntityID.String(),
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Path:        a.Path,
+		CreatedAt:   formatTime(a.CreatedAt),
+	}
+}
+
+// entryToAttachment converts a YAML entry back to a models.Attachment without data.
+func entryToAttachment(e attachmentEntry) (*models.Attachment, error) {
+	id, err := uuid.Parse(e.ID)
+	if err != nil {
+		return nil, err
+	}
+	entityID, err := uuid.Parse(e.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := parseTime(e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Attachment{
+		ID:          id,
+		EntityType:  e.EntityType,
+		EntityID:    entityID,
+		Filename:    e.Filename,
+		ContentType: e.ContentType,
+		Size:        e.Size,
+		Path:        e.Path,
+		CreatedAt:   createdAt,
+	}, nil
+}
+
+// readAttachments reads all attachment metadata entries.
+func (s *MarkdownStore) readAttachments() ([]attachmentEntry, error) {
+	var entries []attachmentEntry
+	if err := mdstore.ReadYAML(s.attachmentsFile(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// blobPath returns the on-disk location of an inline attachment's contents.
+func (s *MarkdownStore) blobPath(id string) string {
+	return filepath.Join(s.attachmentsDir(), id)
+}
+
+// AddAttachment validates the attachment, writes any inline data to disk,
+// and appends its metadata.
+func (s *MarkdownStore) AddAttachment(a *models.Attachment) error {
+	if err := validateAttachment(a); err != nil {
+		return err
+	}
+	if len(a.Data) > 0 {
+		if err := mdstore.AtomicWrite(s.blobPath(a.ID.String()), a.Data); err != nil {
+			return err
+		}
+	}
+	return mdstore.AppendYAML(s.attachmentsFile(), attachmentToEntry(a))
+}
+
+// GetAttachment retrieves an attachment including its inline data.
+func (s *MarkdownStore) GetAttachment(id uuid.UUID) (*models.Attachment, error) {
+	entries, err := s.readAttachments()
+	if err != nil {
+		return nil, err
+	}
+	idStr := id.String()
+	for _, e := range entries {
+		if e.ID != idStr {
+			continue
+		}
+		a, err := entryToAttachment(e)
+		if err != nil {
+			return nil, err
+		}
+		if a.Path == "" {
+			data, err := os.ReadFile(s.blobPath(idStr)) //nolint:gosec // path is built from a parsed UUID
+			if err != nil {
+				return nil, err
+			}
+			a.Data = data
+		}
+		return a, nil
+	}
+	return nil, ErrAttachmentNotFound
+}
+
+// ListAttachments returns attachment metadata for the given entity.
+func (s *MarkdownStore) ListAttachments(entityID uuid.UUID) ([]*models.Attachment, error) {
+	entries, err := s.readAttachments()
+	if err != nil {
+		return nil, err
+	}
+	idStr := entityID.String()
+	var results []*models.Attachment
+	for _, e := range entries {
+		if e.EntityID != idStr {
+			continue
+		}
+		a, err := entryToAttachment(e)
+		if err != nil {
+			continue
+		}
+		results = append(results, a)
+	}
+	return results, nil
+}
+
+// DeleteAttachment removes an attachment's metadata and any inline blob.
+func (s *MarkdownStore) DeleteAttachment(id uuid.UUID) error {
+	entries, err := s.readAttachments()
+	if err != nil {
+		return err
+	}
+	idStr := id.String()
+	found := false
+	var remaining []attachmentEntry
+	for _, e := range entries {
+		if e.ID == idStr {
+			found = true
+			continue
+		}
+		remaining = append(remaining, e)
+	}
+	if !found {
+		return ErrAttachmentNotFound
+	}
+	if err := os.Remove(s.blobPath(idStr)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return mdstore.WriteYAML(s.attachmentsFile(), remaining)
+}
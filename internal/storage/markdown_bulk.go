@@ -0,0 +1,57 @@
+// ABOUTME: Markdown bulk fetch of contacts and companies by ID.
+// ABOUTME: Reads each entity directory once instead of scanning it per ID.
+package storage
+
+import (
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// GetContactsByIDs returns the contacts with the given IDs, keyed by ID.
+// IDs with no matching contact are absent from the map.
+func (s *MarkdownStore) GetContactsByIDs(ids []uuid.UUID) (map[uuid.UUID]*models.Contact, error) {
+	want := idSet(ids)
+	result := make(map[uuid.UUID]*models.Contact, len(want))
+	if len(want) == 0 {
+		return result, nil
+	}
+	err := s.ForEachContact(func(c *models.Contact) error {
+		if want[c.ID] {
+			result[c.ID] = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCompaniesByIDs returns the companies with the given IDs, keyed by ID.
+// IDs with no matching company are absent from the map.
+func (s *MarkdownStore) GetCompaniesByIDs(ids []uuid.UUID) (map[uuid.UUID]*models.Company, error) {
+	want := idSet(ids)
+	result := make(map[uuid.UUID]*models.Company, len(want))
+	if len(want) == 0 {
+		return result, nil
+	}
+	err := s.ForEachCompany(func(c *models.Company) error {
+		if want[c.ID] {
+			result[c.ID] = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// idSet returns ids as a set.
+func idSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,54 @@
+// ABOUTME: Markdown cleanup of records that depend on a deleted contact or company.
+// ABOUTME: Shared by DeleteContact and DeleteCompany so both cascade the same way.
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/mdstore"
+)
+
+// deleteDependents removes the relationships, attachments, notes, and photo
+// that belong to entity id. Inline attachment blobs are deleted; files
+// referenced by path are left alone.
+func (s *MarkdownStore) deleteDependents(id uuid.UUID) error {
+	if err := s.deleteRelationshipsFor(id); err != nil {
+		return err
+	}
+	if err := s.deleteAttachmentsFor(id); err != nil {
+		return err
+	}
+	if err := s.deleteNotesFor(companyNotes, id); err != nil {
+		return err
+	}
+	if err := s.deleteNotesFor(contactNotes, id); err != nil {
+		return err
+	}
+	return s.deleteContactPhoto(id)
+}
+
+// deleteAttachmentsFor removes every attachment recorded against id.
+func (s *MarkdownStore) deleteAttachmentsFor(id uuid.UUID) error {
+	entries, err := s.readAttachments()
+	if err != nil {
+		return err
+	}
+	idStr := id.String()
+	remaining := make([]attachmentEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.EntityID != idStr {
+			remaining = append(remaining, e)
+			continue
+		}
+		if err := os.Remove(s.blobPath(e.ID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if len(remaining) == len(entries) {
+		return nil
+	}
+	return mdstore.WriteYAML(s.attachmentsFile(), remaining)
+}
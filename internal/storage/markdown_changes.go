@@ -0,0 +1,185 @@
+// ABOUTME: Markdown queries for incremental export: modified-since, the deletion log, and the change feed.
+// ABOUTME: Deletes are appended to _deletions.yaml and every contact and company write to _changes.yaml, before the write itself.
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// deletionEntry is the YAML representation of one deletion record.
+type deletionEntry struct {
+	EntityType string `yaml:"entity_type" json:"entity_type"`
+	EntityID   string `yaml:"entity_id" json:"entity_id"`
+	DeletedAt  string `yaml:"deleted_at" json:"deleted_at"`
+}
+
+func (e deletionEntry) loggedAt() string { return e.DeletedAt }
+
+// changeEntry is the YAML representation of one change feed event.
+type changeEntry struct {
+	Seq        int64  `yaml:"seq" json:"seq"`
+	EntityType string `yaml:"entity_type" json:"entity_type"`
+	EntityID   string `yaml:"entity_id" json:"entity_id"`
+	Op         string `yaml:"op" json:"op"`
+	ChangedAt  string `yaml:"changed_at" json:"changed_at"`
+}
+
+func (e changeEntry) loggedAt() string { return e.ChangedAt }
+
+// GetContactsModifiedSince returns contacts updated strictly after since,
+// oldest change first.
+func (s *MarkdownStore) GetContactsModifiedSince(since time.Time) ([]*models.Contact, error) {
+	var contacts []*models.Contact
+	err := s.ForEachContact(func(c *models.Contact) error {
+		if c.UpdatedAt.After(since) {
+			contacts = append(contacts, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(contacts, func(a, b *models.Contact) int {
+		return compareCursorKeys(a.UpdatedAt, a.ID, b.UpdatedAt, b.ID)
+	})
+	return contacts, nil
+}
+
+// GetCompaniesModifiedSince returns companies updated strictly after since,
+// oldest change first.
+func (s *MarkdownStore) GetCompaniesModifiedSince(since time.Time) ([]*models.Company, error) {
+	var companies []*models.Company
+	err := s.ForEachCompany(func(c *models.Company) error {
+		if c.UpdatedAt.After(since) {
+			companies = append(companies, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(companies, func(a, b *models.Company) int {
+		return compareCursorKeys(a.UpdatedAt, a.ID, b.UpdatedAt, b.ID)
+	})
+	return companies, nil
+}
+
+// ListDeletionsSince returns contacts and companies deleted strictly after
+// since, oldest first.
+func (s *MarkdownStore) ListDeletionsSince(since time.Time) ([]Deletion, error) {
+	entries, err := readLog[deletionEntry](s.deletionsFile())
+	if err != nil {
+		return nil, err
+	}
+	var deletions []Deletion
+	for _, e := range entries {
+		id, err := uuid.Parse(e.EntityID)
+		if err != nil {
+			continue
+		}
+		deletedAt, err := parseTime(e.DeletedAt)
+		if err != nil || !deletedAt.After(since) {
+			continue
+		}
+		deletions = append(deletions, Deletion{EntityType: e.EntityType, EntityID: id, DeletedAt: deletedAt})
+	}
+	return deletions, nil
+}
+
+// WasDeleted reports whether the deletion log records entity id.
+func (s *MarkdownStore) WasDeleted(entityType string, id uuid.UUID) (bool, error) {
+	entries, err := readLog[deletionEntry](s.deletionsFile())
+	if err != nil {
+		return false, err
+	}
+	idStr := id.String()
+	for _, e := range entries {
+		if e.EntityType == entityType && e.EntityID == idStr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// recordDeletion appends a deletion record for entity id. Entries are
+// written in deletion order, so the file stays sorted oldest first.
+func (s *MarkdownStore) recordDeletion(entityType string, id uuid.UUID) error {
+	return appendLogEntry(s.deletionsFile(), deletionEntry{
+		EntityType: entityType,
+		EntityID:   id.String(),
+		DeletedAt:  formatTime(time.Now()),
+	})
+}
+
+// ReadChangeFeed returns up to limit change events with a sequence greater
+// than afterSeq, in sequence order. A non-positive limit uses the store's
+// default list limit.
+func (s *MarkdownStore) ReadChangeFeed(afterSeq int64, limit int) ([]ChangeEvent, error) {
+	entries, err := readLog[changeEntry](s.changesFile())
+	if err != nil {
+		return nil, err
+	}
+	limit = s.opts.listLimit(limit)
+	var events []ChangeEvent
+	for _, e := range entries {
+		if e.Seq <= afterSeq {
+			continue
+		}
+		id, err := uuid.Parse(e.EntityID)
+		if err != nil {
+			continue
+		}
+		changedAt, err := parseTime(e.ChangedAt)
+		if err != nil {
+			continue
+		}
+		events = append(events, ChangeEvent{Seq: e.Seq, EntityType: e.EntityType, EntityID: id, Op: e.Op, ChangedAt: changedAt})
+		if len(events) == limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+// recordChange appends a change feed event for entity id, numbered one past
+// the last recorded event. Callers record the change before making it, so a
+// failure here leaves nothing persisted for them to retry on top of; a write
+// that then fails leaves an event for an unchanged entity, which consumers
+// that re-read the entity already tolerate.
+func (s *MarkdownStore) recordChange(entityType string, id uuid.UUID, op string) error {
+	s.changeLogMu.Lock()
+	defer s.changeLogMu.Unlock()
+
+	last, _, err := lastLogEntry[changeEntry](s.changesFile())
+	if err != nil {
+		return err
+	}
+	return appendLogEntry(s.changesFile(), changeEntry{
+		Seq:        last.Seq + 1,
+		EntityType: entityType,
+		EntityID:   id.String(),
+		Op:         op,
+		ChangedAt:  formatTime(time.Now()),
+	})
+}
+
+// pruneChangeLogs drops change feed and deletion entries older than the
+// retention period.
+func (s *MarkdownStore) pruneChangeLogs() error {
+	s.changeLogMu.Lock()
+	defer s.changeLogMu.Unlock()
+
+	cutoff := time.Now().UTC().Add(-s.opts.changeRetention())
+	if err := pruneLog[changeEntry](s.changesFile(), cutoff); err != nil {
+		return fmt.Errorf("prune change feed: %w", err)
+	}
+	if err := pruneLog[deletionEntry](s.deletionsFile(), cutoff); err != nil {
+		return fmt.Errorf("prune deletion log: %w", err)
+	}
+	return nil
+}
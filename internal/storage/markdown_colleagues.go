@@ -0,0 +1,75 @@
+// ABOUTME: Markdown backend support for colleague relationship suggestions.
+// ABOUTME: Derives company memberships from _relationships.yaml.
+package storage
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// SuggestColleagueRelationships proposes links between contacts that share
+// a company but have no relationship with each other yet.
+func (s *MarkdownStore) SuggestColleagueRelationships(limit int) ([]*ColleagueSuggestion, error) {
+	contacts, err := s.GetAllContacts()
+	if err != nil {
+		return nil, err
+	}
+	companies, err := s.GetAllCompanies()
+	if err != nil {
+		return nil, err
+	}
+	contactsByID := make(map[string]*models.Contact, len(contacts))
+	for _, c := range contacts {
+		contactsByID[c.ID.String()] = c
+	}
+	companiesByID := make(map[string]*models.Company, len(companies))
+	for _, co := range companies {
+		companiesByID[co.ID.String()] = co
+	}
+
+	entries, err := s.readRelationships()
+	if err != nil {
+		return nil, err
+	}
+
+	memberSeen := make(map[[2]uuid.UUID]bool)
+	var members []companyMember
+	for _, e := range entries {
+		contact, company := contactsByID[e.SourceID], companiesByID[e.TargetID]
+		if contact == nil || company == nil {
+			contact, company = contactsByID[e.TargetID], companiesByID[e.SourceID]
+		}
+		if contact == nil || company == nil {
+			continue
+		}
+		key := [2]uuid.UUID{company.ID, contact.ID}
+		if memberSeen[key] {
+			continue
+		}
+		memberSeen[key] = true
+		members = append(members, companyMember{
+			companyID:   company.ID,
+			companyName: company.Name,
+			contactID:   contact.ID,
+			contactName: contact.Name,
+		})
+	}
+
+	// Match the SQLite ordering so both backends suggest the same pairs first.
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if a.companyName != b.companyName {
+			return a.companyName < b.companyName
+		}
+		if a.companyID != b.companyID {
+			return a.companyID.String() < b.companyID.String()
+		}
+		if a.contactName != b.contactName {
+			return a.contactName < b.contactName
+		}
+		return a.contactID.String() < b.contactID.String()
+	})
+	return buildColleagueSuggestions(members, s.RelationshipExistsMany, limit)
+}
@@ -22,6 +22,8 @@ type companyFrontmatter struct {
 	Tags      []string       `yaml:"tags,omitempty"`
 	CreatedAt string         `yaml:"created_at"`
 	UpdatedAt string         `yaml:"updated_at"`
+	Version   int            `yaml:"version,omitempty"`
+	Source    string         `yaml:"source,omitempty"`
 }
 
 // companyToFrontmatter converts a models.Company to its YAML frontmatter representation.
@@ -32,8 +34,10 @@ func companyToFrontmatter(c *models.Company) companyFrontmatter {
 		Domain:    c.Domain,
 		Fields:    c.Fields,
 		Tags:      c.Tags,
-		CreatedAt: mdstore.FormatTime(c.CreatedAt),
-		UpdatedAt: mdstore.FormatTime(c.UpdatedAt),
+		CreatedAt: formatTime(c.CreatedAt),
+		UpdatedAt: formatTime(c.UpdatedAt),
+		Version:   c.Version,
+		Source:    c.Source,
 	}
 }
 
@@ -43,11 +47,11 @@ func frontmatterToCompany(fm companyFrontmatter) (*models.Company, error) {
 	if err != nil {
 		return nil, err
 	}
-	createdAt, err := mdstore.ParseTime(fm.CreatedAt)
+	createdAt, err := parseTime(fm.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
-	updatedAt, err := mdstore.ParseTime(fm.UpdatedAt)
+	updatedAt, err := parseTime(fm.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +71,8 @@ func frontmatterToCompany(fm companyFrontmatter) (*models.Company, error) {
 		Tags:      tags,
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
+		Version:   max(fm.Version, 1),
+		Source:    fm.Source,
 	}, nil
 }
 
@@ -121,6 +127,9 @@ func (s *MarkdownStore) findCompanyFile(id uuid.UUID) (string, *models.Company,
 
 // CreateCompany writes a new company as a markdown file.
 func (s *MarkdownStore) CreateCompany(company *models.Company) error {
+	if err := s.recordChange(EntityCompany, company.ID, ChangeCreate); err != nil {
+		return err
+	}
 	filename := slugForName(company.Name, company.ID.String(), s.companiesDir())
 	return s.writeCompany(company, filename)
 }
@@ -134,6 +143,7 @@ func (s *MarkdownStore) GetCompany(id uuid.UUID) (*models.Company, error) {
 	if c == nil {
 		return nil, ErrCompanyNotFound
 	}
+	s.recordAccess(EntityCompany, c.ID)
 	return c, nil
 }
 
@@ -164,14 +174,34 @@ func (s *MarkdownStore) GetCompanyByPrefix(prefix string) (*models.Company, erro
 	case 0:
 		return nil, ErrCompanyNotFound
 	case 1:
+		s.recordAccess(EntityCompany, matches[0].ID)
 		return matches[0], nil
 	default:
 		return nil, ErrAmbiguousPrefix
 	}
 }
 
-// ListCompanies returns companies matching the optional filter.
+// ListCompanies returns companies matching the optional filter criteria.
+// A missing or non-positive Limit uses the store's default limit, and larger
+// limits are clamped to its maximum; use GetAllCompanies to read every row.
 func (s *MarkdownStore) ListCompanies(filter *CompanyFilter) ([]*models.Company, error) {
+	f := CompanyFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	f.Limit = s.opts.listLimit(f.Limit)
+	return s.listCompanies(&f)
+}
+
+// GetAllCompanies returns every company with no limit applied. The result
+// is unbounded, so reserve it for callers that genuinely need the full set.
+func (s *MarkdownStore) GetAllCompanies() ([]*models.Company, error) {
+	return s.listCompanies(nil)
+}
+
+// listCompanies returns companies matching filter; a nil filter or
+// non-positive Limit returns all matches.
+func (s *MarkdownStore) listCompanies(filter *CompanyFilter) ([]*models.Company, error) {
 	entries, err := os.ReadDir(s.companiesDir())
 	if err != nil {
 		return nil, err
@@ -211,6 +241,9 @@ func companyMatchesFilter(c *models.Company, f *CompanyFilter) bool {
 			return false
 		}
 	}
+	if f.Source != "" && c.Source != f.Source {
+		return false
+	}
 	if f.Search != "" {
 		return companyMatchesSearch(c, f.Search)
 	}
@@ -234,8 +267,9 @@ func companyMatchesSearch(c *models.Company, query string) bool {
 	return false
 }
 
-// UpdateCompany updates an existing company. Returns ErrCompanyNotFound if
-// the company does not exist.
+// UpdateCompany updates an existing company and increments its Version. Returns
+// ErrCompanyNotFound if the company does not exist and ErrVersionConflict if
+// it has changed since company was read.
 func (s *MarkdownStore) UpdateCompany(company *models.Company) error {
 	path, existing, err := s.findCompanyFile(company.ID)
 	if err != nil {
@@ -244,12 +278,18 @@ func (s *MarkdownStore) UpdateCompany(company *models.Company) error {
 	if existing == nil {
 		return ErrCompanyNotFound
 	}
+	if company.Version != existing.Version {
+		return ErrVersionConflict
+	}
 	// Preserve original created_at
 	if company.CreatedAt.IsZero() {
 		company.CreatedAt = existing.CreatedAt
 	}
 	if company.UpdatedAt.IsZero() || company.UpdatedAt.Before(existing.UpdatedAt) {
-		company.UpdatedAt = time.Now()
+		company.UpdatedAt = time.Now().UTC()
+	}
+	if err := s.recordChange(EntityCompany, company.ID, ChangeUpdate); err != nil {
+		return err
 	}
 	// If name changed, we might need a new filename
 	filename := filepath.Base(path)
@@ -259,10 +299,16 @@ func (s *MarkdownStore) UpdateCompany(company *models.Company) error {
 		}
 		filename = slugForName(company.Name, company.ID.String(), s.companiesDir())
 	}
-	return s.writeCompany(company, filename)
+	company.Version++
+	if err := s.writeCompany(company, filename); err != nil {
+		company.Version--
+		return err
+	}
+	return nil
 }
 
-// DeleteCompany removes the markdown file for the given company ID.
+// DeleteCompany removes the markdown file for the given company ID along
+// with its relationships and attachments.
 func (s *MarkdownStore) DeleteCompany(id uuid.UUID) error {
 	path, c, err := s.findCompanyFile(id)
 	if err != nil {
@@ -271,5 +317,17 @@ func (s *MarkdownStore) DeleteCompany(id uuid.UUID) error {
 	if c == nil {
 		return ErrCompanyNotFound
 	}
-	return os.Remove(path)
+	if err := s.recordDeletion(EntityCompany, id); err != nil {
+		return err
+	}
+	if err := s.recordChange(EntityCompany, id, ChangeDelete); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := s.deleteDependents(id); err != nil {
+		return err
+	}
+	return s.forgetAccess(id)
 }
@@ -23,6 +23,10 @@ type contactFrontmatter struct {
 	Tags      []string       `yaml:"tags,omitempty"`
 	CreatedAt string         `yaml:"created_at"`
 	UpdatedAt string         `yaml:"updated_at"`
+
+	DoNotContact bool   `yaml:"do_not_contact,omitempty"`
+	Version      int    `yaml:"version,omitempty"`
+	Source       string `yaml:"source,omitempty"`
 }
 
 // contactToFrontmatter converts a models.Contact to its YAML frontmatter representation.
@@ -34,8 +38,12 @@ func contactToFrontmatter(c *models.Contact) contactFrontmatter {
 		Phone:     c.Phone,
 		Fields:    c.Fields,
 		Tags:      c.Tags,
-		CreatedAt: mdstore.FormatTime(c.CreatedAt),
-		UpdatedAt: mdstore.FormatTime(c.UpdatedAt),
+		CreatedAt: formatTime(c.CreatedAt),
+		UpdatedAt: formatTime(c.UpdatedAt),
+
+		DoNotContact: c.DoNotContact,
+		Version:      c.Version,
+		Source:       c.Source,
 	}
 }
 
@@ -45,11 +53,11 @@ func frontmatterToContact(fm contactFrontmatter) (*models.Contact, error) {
 	if err != nil {
 		return nil, err
 	}
-	createdAt, err := mdstore.ParseTime(fm.CreatedAt)
+	createdAt, err := parseTime(fm.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
-	updatedAt, err := mdstore.ParseTime(fm.UpdatedAt)
+	updatedAt, err := parseTime(fm.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +78,10 @@ func frontmatterToContact(fm contactFrontmatter) (*models.Contact, error) {
 		Tags:      tags,
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
+
+		DoNotContact: fm.DoNotContact,
+		Version:      max(fm.Version, 1),
+		Source:       fm.Source,
 	}, nil
 }
 
@@ -122,8 +134,18 @@ func (s *MarkdownStore) findContactFile(id uuid.UUID) (string, *models.Contact,
 	return "", nil, nil
 }
 
-// CreateContact writes a new contact as a markdown file.
+// CreateContact writes a new contact as a markdown file. With
+// Options.EnforceUniqueEmail set it returns ErrDuplicateEmail if another
+// contact already uses the email.
 func (s *MarkdownStore) CreateContact(contact *models.Contact) error {
+	if s.opts.EnforceUniqueEmail {
+		if err := checkUniqueEmail(s, contact); err != nil {
+			return err
+		}
+	}
+	if err := s.recordChange(EntityContact, contact.ID, ChangeCreate); err != nil {
+		return err
+	}
 	filename := slugForName(contact.Name, contact.ID.String(), s.contactsDir())
 	return s.writeContact(contact, filename)
 }
@@ -137,6 +159,7 @@ func (s *MarkdownStore) GetContact(id uuid.UUID) (*models.Contact, error) {
 	if c == nil {
 		return nil, ErrContactNotFound
 	}
+	s.recordAccess(EntityContact, c.ID)
 	return c, nil
 }
 
@@ -167,14 +190,34 @@ func (s *MarkdownStore) GetContactByPrefix(prefix string) (*models.Contact, erro
 	case 0:
 		return nil, ErrContactNotFound
 	case 1:
+		s.recordAccess(EntityContact, matches[0].ID)
 		return matches[0], nil
 	default:
 		return nil, ErrAmbiguousPrefix
 	}
 }
 
-// ListContacts returns contacts matching the optional filter.
+// ListContacts returns contacts matching the optional filter criteria.
+// A missing or non-positive Limit uses the store's default limit, and larger
+// limits are clamped to its maximum; use GetAllContacts to read every row.
 func (s *MarkdownStore) ListContacts(filter *ContactFilter) ([]*models.Contact, error) {
+	f := ContactFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	f.Limit = s.opts.listLimit(f.Limit)
+	return s.listContacts(&f)
+}
+
+// GetAllContacts returns every contact with no limit applied. The result
+// is unbounded, so reserve it for callers that genuinely need the full set.
+func (s *MarkdownStore) GetAllContacts() ([]*models.Contact, error) {
+	return s.listContacts(nil)
+}
+
+// listContacts returns contacts matching filter; a nil filter or
+// non-positive Limit returns all matches.
+func (s *MarkdownStore) listContacts(filter *ContactFilter) ([]*models.Contact, error) {
 	entries, err := os.ReadDir(s.contactsDir())
 	if err != nil {
 		return nil, err
@@ -214,6 +257,9 @@ func contactMatchesFilter(c *models.Contact, f *ContactFilter) bool {
 			return false
 		}
 	}
+	if f.Source != "" && c.Source != f.Source {
+		return false
+	}
 	if f.Search != "" {
 		return contactMatchesSearch(c, f.Search)
 	}
@@ -232,6 +278,9 @@ func contactMatchesSearch(c *models.Contact, query string) bool {
 	if strings.Contains(strings.ToLower(c.Phone), q) {
 		return true
 	}
+	if digits := phoneSearchDigits(query); digits != "" && strings.HasSuffix(NormalizePhone(c.Phone), digits) {
+		return true
+	}
 	for _, v := range c.Fields {
 		if strings.Contains(strings.ToLower(anyToString(v)), q) {
 			return true
@@ -240,8 +289,10 @@ func contactMatchesSearch(c *models.Contact, query string) bool {
 	return false
 }
 
-// UpdateContact updates an existing contact. Returns ErrContactNotFound if
-// the contact does not exist.
+// UpdateContact updates an existing contact and increments its Version. Returns
+// ErrContactNotFound if the contact does not exist and ErrVersionConflict if
+// it has changed since contact was read, and ErrDuplicateEmail under
+// Options.EnforceUniqueEmail if another contact already uses the email.
 func (s *MarkdownStore) UpdateContact(contact *models.Contact) error {
 	path, existing, err := s.findContactFile(contact.ID)
 	if err != nil {
@@ -250,12 +301,23 @@ func (s *MarkdownStore) UpdateContact(contact *models.Contact) error {
 	if existing == nil {
 		return ErrContactNotFound
 	}
+	if contact.Version != existing.Version {
+		return ErrVersionConflict
+	}
+	if s.opts.EnforceUniqueEmail {
+		if err := checkUniqueEmail(s, contact); err != nil {
+			return err
+		}
+	}
 	// Preserve original created_at
 	if contact.CreatedAt.IsZero() {
 		contact.CreatedAt = existing.CreatedAt
 	}
 	if contact.UpdatedAt.IsZero() || contact.UpdatedAt.Before(existing.UpdatedAt) {
-		contact.UpdatedAt = time.Now()
+		contact.UpdatedAt = time.Now().UTC()
+	}
+	if err := s.recordChange(EntityContact, contact.ID, ChangeUpdate); err != nil {
+		return err
 	}
 	// If name changed, we might need a new filename
 	filename := filepath.Base(path)
@@ -266,10 +328,16 @@ func (s *MarkdownStore) UpdateContact(contact *models.Contact) error {
 		}
 		filename = slugForName(contact.Name, contact.ID.String(), s.contactsDir())
 	}
-	return s.writeContact(contact, filename)
+	contact.Version++
+	if err := s.writeContact(contact, filename); err != nil {
+		contact.Version--
+		return err
+	}
+	return nil
 }
 
-// DeleteContact removes the markdown file for the given contact ID.
+// DeleteContact removes the markdown file for the given contact ID along
+// with its relationships and attachments.
 func (s *MarkdownStore) DeleteContact(id uuid.UUID) error {
 	path, c, err := s.findContactFile(id)
 	if err != nil {
@@ -278,5 +346,17 @@ func (s *MarkdownStore) DeleteContact(id uuid.UUID) error {
 	if c == nil {
 		return ErrContactNotFound
 	}
-	return os.Remove(path)
+	if err := s.recordDeletion(EntityContact, id); err != nil {
+		return err
+	}
+	if err := s.recordChange(EntityContact, id, ChangeDelete); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := s.deleteDependents(id); err != nil {
+		return err
+	}
+	return s.forgetAccess(id)
 }
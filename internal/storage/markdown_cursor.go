@@ -0,0 +1,59 @@
+// ABOUTME: Markdown cursor-paged scans of contacts and companies.
+// ABOUTME: Filters and sorts entity files in memory by (created_at, id) to match the SQLite ordering.
+package storage
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// ListContactsAfter returns up to limit contacts that sort after cursor in
+// (created_at, id) order, plus the cursor to pass for the next page. A
+// non-positive limit uses the store's default list limit. The scan is done
+// when a page comes back shorter than the limit.
+func (s *MarkdownStore) ListContactsAfter(cursor Cursor, limit int) ([]*models.Contact, Cursor, error) {
+	var contacts []*models.Contact
+	err := s.ForEachContact(func(c *models.Contact) error {
+		if cursor.after(c.CreatedAt, c.ID) {
+			contacts = append(contacts, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, cursor, err
+	}
+	slices.SortFunc(contacts, func(a, b *models.Contact) int {
+		return compareCursorKeys(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	contacts = contacts[:min(len(contacts), s.opts.listLimit(limit))]
+	next := nextCursor(cursor, len(contacts), func(i int) (time.Time, uuid.UUID) {
+		return contacts[i].CreatedAt, contacts[i].ID
+	})
+	return contacts, next, nil
+}
+
+// ListCompaniesAfter returns up to limit companies that sort after cursor
+// in (created_at, id) order, plus the cursor to pass for the next page.
+func (s *MarkdownStore) ListCompaniesAfter(cursor Cursor, limit int) ([]*models.Company, Cursor, error) {
+	var companies []*models.Company
+	err := s.ForEachCompany(func(c *models.Company) error {
+		if cursor.after(c.CreatedAt, c.ID) {
+			companies = append(companies, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, cursor, err
+	}
+	slices.SortFunc(companies, func(a, b *models.Company) int {
+		return compareCursorKeys(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	companies = companies[:min(len(companies), s.opts.listLimit(limit))]
+	next := nextCursor(cursor, len(companies), func(i int) (time.Time, uuid.UUID) {
+		return companies[i].CreatedAt, companies[i].ID
+	})
+	return companies, next, nil
+}
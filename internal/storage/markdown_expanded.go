@@ -0,0 +1,75 @@
+// ABOUTME: Markdown eager-loading of a contact with its companies, relationships, notes, and attachments.
+// ABOUTME: Resolves relationship counterparts against the contact and company files.
+package storage
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// GetContactExpanded returns a contact together with the companies it is
+// linked to, every relationship annotated with its counterpart's name, and
+// its notes and attachments.
+// Unlike GetContact it does not record access; callers that resolve the
+// contact first have already done so.
+func (s *MarkdownStore) GetContactExpanded(id uuid.UUID) (*ContactExpanded, error) {
+	_, contact, err := s.findContactFile(id)
+	if err != nil {
+		return nil, err
+	}
+	if contact == nil {
+		return nil, ErrContactNotFound
+	}
+
+	rels, err := s.ListRelationships(id)
+	if err != nil {
+		return nil, err
+	}
+	otherIDs := make([]uuid.UUID, 0, len(rels))
+	for _, r := range rels {
+		otherIDs = append(otherIDs, r.SourceID, r.TargetID)
+	}
+	contactsByID, err := s.GetContactsByIDs(otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	companiesByID, err := s.GetCompaniesByIDs(otherIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := &ContactExpanded{Contact: contact}
+	seen := make(map[uuid.UUID]bool)
+	for _, r := range rels {
+		e := &RelatedEntity{Relationship: r, OtherID: r.TargetID}
+		if r.TargetID == id {
+			e.OtherID = r.SourceID
+		}
+		if c, ok := contactsByID[e.OtherID]; ok {
+			e.OtherType, e.OtherName = EntityContact, c.Name
+		} else if co, ok := companiesByID[e.OtherID]; ok {
+			e.OtherType, e.OtherName = EntityCompany, co.Name
+			if !seen[co.ID] {
+				seen[co.ID] = true
+				expanded.Companies = append(expanded.Companies, co)
+			}
+		}
+		expanded.Relationships = append(expanded.Relationships, e)
+	}
+
+	sort.Slice(expanded.Companies, func(i, j int) bool {
+		return expanded.Companies[i].Name < expanded.Companies[j].Name
+	})
+	sort.Slice(expanded.Relationships, func(i, j int) bool {
+		return expanded.Relationships[i].Relationship.CreatedAt.Before(expanded.Relationships[j].Relationship.CreatedAt)
+	})
+
+	if expanded.Notes, err = s.ListContactNotes(id); err != nil {
+		return nil, err
+	}
+	if expanded.Attachments, err = s.ListAttachments(id); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
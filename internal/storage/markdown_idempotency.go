@@ -0,0 +1,125 @@
+// ABOUTME: Idempotency key storage for the markdown backend.
+// ABOUTME: Keeps key-to-entity mappings in _idempotency.yaml until they expire.
+package storage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/mdstore"
+)
+
+// idempotencyEntry is the YAML representation of one idempotency key.
+type idempotencyEntry struct {
+	EntityType string `yaml:"entity_type"`
+	Key        string `yaml:"key"`
+	EntityID   string `yaml:"entity_id"`
+	CreatedAt  string `yaml:"created_at"`
+}
+
+// readIdempotencyKeys reads all stored idempotency keys.
+func (s *MarkdownStore) readIdempotencyKeys() ([]idempotencyEntry, error) {
+	var entries []idempotencyEntry
+	if err := mdstore.ReadYAML(s.idempotencyFile(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// expired reports whether e is older than ttl, treating unparseable
+// timestamps as expired.
+func (e idempotencyEntry) expired(ttl time.Duration) bool {
+	createdAt, err := parseTime(e.CreatedAt)
+	return err != nil || time.Since(createdAt) > ttl
+}
+
+// lookupIdempotencyKey returns the ID of the entity previously created with
+// key, if the key is known and has not expired.
+func (s *MarkdownStore) lookupIdempotencyKey(entityType, key string) (uuid.UUID, bool, error) {
+	entries, err := s.readIdempotencyKeys()
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	for _, e := range entries {
+		if e.EntityType != entityType || e.Key != key {
+			continue
+		}
+		if e.expired(s.opts.idempotencyTTL()) {
+			return uuid.Nil, false, nil
+		}
+		id, err := uuid.Parse(e.EntityID)
+		if err != nil {
+			return uuid.Nil, false, err
+		}
+		return id, true, nil
+	}
+	return uuid.Nil, false, nil
+}
+
+// saveIdempotencyKey records that key created the entity with the given ID,
+// replacing any earlier mapping and pruning expired keys. Callers hold
+// idempotencyMu.
+func (s *MarkdownStore) saveIdempotencyKey(entityType, key string, id uuid.UUID) error {
+	entries, err := s.readIdempotencyKeys()
+	if err != nil {
+		return err
+	}
+
+	ttl := s.opts.idempotencyTTL()
+	kept := make([]idempotencyEntry, 0, len(entries)+1)
+	for _, e := range entries {
+		if e.expired(ttl) || (e.EntityType == entityType && e.Key == key) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	kept = append(kept, idempotencyEntry{
+		EntityType: entityType,
+		Key:        key,
+		EntityID:   id.String(),
+		CreatedAt:  formatTime(time.Now()),
+	})
+	return mdstore.WriteYAML(s.idempotencyFile(), kept)
+}
+
+// ClaimIdempotencyKey reserves key for the entity about to be created as id.
+// Claims are serialized within the process, so of two concurrent claims
+// exactly one succeeds. If an unexpired claim already holds the key, it
+// returns that claim's entity ID and false.
+func (s *MarkdownStore) ClaimIdempotencyKey(entityType, key string, id uuid.UUID) (uuid.UUID, bool, error) {
+	s.idempotencyMu.Lock()
+	defer s.idempotencyMu.Unlock()
+
+	held, ok, err := s.lookupIdempotencyKey(entityType, key)
+	if err != nil || ok {
+		return held, false, err
+	}
+	if err := s.saveIdempotencyKey(entityType, key, id); err != nil {
+		return uuid.Nil, false, err
+	}
+	return id, true, nil
+}
+
+// ReleaseIdempotencyKey drops key's claim if it is still held for id, so a
+// failed create can be retried or a deleted entity created again.
+func (s *MarkdownStore) ReleaseIdempotencyKey(entityType, key string, id uuid.UUID) error {
+	s.idempotencyMu.Lock()
+	defer s.idempotencyMu.Unlock()
+
+	entries, err := s.readIdempotencyKeys()
+	if err != nil {
+		return err
+	}
+	idStr := id.String()
+	kept := make([]idempotencyEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.EntityType == entityType && e.Key == key && e.EntityID == idStr {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	return mdstore.WriteYAML(s.idempotencyFile(), kept)
+}
@@ -0,0 +1,147 @@
+// ABOUTME: Markdown integrity check for references to deleted contacts and companies.
+// ABOUTME: Compares relationship, attachment, and note entries against the entity files on disk and flags duplicate relationships.
+package storage
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// CheckIntegrity reports relationships with a missing endpoint, attachments
+// and notes whose owning entity is missing, and duplicate relationships,
+// such as those left by hand edits to the relationships file.
+func (s *MarkdownStore) CheckIntegrity() (*IntegrityReport, error) {
+	known, err := s.entityIDSet()
+	if err != nil {
+		return nil, err
+	}
+
+	rels, err := s.readRelationships()
+	if err != nil {
+		return nil, err
+	}
+	report := &IntegrityReport{
+		DanglingRelationships:  []uuid.UUID{},
+		OrphanedAttachments:    []uuid.UUID{},
+		DuplicateRelationships: duplicateRelationshipIDs(rels),
+	}
+	for _, e := range rels {
+		if known[e.SourceID] && known[e.TargetID] {
+			continue
+		}
+		if id, err := uuid.Parse(e.ID); err == nil {
+			report.DanglingRelationships = append(report.DanglingRelationships, id)
+		}
+	}
+
+	atts, err := s.readAttachments()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range atts {
+		if known[e.EntityID] {
+			continue
+		}
+		if id, err := uuid.Parse(e.ID); err == nil {
+			report.OrphanedAttachments = append(report.OrphanedAttachments, id)
+		}
+	}
+
+	if report.OrphanedCompanyNotes, err = s.orphanedNotes(companyNotes, known); err != nil {
+		return nil, err
+	}
+	if report.OrphanedContactNotes, err = s.orphanedNotes(contactNotes, known); err != nil {
+		return nil, err
+	}
+
+	sortIDs(report.DanglingRelationships)
+	sortIDs(report.OrphanedAttachments)
+	sortIDs(report.DuplicateRelationships)
+	return report, nil
+}
+
+// orphanedNotes returns the notes in k's file whose owner is not in known,
+// sorted by ID.
+func (s *MarkdownStore) orphanedNotes(k noteKind, known map[string]bool) ([]uuid.UUID, error) {
+	entries, err := s.readNotes(k)
+	if err != nil {
+		return nil, err
+	}
+	ids := []uuid.UUID{}
+	for _, e := range entries {
+		if known[k.owner(e)] {
+			continue
+		}
+		if id, err := uuid.Parse(e.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sortIDs(ids)
+	return ids, nil
+}
+
+// duplicateRelationshipIDs returns every relationship but the newest of each
+// type between the same pair of entities, in either direction.
+func duplicateRelationshipIDs(rels []relationshipEntry) []uuid.UUID {
+	type groupKey struct{ a, b, typ string }
+	newest := make(map[groupKey]relationshipEntry)
+	dups := []uuid.UUID{}
+	for _, e := range rels {
+		a, b := e.SourceID, e.TargetID
+		if b < a {
+			a, b = b, a
+		}
+		key := groupKey{a, b, e.Type}
+		kept, seen := newest[key]
+		if !seen {
+			newest[key] = e
+			continue
+		}
+		drop := e
+		if newerEntry(e, kept) {
+			newest[key], drop = e, kept
+		}
+		if id, err := uuid.Parse(drop.ID); err == nil {
+			dups = append(dups, id)
+		}
+	}
+	return dups
+}
+
+// newerEntry reports whether a was created after b. Unparseable times sort
+// as oldest, so a well-formed entry is the one kept.
+func newerEntry(a, b relationshipEntry) bool {
+	at, aErr := parseTime(a.CreatedAt)
+	bt, bErr := parseTime(b.CreatedAt)
+	if aErr != nil {
+		return false
+	}
+	return bErr != nil || at.After(bt)
+}
+
+// entityIDSet returns the IDs of every contact and company, as strings.
+func (s *MarkdownStore) entityIDSet() (map[string]bool, error) {
+	known := make(map[string]bool)
+	err := s.ForEachContact(func(c *models.Contact) error {
+		known[c.ID.String()] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.ForEachCompany(func(c *models.Company) error {
+		known[c.ID.String()] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return known, nil
+}
+
+// sortIDs orders ids by their string form, matching SQLite's ORDER BY id.
+func sortIDs(ids []uuid.UUID) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+}
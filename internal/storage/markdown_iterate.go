@@ -0,0 +1,54 @@
+// ABOUTME: Markdown streaming iteration over contacts and companies.
+// ABOUTME: Reads one entity file at a time and hands it to a callback.
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+// ForEachContact calls fn for every contact, reading one file at a time and
+// stopping at the first error fn returns. Unreadable files are skipped, as
+// in ListContacts.
+func (s *MarkdownStore) ForEachContact(fn func(*models.Contact) error) error {
+	return forEachEntityFile(s.contactsDir(), func(path string) error {
+		c, err := readContactFile(path)
+		if err != nil {
+			return nil //nolint:nilerr // skip unreadable files, matching ListContacts
+		}
+		return fn(c)
+	})
+}
+
+// ForEachCompany calls fn for every company, reading one file at a time and
+// stopping at the first error fn returns. Unreadable files are skipped, as
+// in ListCompanies.
+func (s *MarkdownStore) ForEachCompany(fn func(*models.Company) error) error {
+	return forEachEntityFile(s.companiesDir(), func(path string) error {
+		c, err := readCompanyFile(path)
+		if err != nil {
+			return nil //nolint:nilerr // skip unreadable files, matching ListCompanies
+		}
+		return fn(c)
+	})
+}
+
+// forEachEntityFile calls fn with the path of each .md file in dir.
+func forEachEntityFile(dir string, fn func(path string) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		if err := fn(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// ABOUTME: Append-only YAML logs for the markdown backend's change feed and deletion log.
+// ABOUTME: Each entry is one "- {json}" line, so writes append instead of rewriting the file.
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/harperreed/mdstore"
+)
+
+// logEntry is an entry type kept in an append-only log.
+type logEntry interface {
+	changeEntry | deletionEntry
+	loggedAt() string
+}
+
+// logPeekSize is how much of a log's head or tail is read to find its first
+// or last entry. Entries are well under this size.
+const logPeekSize = 4096
+
+// appendLogEntry appends e to the log at path as one line. A JSON object is
+// a YAML flow mapping, so the file stays a valid YAML list that ReadYAML
+// reads whole, including files written before entries were appended.
+func appendLogEntry[T logEntry](path string, e T) error {
+	line, err := logLine(e)
+	if err != nil {
+		return err
+	}
+	//nolint:gosec // path is a fixed file under the store's data directory
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// logLine renders e as a "- {json}" line.
+func logLine[T logEntry](e T) ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte("- "), data...), '\n'), nil
+}
+
+// parseLogLine decodes a line written by appendLogEntry. It reports false
+// for anything else, such as part of a block-style entry.
+func parseLogLine[T logEntry](line []byte) (T, bool) {
+	var e T
+	line = bytes.TrimSpace(line)
+	if !bytes.HasPrefix(line, []byte("- {")) {
+		return e, false
+	}
+	if err := json.Unmarshal(line[2:], &e); err != nil {
+		return e, false
+	}
+	return e, true
+}
+
+// lastLogEntry returns the newest entry in the log at path, reading only
+// the file's tail when it ends in an appended line. It reports false for a
+// missing or empty log.
+func lastLogEntry[T logEntry](path string) (T, bool, error) {
+	var zero T
+	tail, err := readLogEdge(path, true)
+	if err != nil || len(tail) == 0 {
+		return zero, false, err
+	}
+	tail = bytes.TrimRight(tail, "\n")
+	if e, ok := parseLogLine[T](tail[bytes.LastIndexByte(tail, '\n')+1:]); ok {
+		return e, true, nil
+	}
+	entries, err := readLog[T](path)
+	if err != nil || len(entries) == 0 {
+		return zero, false, err
+	}
+	return entries[len(entries)-1], true, nil
+}
+
+// readLog reads every entry in the log at path; a missing log is empty.
+func readLog[T logEntry](path string) ([]T, error) {
+	var entries []T
+	if err := mdstore.ReadYAML(path, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// pruneLog drops entries logged before cutoff from the log at path,
+// rewriting it in line form. Entries are appended oldest first, so when the
+// first entry is recent enough only the file's head is read. The newest
+// entry is always kept so change sequence numbers keep increasing.
+func pruneLog[T logEntry](path string, cutoff time.Time) error {
+	head, err := readLogEdge(path, false)
+	if err != nil || len(head) == 0 {
+		return err
+	}
+	first := head
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		first = head[:i]
+	}
+	if e, ok := parseLogLine[T](first); ok && !loggedBefore(e, cutoff) {
+		return nil
+	}
+
+	entries, err := readLog[T](path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for i, e := range entries {
+		if i < len(entries)-1 && loggedBefore(e, cutoff) {
+			continue
+		}
+		line, err := logLine(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	}
+	return mdstore.AtomicWrite(path, buf.Bytes())
+}
+
+// loggedBefore reports whether e was logged before cutoff. Entries with an
+// unreadable time are kept.
+func loggedBefore[T logEntry](e T, cutoff time.Time) bool {
+	t, err := parseTime(e.loggedAt())
+	return err == nil && t.Before(cutoff)
+}
+
+// readLogEdge returns up to logPeekSize bytes from the start of the log at
+// path, or from its end when tail is set. A missing log reads as empty.
+func readLogEdge(path string, tail bool) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // path is a fixed file under the store's data directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	n := min(info.Size(), logPeekSize)
+	offset := int64(0)
+	if tail {
+		offset = info.Size() - n
+	}
+	buf := make([]byte, n)
+	if _, err := f.ReadAt(buf, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf, nil
+}
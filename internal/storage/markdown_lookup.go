@@ -0,0 +1,164 @@
+// ABOUTME: Markdown lookups of contacts by email and companies by domain or name.
+// ABOUTME: Scans entity files, returning the oldest record when several match.
+package storage
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// GetContactByEmail returns the contact with the given email address,
+// ignoring case and surrounding whitespace. Returns ErrContactNotFound if
+// none matches.
+func (s *MarkdownStore) GetContactByEmail(email string) (*models.Contact, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return nil, ErrContactNotFound
+	}
+	var match *models.Contact
+	err := s.ForEachContact(func(c *models.Contact) error {
+		if NormalizeEmail(c.Email) == email && (match == nil || c.CreatedAt.Before(match.CreatedAt)) {
+			match = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, ErrContactNotFound
+	}
+	return match, nil
+}
+
+// ListContactsByEmailDomain returns every contact whose email address is at
+// domain, newest first. The domain is normalized, and only exact domain
+// matches count, so "acme.com" does not match "eng.acme.com".
+func (s *MarkdownStore) ListContactsByEmailDomain(domain string) ([]*models.Contact, error) {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return nil, nil
+	}
+	var contacts []*models.Contact
+	err := s.ForEachContact(func(c *models.Contact) error {
+		if EmailDomain(c.Email) == domain {
+			contacts = append(contacts, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(contacts, func(a, b *models.Contact) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return contacts, nil
+}
+
+// GetCompanyByDomain returns the company whose domain matches after
+// normalization (case, scheme, "www." and path are ignored). Returns
+// ErrCompanyNotFound if none matches.
+func (s *MarkdownStore) GetCompanyByDomain(domain string) (*models.Company, error) {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return nil, ErrCompanyNotFound
+	}
+	var match *models.Company
+	err := s.ForEachCompany(func(c *models.Company) error {
+		if NormalizeDomain(c.Domain) == domain && (match == nil || c.CreatedAt.Before(match.CreatedAt)) {
+			match = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, ErrCompanyNotFound
+	}
+	return match, nil
+}
+
+// GetOrCreateCompany returns the oldest company named name, creating one if
+// none exists. Surrounding whitespace is ignored; otherwise the name must
+// match exactly. The bool reports whether a company was created.
+func (s *MarkdownStore) GetOrCreateCompany(name string) (*models.Company, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, ErrNameRequired
+	}
+	var match *models.Company
+	err := s.ForEachCompany(func(c *models.Company) error {
+		if strings.TrimSpace(c.Name) == name && (match == nil || c.CreatedAt.Before(match.CreatedAt)) {
+			match = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if match != nil {
+		return match, false, nil
+	}
+
+	c := models.NewCompany(name)
+	if err := s.CreateCompany(c); err != nil {
+		return nil, false, err
+	}
+	return c, true, nil
+}
+
+// GetOrCreateContact returns the contact with email if there is one, else
+// the oldest contact named name (surrounding whitespace ignored), else a
+// newly created contact. A new contact is linked to companyID with a
+// "works_at" relationship when companyID is non-nil; existing contacts are
+// returned unchanged. The bool reports whether a contact was created.
+func (s *MarkdownStore) GetOrCreateContact(name, email string, companyID *uuid.UUID) (*models.Contact, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, ErrNameRequired
+	}
+	c, err := s.GetContactByEmail(email)
+	if err == nil {
+		return c, false, nil
+	}
+	if !errors.Is(err, ErrContactNotFound) {
+		return nil, false, err
+	}
+	var match *models.Contact
+	err = s.ForEachContact(func(c *models.Contact) error {
+		if strings.TrimSpace(c.Name) == name && (match == nil || c.CreatedAt.Before(match.CreatedAt)) {
+			match = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if match != nil {
+		return match, false, nil
+	}
+
+	if companyID != nil {
+		if _, err := s.GetCompany(*companyID); err != nil {
+			return nil, false, err
+		}
+	}
+	c = models.NewContact(name)
+	c.Email = strings.TrimSpace(email)
+	if err := s.CreateContact(c); err != nil {
+		return nil, false, err
+	}
+	if companyID != nil {
+		if err := s.CreateRelationship(models.NewRelationship(c.ID, *companyID, worksAtType, "")); err != nil {
+			// Undo the create so a failed link leaves no unlinked contact.
+			_ = s.DeleteContact(c.ID)
+			return nil, false, err
+		}
+	}
+	return c, true, nil
+}
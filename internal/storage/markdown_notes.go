@@ -0,0 +1,182 @@
+// ABOUTME: Markdown storage for the running notes history kept on contacts and companies.
+// ABOUTME: Keeps each entity type's notes as entries in _contact_notes.yaml or _company_notes.yaml.
+package storage
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/mdstore"
+)
+
+// noteEntry is the YAML representation of a note. Exactly one owner field
+// is set, matching the file the entry lives in.
+type noteEntry struct {
+	ID        string `yaml:"id"`
+	CompanyID string `yaml:"company_id,omitempty"`
+	ContactID string `yaml:"contact_id,omitempty"`
+	Content   string `yaml:"content"`
+	CreatedAt string `yaml:"created_at"`
+}
+
+// owner returns the ID of the entity e belongs to.
+func (k noteKind) owner(e noteEntry) string {
+	if k.entityType == EntityCompany {
+		return e.CompanyID
+	}
+	return e.ContactID
+}
+
+// entry converts n to its YAML representation in k's file.
+func (k noteKind) entry(n note) noteEntry {
+	e := noteEntry{ID: n.ID.String(), Content: n.Content, CreatedAt: formatTime(n.CreatedAt)}
+	if k.entityType == EntityCompany {
+		e.CompanyID = n.OwnerID.String()
+	} else {
+		e.ContactID = n.OwnerID.String()
+	}
+	return e
+}
+
+// AddCompanyNote appends a note to a company's history. It returns
+// ErrNoteContentRequired for blank content and ErrCompanyNotFound if the
+// company does not exist.
+func (s *MarkdownStore) AddCompanyNote(n *models.CompanyNote) error {
+	return s.addNote(companyNotes, fromCompanyNote(n))
+}
+
+// ListCompanyNotes returns a company's notes, oldest first.
+func (s *MarkdownStore) ListCompanyNotes(companyID uuid.UUID) ([]*models.CompanyNote, error) {
+	notes, err := s.listNotes(companyNotes, companyID)
+	return toCompanyNotes(notes), err
+}
+
+// DeleteCompanyNote removes a note by UUID, returning ErrNoteNotFound if it
+// does not exist.
+func (s *MarkdownStore) DeleteCompanyNote(id uuid.UUID) error {
+	return s.deleteNote(companyNotes, id)
+}
+
+// AddContactNote appends a note to a contact's history. It returns
+// ErrNoteContentRequired for blank content and ErrContactNotFound if the
+// contact does not exist.
+func (s *MarkdownStore) AddContactNote(n *models.ContactNote) error {
+	return s.addNote(contactNotes, fromContactNote(n))
+}
+
+// ListContactNotes returns a contact's notes, oldest first.
+func (s *MarkdownStore) ListContactNotes(contactID uuid.UUID) ([]*models.ContactNote, error) {
+	notes, err := s.listNotes(contactNotes, contactID)
+	return toContactNotes(notes), err
+}
+
+// DeleteContactNote removes a note by UUID, returning ErrNoteNotFound if it
+// does not exist.
+func (s *MarkdownStore) DeleteContactNote(id uuid.UUID) error {
+	return s.deleteNote(contactNotes, id)
+}
+
+// readNotes reads every entry in k's notes file.
+func (s *MarkdownStore) readNotes(k noteKind) ([]noteEntry, error) {
+	var entries []noteEntry
+	if err := mdstore.ReadYAML(s.notesFile(k), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// addNote appends n to k's notes file after checking its owner exists.
+func (s *MarkdownStore) addNote(k noteKind, n note) error {
+	if err := validateNoteContent(n.Content); err != nil {
+		return err
+	}
+	ok, err := s.noteOwnerExists(k, n.OwnerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return k.notFound
+	}
+	return mdstore.AppendYAML(s.notesFile(k), k.entry(n))
+}
+
+// noteOwnerExists reports whether the contact or company a note of kind k
+// would belong to exists.
+func (s *MarkdownStore) noteOwnerExists(k noteKind, id uuid.UUID) (bool, error) {
+	if k.entityType == EntityCompany {
+		_, c, err := s.findCompanyFile(id)
+		return c != nil, err
+	}
+	_, c, err := s.findContactFile(id)
+	return c != nil, err
+}
+
+// listNotes returns k's notes for ownerID, oldest first.
+func (s *MarkdownStore) listNotes(k noteKind, ownerID uuid.UUID) ([]note, error) {
+	entries, err := s.readNotes(k)
+	if err != nil {
+		return nil, err
+	}
+	idStr := ownerID.String()
+	var notes []note
+	for _, e := range entries {
+		if k.owner(e) != idStr {
+			continue
+		}
+		id, err := uuid.Parse(e.ID)
+		if err != nil {
+			continue
+		}
+		createdAt, err := parseTime(e.CreatedAt)
+		if err != nil {
+			continue
+		}
+		notes = append(notes, note{ID: id, OwnerID: ownerID, Content: e.Content, CreatedAt: createdAt})
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+	})
+	return notes, nil
+}
+
+// deleteNote removes the note id from k's notes file, returning
+// ErrNoteNotFound if it does not exist.
+func (s *MarkdownStore) deleteNote(k noteKind, id uuid.UUID) error {
+	idStr := id.String()
+	removed, err := s.removeNotes(k, func(e noteEntry) bool { return e.ID == idStr })
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
+// deleteNotesFor removes every note in k's file recorded against ownerID.
+func (s *MarkdownStore) deleteNotesFor(k noteKind, ownerID uuid.UUID) error {
+	idStr := ownerID.String()
+	_, err := s.removeNotes(k, func(e noteEntry) bool { return k.owner(e) == idStr })
+	return err
+}
+
+// removeNotes rewrites k's notes file without the entries drop matches and
+// reports how many were removed.
+func (s *MarkdownStore) removeNotes(k noteKind, drop func(noteEntry) bool) (int, error) {
+	entries, err := s.readNotes(k)
+	if err != nil {
+		return 0, err
+	}
+	remaining := make([]noteEntry, 0, len(entries))
+	for _, e := range entries {
+		if !drop(e) {
+			remaining = append(remaining, e)
+		}
+	}
+	removed := len(entries) - len(remaining)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, mdstore.WriteYAML(s.notesFile(k), remaining)
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Orphan detection for the markdown storage backend.
+// ABOUTME: Cross-references relationship entries against contact and company files.
+package storage
+
+import (
+	"github.com/harperreed/crm/internal/models"
+)
+
+// ListContactsWithoutCompany returns contacts that have no relationship,
+// in either direction, to any existing company.
+func (s *MarkdownStore) ListContactsWithoutCompany() ([]*models.Contact, error) {
+	contacts, err := s.GetAllContacts()
+	if err != nil {
+		return nil, err
+	}
+	companies, err := s.GetAllCompanies()
+	if err != nil {
+		return nil, err
+	}
+	companyIDs := make(map[string]bool, len(companies))
+	for _, co := range companies {
+		companyIDs[co.ID.String()] = true
+	}
+	linked, err := s.linkedEntityIDs(companyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*models.Contact
+	for _, c := range contacts {
+		if !linked[c.ID.String()] {
+			results = append(results, c)
+		}
+	}
+	return results, nil
+}
+
+// ListEmptyCompanies returns companies that have no relationship, in either
+// direction, to any existing contact.
+func (s *MarkdownStore) ListEmptyCompanies() ([]*models.Company, error) {
+	companies, err := s.GetAllCompanies()
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := s.GetAllContacts()
+	if err != nil {
+		return nil, err
+	}
+	contactIDs := make(map[string]bool, len(contacts))
+	for _, c := range contacts {
+		contactIDs[c.ID.String()] = true
+	}
+	linked, err := s.linkedEntityIDs(contactIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*models.Company
+	for _, co := range companies {
+		if !linked[co.ID.String()] {
+			results = append(results, co)
+		}
+	}
+	return results, nil
+}
+
+// linkedEntityIDs returns the set of entity IDs that share a relationship
+// with any ID in counterparts.
+func (s *MarkdownStore) linkedEntityIDs(counterparts map[string]bool) (map[string]bool, error) {
+	entries, err := s.readRelationships()
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[string]bool)
+	for _, e := range entries {
+		if counterparts[e.TargetID] {
+			linked[e.SourceID] = true
+		}
+		if counterparts[e.SourceID] {
+			linked[e.TargetID] = true
+		}
+	}
+	return linked, nil
+}
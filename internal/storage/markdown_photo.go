@@ -0,0 +1,91 @@
+// ABOUTME: Markdown storage for contact photos.
+// ABOUTME: Writes image bytes to photos/<id> and records content types in _photos.yaml.
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/mdstore"
+)
+
+// readPhotoTypes reads the contact ID to content type map.
+func (s *MarkdownStore) readPhotoTypes() (map[string]string, error) {
+	types := map[string]string{}
+	if err := mdstore.ReadYAML(s.photoTypesFile(), &types); err != nil {
+		return nil, err
+	}
+	if types == nil {
+		types = map[string]string{}
+	}
+	return types, nil
+}
+
+// SetContactPhoto stores data as the contact's photo, replacing any
+// previous one. Empty data removes the photo. The contact file itself is
+// left unchanged.
+func (s *MarkdownStore) SetContactPhoto(id uuid.UUID, contentType string, data []byte) error {
+	if err := validatePhoto(contentType, data); err != nil {
+		return err
+	}
+	if _, err := s.GetContact(id); err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return s.deleteContactPhoto(id)
+	}
+
+	if err := os.MkdirAll(s.photosDir(), 0o750); err != nil {
+		return err
+	}
+	if err := mdstore.AtomicWrite(filepath.Join(s.photosDir(), id.String()), data); err != nil {
+		return err
+	}
+	types, err := s.readPhotoTypes()
+	if err != nil {
+		return err
+	}
+	types[id.String()] = contentType
+	return mdstore.WriteYAML(s.photoTypesFile(), types)
+}
+
+// GetContactPhoto returns the contact's photo and its content type. It
+// returns ErrContactNotFound for an unknown contact and ErrPhotoNotFound
+// when the contact has no photo.
+func (s *MarkdownStore) GetContactPhoto(id uuid.UUID) ([]byte, string, error) {
+	if _, err := s.GetContact(id); err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(filepath.Join(s.photosDir(), id.String())) //nolint:gosec // path is built from a parsed UUID
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", ErrPhotoNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	types, err := s.readPhotoTypes()
+	if err != nil {
+		return nil, "", err
+	}
+	return data, types[id.String()], nil
+}
+
+// deleteContactPhoto removes the photo stored for id, if any.
+func (s *MarkdownStore) deleteContactPhoto(id uuid.UUID) error {
+	err := os.Remove(filepath.Join(s.photosDir(), id.String()))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	types, err := s.readPhotoTypes()
+	if err != nil {
+		return err
+	}
+	if _, ok := types[id.String()]; !ok {
+		return nil
+	}
+	delete(types, id.String())
+	return mdstore.WriteYAML(s.photoTypesFile(), types)
+}
@@ -0,0 +1,92 @@
+// ABOUTME: Markdown bulk move of a company's contacts to another company.
+// ABOUTME: Repoints contact-company relationships with a single rewrite of _relationships.yaml.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// ReassignCompanyContacts moves every contact related to company from over
+// to company to, keeping each relationship's type, context, and direction.
+// A relationship whose equivalent already joins the contact to to is
+// removed instead of duplicated. It returns the number of contacts moved,
+// or ErrCompanyNotFound if either company does not exist.
+func (s *MarkdownStore) ReassignCompanyContacts(from, to uuid.UUID) (int, error) {
+	for _, id := range []uuid.UUID{from, to} {
+		_, c, err := s.findCompanyFile(id)
+		if err != nil {
+			return 0, err
+		}
+		if c == nil {
+			return 0, fmt.Errorf("%w: %s", ErrCompanyNotFound, id)
+		}
+	}
+	if from == to {
+		return 0, nil
+	}
+
+	entries, err := s.readRelationships()
+	if err != nil {
+		return 0, err
+	}
+	fromStr, toStr := from.String(), to.String()
+	var others []uuid.UUID
+	for _, e := range entries {
+		if other, ok := otherEndpoint(e, fromStr); ok {
+			others = append(others, other)
+		}
+	}
+	contacts, err := s.GetContactsByIDs(others)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := make(map[uuid.UUID]bool)
+	kept := entries[:0:0]
+	for _, e := range entries {
+		other, ok := otherEndpoint(e, fromStr)
+		if !ok || contacts[other] == nil {
+			kept = append(kept, e)
+			continue
+		}
+		moved[other] = true
+		target := &models.Relationship{SourceID: other, TargetID: to, Type: e.Type}
+		if findRelationshipEntry(entries, target) >= 0 {
+			continue
+		}
+		if e.SourceID == fromStr {
+			e.SourceID = toStr
+		} else {
+			e.TargetID = toStr
+		}
+		kept = append(kept, e)
+	}
+	if len(moved) == 0 {
+		return 0, nil
+	}
+	if err := s.writeRelationships(kept); err != nil {
+		return 0, err
+	}
+	return len(moved), nil
+}
+
+// otherEndpoint returns the endpoint of e that is not id, if e touches id.
+func otherEndpoint(e relationshipEntry, id string) (uuid.UUID, bool) {
+	var other string
+	switch id {
+	case e.SourceID:
+		other = e.TargetID
+	case e.TargetID:
+		other = e.SourceID
+	default:
+		return uuid.Nil, false
+	}
+	parsed, err := uuid.Parse(other)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return parsed, true
+}
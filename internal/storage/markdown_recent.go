@@ -0,0 +1,103 @@
+// ABOUTME: Access tracking for the markdown storage backend's "recently viewed" list.
+// ABOUTME: Keeps a bounded, newest-first list of reads in _recent.yaml when enabled.
+package storage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/mdstore"
+)
+
+// recentEntry is the YAML representation of one access record.
+type recentEntry struct {
+	EntityType   string `yaml:"entity_type"`
+	EntityID     string `yaml:"entity_id"`
+	LastAccessed string `yaml:"last_accessed"`
+	Count        int    `yaml:"count"`
+}
+
+// readRecent reads all access records.
+func (s *MarkdownStore) readRecent() ([]recentEntry, error) {
+	var entries []recentEntry
+	if err := mdstore.ReadYAML(s.recentFile(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// recordAccess notes a read of the given entity when access tracking is
+// enabled. Tracking is best-effort: failures never fail the read itself.
+func (s *MarkdownStore) recordAccess(entityType string, id uuid.UUID) {
+	if !s.opts.TrackAccess {
+		return
+	}
+	entries, err := s.readRecent()
+	if err != nil {
+		return
+	}
+
+	idStr := id.String()
+	entry := recentEntry{EntityType: entityType, EntityID: idStr}
+	kept := make([]recentEntry, 0, len(entries)+1)
+	for _, e := range entries {
+		if e.EntityType == entityType && e.EntityID == idStr {
+			entry.Count = e.Count
+			continue
+		}
+		kept = append(kept, e)
+	}
+	entry.Count++
+	entry.LastAccessed = formatTime(time.Now())
+
+	// Entries are kept newest first, so the new access goes to the front.
+	kept = append([]recentEntry{entry}, kept...)
+	if len(kept) > maxRecentAccess {
+		kept = kept[:maxRecentAccess]
+	}
+	_ = mdstore.WriteYAML(s.recentFile(), kept)
+}
+
+// forgetAccess removes any access record for a deleted entity.
+func (s *MarkdownStore) forgetAccess(id uuid.UUID) error {
+	entries, err := s.readRecent()
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	idStr := id.String()
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.EntityID != idStr {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	return mdstore.WriteYAML(s.recentFile(), kept)
+}
+
+// ListRecent returns the IDs of the most recently read entities, newest first.
+// An empty entityType includes both contacts and companies.
+func (s *MarkdownStore) ListRecent(entityType string, limit int) ([]uuid.UUID, error) {
+	entries, err := s.readRecent()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, e := range entries {
+		if entityType != "" && e.EntityType != entityType {
+			continue
+		}
+		id, err := uuid.Parse(e.EntityID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+		if limit > 0 && len(ids) >= limit {
+			break
+		}
+	}
+	return ids, nil
+}
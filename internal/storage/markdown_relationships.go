@@ -3,7 +3,9 @@
 package storage
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/crm/internal/models"
@@ -28,7 +30,7 @@ func relationshipToEntry(r *models.Relationship) relationshipEntry {
 		TargetID:  r.TargetID.String(),
 		Type:      r.Type,
 		Context:   r.Context,
-		CreatedAt: mdstore.FormatTime(r.CreatedAt),
+		CreatedAt: formatTime(r.CreatedAt),
 	}
 }
 
@@ -46,7 +48,7 @@ func entryToRelationship(e relationshipEntry) (*models.Relationship, error) {
 	if err != nil {
 		return nil, err
 	}
-	createdAt, err := mdstore.ParseTime(e.CreatedAt)
+	createdAt, err := parseTime(e.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -73,21 +75,155 @@ func (s *MarkdownStore) readRelationships() ([]relationshipEntry, error) {
 	return entries, nil
 }
 
-// writeRelationships writes the full relationships list to the YAML file.
+// writeRelationships writes the full relationships list to the YAML file,
+// collapsing any duplicate pairs left over from older files.
 func (s *MarkdownStore) writeRelationships(entries []relationshipEntry) error {
-	return mdstore.WriteYAML(s.relationshipsFile(), entries)
+	return mdstore.WriteYAML(s.relationshipsFile(), dedupeRelationshipEntries(entries))
 }
 
-// CreateRelationship appends a new relationship to the YAML file.
+// CreateRelationship appends a new relationship to the YAML file. It returns
+// ErrSelfRelationship if both ends are the same entity, ErrEndpointNotFound
+// if either end is not an existing contact or company, and
+// ErrDuplicateRelationship if a relationship of the same type already joins
+// the pair in either direction.
 func (s *MarkdownStore) CreateRelationship(rel *models.Relationship) error {
+	if err := s.validateRelationship(rel); err != nil {
+		return err
+	}
 	entries, err := s.readRelationships()
 	if err != nil {
 		return err
 	}
+	if findRelationshipEntry(entries, rel) >= 0 {
+		return ErrDuplicateRelationship
+	}
 	entries = append(entries, relationshipToEntry(rel))
 	return s.writeRelationships(entries)
 }
 
+// CreateOrUpdateRelationship appends rel, or, if a relationship of the same
+// type already joins the pair in either direction, updates that entry's
+// context instead. On update, rel is rewritten to match the stored entry.
+func (s *MarkdownStore) CreateOrUpdateRelationship(rel *models.Relationship) error {
+	if err := s.validateRelationship(rel); err != nil {
+		return err
+	}
+	entries, err := s.readRelationships()
+	if err != nil {
+		return err
+	}
+	i := findRelationshipEntry(entries, rel)
+	if i < 0 {
+		entries = append(entries, relationshipToEntry(rel))
+		return s.writeRelationships(entries)
+	}
+
+	entries[i].Context = rel.Context
+	existing, err := entryToRelationship(entries[i])
+	if err != nil {
+		return err
+	}
+	if err := s.writeRelationships(entries); err != nil {
+		return err
+	}
+	*rel = *existing
+	return nil
+}
+
+// validateRelationship checks that rel joins two distinct, existing entities.
+func (s *MarkdownStore) validateRelationship(rel *models.Relationship) error {
+	if rel.SourceID == rel.TargetID {
+		return ErrSelfRelationship
+	}
+	for _, id := range []uuid.UUID{rel.SourceID, rel.TargetID} {
+		ok, err := s.entityExists(id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrEndpointNotFound, id)
+		}
+	}
+	return nil
+}
+
+// findRelationshipEntry returns the index of the entry with rel's type that
+// joins rel's endpoints in either direction, or -1.
+func findRelationshipEntry(entries []relationshipEntry, rel *models.Relationship) int {
+	src, tgt := rel.SourceID.String(), rel.TargetID.String()
+	for i, e := range entries {
+		if e.Type != rel.Type {
+			continue
+		}
+		if (e.SourceID == src && e.TargetID == tgt) || (e.SourceID == tgt && e.TargetID == src) {
+			return i
+		}
+	}
+	return -1
+}
+
+// dedupeRelationshipEntries keeps only the newest entry for each type and
+// unordered pair, preserving the order of the survivors. Files written before
+// duplicates were rejected may still contain them.
+func dedupeRelationshipEntries(entries []relationshipEntry) []relationshipEntry {
+	type groupKey struct {
+		lo, hi, relType string
+	}
+	keyOf := func(e relationshipEntry) groupKey {
+		lo, hi := e.SourceID, e.TargetID
+		if hi < lo {
+			lo, hi = hi, lo
+		}
+		return groupKey{lo: lo, hi: hi, relType: e.Type}
+	}
+
+	newest := make(map[groupKey]int, len(entries))
+	for i, e := range entries {
+		k := keyOf(e)
+		j, ok := newest[k]
+		if !ok || !entryCreatedAt(e).Before(entryCreatedAt(entries[j])) {
+			newest[k] = i
+		}
+	}
+	if len(newest) == len(entries) {
+		return entries
+	}
+
+	kept := make([]relationshipEntry, 0, len(newest))
+	for i, e := range entries {
+		if newest[keyOf(e)] == i {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// entryCreatedAt parses an entry's creation time, treating unparseable
+// values as the zero time so they lose to any valid entry.
+func entryCreatedAt(e relationshipEntry) time.Time {
+	t, err := parseTime(e.CreatedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// entityExists reports whether id belongs to a contact or a company.
+func (s *MarkdownStore) entityExists(id uuid.UUID) (bool, error) {
+	_, c, err := s.findContactFile(id)
+	if err != nil {
+		return false, err
+	}
+	if c != nil {
+		return true, nil
+	}
+	_, co, err := s.findCompanyFile(id)
+	if err != nil {
+		return false, err
+	}
+	return co != nil, nil
+}
+
 // ListRelationships returns all relationships where the given entity ID appears
 // as either source_id or target_id.
 func (s *MarkdownStore) ListRelationships(entityID uuid.UUID) ([]*models.Relationship, error) {
@@ -131,3 +267,51 @@ func (s *MarkdownStore) DeleteRelationship(id uuid.UUID) error {
 	}
 	return s.writeRelationships(remaining)
 }
+
+// deleteRelationshipsFor removes every relationship with id at either end,
+// so deleting an entity leaves no dangling edges behind.
+func (s *MarkdownStore) deleteRelationshipsFor(id uuid.UUID) error {
+	entries, err := s.readRelationships()
+	if err != nil {
+		return err
+	}
+	idStr := id.String()
+	remaining := make([]relationshipEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.SourceID != idStr && e.TargetID != idStr {
+			remaining = append(remaining, e)
+		}
+	}
+	if len(remaining) == len(entries) {
+		return nil
+	}
+	return s.writeRelationships(remaining)
+}
+
+// RelationshipExistsMany reports, for each given pair, whether any
+// relationship joins the two entities in either direction. The result is
+// keyed by the pairs exactly as given.
+func (s *MarkdownStore) RelationshipExistsMany(pairs [][2]uuid.UUID) (map[[2]uuid.UUID]bool, error) {
+	entries, err := s.readRelationships()
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[[2]uuid.UUID]bool, len(entries))
+	for _, e := range entries {
+		src, err := uuid.Parse(e.SourceID)
+		if err != nil {
+			continue
+		}
+		tgt, err := uuid.Parse(e.TargetID)
+		if err != nil {
+			continue
+		}
+		found[pairKey(src, tgt)] = true
+	}
+
+	result := make(map[[2]uuid.UUID]bool, len(pairs))
+	for _, p := range pairs {
+		result[p] = found[pairKey(p[0], p[1])]
+	}
+	return result, nil
+}
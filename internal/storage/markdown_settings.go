@@ -0,0 +1,45 @@
+// ABOUTME: Markdown storage for the settings key/value store.
+// ABOUTME: Keeps all settings as a single map in _settings.yaml.
+package storage
+
+import "github.com/harperreed/mdstore"
+
+// readSettings reads the settings map, which is empty when no file exists.
+func (s *MarkdownStore) readSettings() (map[string]string, error) {
+	settings := map[string]string{}
+	if err := mdstore.ReadYAML(s.settingsFile(), &settings); err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = map[string]string{}
+	}
+	return settings, nil
+}
+
+// GetSetting returns the value stored under key and whether it was set.
+func (s *MarkdownStore) GetSetting(key string) (string, bool, error) {
+	settings, err := s.readSettings()
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := settings[key]
+	return value, ok, nil
+}
+
+// SetSetting stores value under key, replacing any previous value.
+func (s *MarkdownStore) SetSetting(key, value string) error {
+	if err := validateSettingKey(key); err != nil {
+		return err
+	}
+	settings, err := s.readSettings()
+	if err != nil {
+		return err
+	}
+	settings[key] = value
+	return mdstore.WriteYAML(s.settingsFile(), settings)
+}
+
+// ListSettings returns every stored setting.
+func (s *MarkdownStore) ListSettings() (map[string]string, error) {
+	return s.readSettings()
+}
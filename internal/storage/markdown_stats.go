@@ -0,0 +1,54 @@
+// ABOUTME: Record counts per entity for the markdown storage backend.
+// ABOUTME: Counts .md files by directory listing and YAML list entries without parsing frontmatter.
+package storage
+
+import (
+	"os"
+	"strings"
+)
+
+// Stats returns the number of stored records of each entity type.
+func (s *MarkdownStore) Stats() (*Stats, error) {
+	contacts, err := countMarkdownFiles(s.contactsDir())
+	if err != nil {
+		return nil, err
+	}
+	companies, err := countMarkdownFiles(s.companiesDir())
+	if err != nil {
+		return nil, err
+	}
+	rels, err := s.readRelationships()
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.readAttachments()
+	if err != nil {
+		return nil, err
+	}
+	return &Stats{
+		Contacts:      contacts,
+		Companies:     companies,
+		Relationships: len(rels),
+		Attachments:   len(attachments),
+	}, nil
+}
+
+// countMarkdownFiles counts the .md files directly inside dir.
+func countMarkdownFiles(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// SchemaVersion reports that the markdown backend has no schema version.
+func (s *MarkdownStore) SchemaVersion() (int, bool, error) {
+	return 0, false, nil
+}
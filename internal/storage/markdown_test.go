@@ -4,12 +4,17 @@ package storage
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/crm/internal/models"
+	"github.com/harperreed/mdstore"
 )
 
 // newTestMarkdownStore creates a MarkdownStore in a temp directory.
@@ -211,8 +216,8 @@ func TestMarkdownDeleteCompanyNotFound(t *testing.T) {
 func TestMarkdownRelationships(t *testing.T) {
 	store := newTestMarkdownStore(t)
 
-	srcID := uuid.New()
-	tgtID := uuid.New()
+	ids := newTestEntities(t, store, 2)
+	srcID, tgtID := ids[0], ids[1]
 	rel := models.NewRelationship(srcID, tgtID, "works_at", "engineering team")
 
 	// Create
@@ -269,9 +274,8 @@ func TestMarkdownDeleteRelationshipNotFound(t *testing.T) {
 func TestMarkdownRelationshipsBidirectional(t *testing.T) {
 	store := newTestMarkdownStore(t)
 
-	a := uuid.New()
-	b := uuid.New()
-	c := uuid.New()
+	ids := newTestEntities(t, store, 3)
+	a, b, c := ids[0], ids[1], ids[2]
 
 	r1 := models.NewRelationship(a, b, "knows", "")
 	r2 := models.NewRelationship(c, a, "manages", "")
@@ -703,3 +707,1107 @@ func TestMarkdownInterfaceCompliance(t *testing.T) {
 	store := newTestMarkdownStore(t)
 	var _ Storage = store
 }
+
+func TestMarkdownListContactsWithoutCompany(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	employed := models.NewContact("Employed")
+	loner := models.NewContact("Loner")
+	acme := models.NewCompany("Acme")
+	ghost := models.NewCompany("Ghost Inc")
+	for _, c := range []*models.Contact{employed, loner} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	for _, co := range []*models.Company{acme, ghost} {
+		if err := store.CreateCompany(co); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+	if err := store.CreateRelationship(models.NewRelationship(employed.ID, acme.ID, "works_at", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+
+	contacts, err := store.ListContactsWithoutCompany()
+	if err != nil {
+		t.Fatalf("ListContactsWithoutCompany: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].ID != loner.ID {
+		t.Errorf("ListContactsWithoutCompany = %v, want only Loner", contacts)
+	}
+
+	companies, err := store.ListEmptyCompanies()
+	if err != nil {
+		t.Fatalf("ListEmptyCompanies: %v", err)
+	}
+	if len(companies) != 1 || companies[0].ID != ghost.ID {
+		t.Errorf("ListEmptyCompanies = %v, want only Ghost Inc", companies)
+	}
+}
+
+func TestMarkdownAttachments(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	entityID := uuid.New()
+	blob := models.NewAttachment("contact", entityID, "notes.txt")
+	blob.Data = []byte("hello")
+	ref := models.NewAttachment("contact", entityID, "contract.pdf")
+	ref.Path = "/docs/contract.pdf"
+
+	for _, a := range []*models.Attachment{blob, ref} {
+		if err := store.AddAttachment(a); err != nil {
+			t.Fatalf("AddAttachment: %v", err)
+		}
+	}
+
+	got, err := store.GetAttachment(blob.ID)
+	if err != nil {
+		t.Fatalf("GetAttachment: %v", err)
+	}
+	if string(got.Data) != "hello" || got.Size != 5 {
+		t.Errorf("GetAttachment = %q (size %d), want %q (size 5)", got.Data, got.Size, "hello")
+	}
+
+	list, err := store.ListAttachments(entityID)
+	if err != nil {
+		t.Fatalf("ListAttachments: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("ListAttachments len = %d, want 2", len(list))
+	}
+
+	if err := store.DeleteAttachment(blob.ID); err != nil {
+		t.Fatalf("DeleteAttachment: %v", err)
+	}
+	if _, err := os.Stat(store.blobPath(blob.ID.String())); !os.IsNotExist(err) {
+		t.Error("expected blob file to be removed")
+	}
+	if _, err := store.GetAttachment(blob.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("GetAttachment after delete: got %v, want ErrAttachmentNotFound", err)
+	}
+}
+
+func TestMarkdownStats(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	alice := models.NewContact("Alice")
+	acme := models.NewCompany("Acme")
+	if err := store.CreateContact(alice); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	if err := store.CreateRelationship(models.NewRelationship(alice.ID, acme.ID, "works_at", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+
+	st, err := store.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	want := Stats{Contacts: 1, Companies: 1, Relationships: 1}
+	if *st != want {
+		t.Errorf("Stats = %+v, want %+v", *st, want)
+	}
+}
+
+func TestMarkdownListRecent(t *testing.T) {
+	store, err := NewMarkdownStoreWithOptions(t.TempDir(), Options{TrackAccess: true})
+	if err != nil {
+		t.Fatalf("NewMarkdownStoreWithOptions: %v", err)
+	}
+
+	alice := models.NewContact("Alice")
+	acme := models.NewCompany("Acme")
+	if err := store.CreateContact(alice); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := store.GetContact(alice.ID); err != nil {
+			t.Fatalf("GetContact: %v", err)
+		}
+	}
+	if _, err := store.GetCompany(acme.ID); err != nil {
+		t.Fatalf("GetCompany: %v", err)
+	}
+
+	ids, err := store.ListRecent("", 0)
+	if err != nil {
+		t.Fatalf("ListRecent: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != acme.ID || ids[1] != alice.ID {
+		t.Errorf("ListRecent = %v, want [%v %v]", ids, acme.ID, alice.ID)
+	}
+
+	if err := store.DeleteCompany(acme.ID); err != nil {
+		t.Fatalf("DeleteCompany: %v", err)
+	}
+	ids, err = store.ListRecent(EntityCompany, 0)
+	if err != nil {
+		t.Fatalf("ListRecent(company): %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected deleted company to be forgotten, got %v", ids)
+	}
+}
+
+func TestMarkdownGetContactExpanded(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	acme := models.NewCompany("Acme")
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	worksAt := models.NewRelationship(alice.ID, acme.ID, "works_at", "")
+	knows := models.NewRelationship(bob.ID, alice.ID, "knows", "")
+	for _, r := range []*models.Relationship{worksAt, knows} {
+		if err := store.CreateRelationship(r); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+
+	if err := store.AddContactNote(models.NewContactNote(alice.ID, "Met at the conference")); err != nil {
+		t.Fatalf("AddContactNote: %v", err)
+	}
+	att := models.NewAttachment(EntityContact, alice.ID, "resume.pdf")
+	att.Data = []byte("pdf")
+	if err := store.AddAttachment(att); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	got, err := store.GetContactExpanded(alice.ID)
+	if err != nil {
+		t.Fatalf("GetContactExpanded: %v", err)
+	}
+	if len(got.Companies) != 1 || got.Companies[0].ID != acme.ID {
+		t.Errorf("Companies = %v, want [Acme]", got.Companies)
+	}
+	if len(got.Relationships) != 2 {
+		t.Fatalf("Relationships len = %d, want 2", len(got.Relationships))
+	}
+	if len(got.Notes) != 1 || got.Notes[0].Content != "Met at the conference" {
+		t.Errorf("Notes = %v, want the conference note", got.Notes)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].Filename != "resume.pdf" {
+		t.Errorf("Attachments = %v, want resume.pdf", got.Attachments)
+	}
+	for _, e := range got.Relationships {
+		switch e.Relationship.ID {
+		case worksAt.ID:
+			if e.OtherType != EntityCompany || e.OtherName != "Acme" {
+				t.Errorf("works_at counterpart = %s %q, want company Acme", e.OtherType, e.OtherName)
+			}
+		case knows.ID:
+			if e.OtherID != bob.ID || e.OtherName != "Bob" {
+				t.Errorf("knows counterpart = %v %q, want Bob", e.OtherID, e.OtherName)
+			}
+		}
+	}
+
+	if _, err := store.GetContactExpanded(uuid.New()); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("expected ErrContactNotFound, got %v", err)
+	}
+}
+
+func TestMarkdownIdempotencyKeys(t *testing.T) {
+	store, err := NewMarkdownStoreWithOptions(t.TempDir(), Options{IdempotencyTTL: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewMarkdownStoreWithOptions: %v", err)
+	}
+
+	id := uuid.New()
+	if _, claimed, err := store.ClaimIdempotencyKey(EntityCompany, "k1", id); err != nil || !claimed {
+		t.Fatalf("ClaimIdempotencyKey = claimed:%v err:%v, want claimed", claimed, err)
+	}
+	if held, claimed, err := store.ClaimIdempotencyKey(EntityCompany, "k1", uuid.New()); err != nil || claimed || held != id {
+		t.Errorf("second claim = %v claimed:%v err:%v, want %v unclaimed", held, claimed, err, id)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	replacement := uuid.New()
+	if held, claimed, err := store.ClaimIdempotencyKey(EntityCompany, "k1", replacement); err != nil || !claimed || held != replacement {
+		t.Errorf("claim after expiry = %v claimed:%v err:%v, want %v claimed", held, claimed, err, replacement)
+	}
+}
+
+func TestMarkdownClaimIdempotencyKey(t *testing.T) {
+	checkClaimIdempotencyKey(t, newTestMarkdownStore(t))
+}
+
+func TestMarkdownEmailAndDomainLookup(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	c := models.NewContact("Jane")
+	c.Email = "jane@acme.com"
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	acme := models.NewCompany("Acme")
+	acme.Domain = "www.acme.com"
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	gotContact, err := store.GetContactByEmail("JANE@acme.com")
+	if err != nil || gotContact.ID != c.ID {
+		t.Errorf("GetContactByEmail = %v, %v; want Jane", gotContact, err)
+	}
+	gotCompany, err := store.GetCompanyByDomain("ACME.com")
+	if err != nil || gotCompany.ID != acme.ID {
+		t.Errorf("GetCompanyByDomain = %v, %v; want Acme", gotCompany, err)
+	}
+
+	if _, err := store.GetContactByEmail("bob@acme.com"); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("expected ErrContactNotFound, got %v", err)
+	}
+	if _, err := store.GetCompanyByDomain("example.com"); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("expected ErrCompanyNotFound, got %v", err)
+	}
+}
+
+func TestMarkdownTimestampsStoredInUTC(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	zone := time.FixedZone("UTC+9", 9*60*60)
+	c := models.NewContact("Zoned")
+	c.CreatedAt = time.Date(2024, 3, 1, 9, 0, 0, 0, zone)
+	c.UpdatedAt = c.CreatedAt
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	path, _, err := store.findContactFile(c.ID)
+	if err != nil {
+		t.Fatalf("findContactFile: %v", err)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // test-controlled temp path
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), "2024-03-01T00:00:00Z") {
+		t.Errorf("expected UTC timestamp in file, got:\n%s", data)
+	}
+
+	got, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.CreatedAt.Location() != time.UTC || !got.CreatedAt.Equal(c.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want UTC instant %v", got.CreatedAt, c.CreatedAt)
+	}
+}
+
+func TestMarkdownContactDoNotContact(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	c := models.NewContact("Opted Out")
+	c.DoNotContact = true
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	got, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if !got.DoNotContact {
+		t.Error("expected DoNotContact to survive a round trip")
+	}
+
+	got.DoNotContact = false
+	if err := store.UpdateContact(got); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	cleared, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact after clear: %v", err)
+	}
+	if cleared.DoNotContact {
+		t.Error("expected DoNotContact to be cleared")
+	}
+}
+
+func TestMarkdownSuggestColleagueRelationships(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	carol := models.NewContact("Carol")
+	for _, c := range []*models.Contact{alice, bob, carol} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	for _, r := range []*models.Relationship{
+		models.NewRelationship(alice.ID, acme.ID, "works_at", ""),
+		models.NewRelationship(acme.ID, bob.ID, "employs", ""),
+		models.NewRelationship(carol.ID, acme.ID, "works_at", ""),
+		models.NewRelationship(carol.ID, alice.ID, "knows", ""),
+	} {
+		if err := store.CreateRelationship(r); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+
+	got, err := store.SuggestColleagueRelationships(0)
+	if err != nil {
+		t.Fatalf("SuggestColleagueRelationships: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("suggestions = %d, want 2", len(got))
+	}
+	if got[0].SourceName != "Alice" || got[0].TargetName != "Bob" {
+		t.Errorf("first suggestion = %s-%s, want Alice-Bob", got[0].SourceName, got[0].TargetName)
+	}
+	if got[1].SourceName != "Bob" || got[1].TargetName != "Carol" {
+		t.Errorf("second suggestion = %s-%s, want Bob-Carol", got[1].SourceName, got[1].TargetName)
+	}
+}
+
+func TestMarkdownRelationshipExistsMany(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	ids := newTestEntities(t, store, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+	if err := store.CreateRelationship(models.NewRelationship(a, b, "knows", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+
+	pairs := [][2]uuid.UUID{{b, a}, {c, a}}
+	got, err := store.RelationshipExistsMany(pairs)
+	if err != nil {
+		t.Fatalf("RelationshipExistsMany: %v", err)
+	}
+	if !got[pairs[0]] || got[pairs[1]] {
+		t.Errorf("RelationshipExistsMany = %v, want b-a true and c-a false", got)
+	}
+}
+
+func TestMarkdownCreateRelationshipValidation(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	id := newTestEntities(t, store, 1)[0]
+	if err := store.CreateRelationship(models.NewRelationship(id, id, "knows", "")); !errors.Is(err, ErrSelfRelationship) {
+		t.Errorf("expected ErrSelfRelationship, got %v", err)
+	}
+	if err := store.CreateRelationship(models.NewRelationship(id, uuid.New(), "knows", "")); !errors.Is(err, ErrEndpointNotFound) {
+		t.Errorf("expected ErrEndpointNotFound, got %v", err)
+	}
+
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	if err := store.CreateRelationship(models.NewRelationship(id, acme.ID, "works_at", "")); err != nil {
+		t.Errorf("CreateRelationship(contact, company): %v", err)
+	}
+}
+
+func TestMarkdownCreateOrUpdateRelationship(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	ids := newTestEntities(t, store, 2)
+	first := models.NewRelationship(ids[0], ids[1], "colleague", "same team")
+	if err := store.CreateOrUpdateRelationship(first); err != nil {
+		t.Fatalf("CreateOrUpdateRelationship(first): %v", err)
+	}
+	dup := models.NewRelationship(ids[1], ids[0], "colleague", "")
+	if err := store.CreateRelationship(dup); !errors.Is(err, ErrDuplicateRelationship) {
+		t.Errorf("expected ErrDuplicateRelationship, got %v", err)
+	}
+
+	again := models.NewRelationship(ids[1], ids[0], "colleague", "different teams now")
+	if err := store.CreateOrUpdateRelationship(again); err != nil {
+		t.Fatalf("CreateOrUpdateRelationship(again): %v", err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("update returned ID %s, want existing %s", again.ID, first.ID)
+	}
+	rels, err := store.ListRelationships(ids[0])
+	if err != nil {
+		t.Fatalf("ListRelationships: %v", err)
+	}
+	if len(rels) != 1 || rels[0].Context != "different teams now" {
+		t.Errorf("relationships = %+v, want one with updated context", rels)
+	}
+}
+
+func TestDedupeRelationshipEntries(t *testing.T) {
+	entries := []relationshipEntry{
+		{ID: "old", SourceID: "a", TargetID: "b", Type: "colleague", CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "other", SourceID: "a", TargetID: "c", Type: "colleague", CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "new", SourceID: "b", TargetID: "a", Type: "colleague", CreatedAt: "2024-06-01T00:00:00Z"},
+		{ID: "typed", SourceID: "a", TargetID: "b", Type: "mentor", CreatedAt: "2024-01-01T00:00:00Z"},
+	}
+	got := dedupeRelationshipEntries(entries)
+	var ids []string
+	for _, e := range got {
+		ids = append(ids, e.ID)
+	}
+	if strings.Join(ids, ",") != "other,new,typed" {
+		t.Errorf("kept %v, want [other new typed]", ids)
+	}
+}
+
+func TestMarkdownListCompaniesLimits(t *testing.T) {
+	store, err := NewMarkdownStoreWithOptions(t.TempDir(), Options{DefaultLimit: 2, MaxLimit: 3})
+	if err != nil {
+		t.Fatalf("NewMarkdownStoreWithOptions: %v", err)
+	}
+	for _, name := range []string{"Acme", "Globex", "Initech", "Umbrella"} {
+		if err := store.CreateCompany(models.NewCompany(name)); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+
+	got, err := store.ListCompanies(nil)
+	if err != nil {
+		t.Fatalf("ListCompanies(nil): %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("ListCompanies(nil) len = %d, want default 2", len(got))
+	}
+	got, err = store.ListCompanies(&CompanyFilter{Limit: 50})
+	if err != nil {
+		t.Fatalf("ListCompanies(50): %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("ListCompanies(50) len = %d, want max 3", len(got))
+	}
+	all, err := store.GetAllCompanies()
+	if err != nil {
+		t.Fatalf("GetAllCompanies: %v", err)
+	}
+	if len(all) != 4 {
+		t.Errorf("GetAllCompanies len = %d, want 4", len(all))
+	}
+}
+
+func TestMarkdownForEach(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	newTestEntities(t, store, 3)
+	if err := store.CreateCompany(models.NewCompany("Acme")); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	contacts := 0
+	if err := store.ForEachContact(func(*models.Contact) error { contacts++; return nil }); err != nil {
+		t.Fatalf("ForEachContact: %v", err)
+	}
+	if contacts != 3 {
+		t.Errorf("visited %d contacts, want 3", contacts)
+	}
+
+	errStop := errors.New("stop")
+	if err := store.ForEachCompany(func(*models.Company) error { return errStop }); !errors.Is(err, errStop) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+}
+
+func TestMarkdownDeleteContactRemovesRelationships(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	ids := newTestEntities(t, store, 3)
+	for _, rel := range []*models.Relationship{
+		models.NewRelationship(ids[0], ids[1], "knows", ""),
+		models.NewRelationship(ids[1], ids[2], "knows", ""),
+	} {
+		if err := store.CreateRelationship(rel); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+
+	if err := store.DeleteContact(ids[0]); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+
+	entries, err := store.readRelationships()
+	if err != nil {
+		t.Fatalf("readRelationships: %v", err)
+	}
+	if len(entries) != 1 || entries[0].SourceID != ids[1].String() {
+		t.Errorf("relationships left = %+v, want only %s-%s", entries, ids[1], ids[2])
+	}
+}
+
+func TestMarkdownDeleteCompanyRemovesDependents(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	id := newTestEntities(t, store, 1)[0]
+	if err := store.CreateRelationship(models.NewRelationship(id, acme.ID, "works_at", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	a := models.NewAttachment(EntityCompany, acme.ID, "logo.png")
+	a.Data = []byte("png")
+	if err := store.AddAttachment(a); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	if err := store.DeleteCompany(acme.ID); err != nil {
+		t.Fatalf("DeleteCompany: %v", err)
+	}
+
+	rels, err := store.readRelationships()
+	if err != nil {
+		t.Fatalf("readRelationships: %v", err)
+	}
+	atts, err := store.readAttachments()
+	if err != nil {
+		t.Fatalf("readAttachments: %v", err)
+	}
+	if len(rels) != 0 || len(atts) != 0 {
+		t.Errorf("left %d relationships and %d attachments, want none", len(rels), len(atts))
+	}
+	if _, err := os.Stat(store.blobPath(a.ID.String())); !os.IsNotExist(err) {
+		t.Errorf("expected attachment blob to be removed, stat err = %v", err)
+	}
+}
+
+func TestMarkdownCheckIntegrity(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	ids := newTestEntities(t, store, 2)
+	good := models.NewRelationship(ids[0], ids[1], "knows", "")
+	if err := store.CreateRelationship(good); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	note := models.NewContactNote(ids[1], "Left the company")
+	if err := store.AddContactNote(note); err != nil {
+		t.Fatalf("AddContactNote: %v", err)
+	}
+
+	// Remove the contact file behind the store's back so the link and note dangle.
+	path, _, err := store.findContactFile(ids[1])
+	if err != nil {
+		t.Fatalf("findContactFile: %v", err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove contact file: %v", err)
+	}
+
+	report, err := store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	if len(report.DanglingRelationships) != 1 || report.DanglingRelationships[0] != good.ID {
+		t.Errorf("DanglingRelationships = %v, want [%s]", report.DanglingRelationships, good.ID)
+	}
+	if len(report.OrphanedAttachments) != 0 {
+		t.Errorf("OrphanedAttachments = %v, want none", report.OrphanedAttachments)
+	}
+	if len(report.OrphanedContactNotes) != 1 || report.OrphanedContactNotes[0] != note.ID {
+		t.Errorf("OrphanedContactNotes = %v, want [%s]", report.OrphanedContactNotes, note.ID)
+	}
+	if len(report.OrphanedCompanyNotes) != 0 {
+		t.Errorf("OrphanedCompanyNotes = %v, want none", report.OrphanedCompanyNotes)
+	}
+}
+
+func TestMarkdownUpdateRejectsStaleVersion(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	c := models.NewContact("Alice")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	stale, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+
+	c.Email = "alice@example.com"
+	if err := store.UpdateContact(c); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	if c.Version != 2 {
+		t.Errorf("version after update = %d, want 2", c.Version)
+	}
+
+	stale.Phone = "555-0100"
+	if err := store.UpdateContact(stale); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("stale UpdateContact err = %v, want ErrVersionConflict", err)
+	}
+	got, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.Email != "alice@example.com" || got.Phone != "" || got.Version != 2 {
+		t.Errorf("stored contact = %+v, want first update only at version 2", got)
+	}
+}
+
+func TestMarkdownSearchMatchesPhoneDigits(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	c := models.NewContact("Caller")
+	c.Phone = "(555) 123-4567"
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	results, err := store.Search("5551234567")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results.Contacts) != 1 {
+		t.Errorf("Search by digits found %d contacts, want 1", len(results.Contacts))
+	}
+
+	// Matching is on trailing digits, as in the SQLite backend.
+	results, err = store.Search("5551234")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results.Contacts) != 0 {
+		t.Errorf("Search(middle digits) found %d contacts, want 0", len(results.Contacts))
+	}
+}
+
+func TestMarkdownGetOrCreateCompany(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	created, isNew, err := store.GetOrCreateCompany(" Acme ")
+	if err != nil {
+		t.Fatalf("GetOrCreateCompany: %v", err)
+	}
+	if !isNew || created.Name != "Acme" {
+		t.Fatalf("first call = (%q, %v), want a new company named Acme", created.Name, isNew)
+	}
+	again, isNew, err := store.GetOrCreateCompany("Acme")
+	if err != nil {
+		t.Fatalf("GetOrCreateCompany: %v", err)
+	}
+	if isNew || again.ID != created.ID {
+		t.Errorf("second call = (%v, %v), want existing %v", again.ID, isNew, created.ID)
+	}
+}
+
+func TestMarkdownGetOrCreateContact(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	created, isNew, err := store.GetOrCreateContact("Jane Doe", "jane@acme.com", &acme.ID)
+	if err != nil {
+		t.Fatalf("GetOrCreateContact: %v", err)
+	}
+	if !isNew {
+		t.Fatal("expected a new contact")
+	}
+	rels, err := store.ListRelationships(created.ID)
+	if err != nil {
+		t.Fatalf("ListRelationships: %v", err)
+	}
+	if len(rels) != 1 || rels[0].TargetID != acme.ID {
+		t.Errorf("relationships = %+v, want one link to Acme", rels)
+	}
+
+	again, isNew, err := store.GetOrCreateContact("Someone Else", "Jane@Acme.com", nil)
+	if err != nil {
+		t.Fatalf("GetOrCreateContact: %v", err)
+	}
+	if isNew || again.ID != created.ID {
+		t.Errorf("email match = (%v, %v), want existing %v", again.ID, isNew, created.ID)
+	}
+}
+
+func TestMarkdownListContactsAfter(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	newTestEntities(t, store, 3)
+
+	first, next, err := store.ListContactsAfter(Cursor{}, 2)
+	if err != nil {
+		t.Fatalf("ListContactsAfter: %v", err)
+	}
+	rest, _, err := store.ListContactsAfter(next, 2)
+	if err != nil {
+		t.Fatalf("ListContactsAfter: %v", err)
+	}
+	if len(first) != 2 || len(rest) != 1 {
+		t.Fatalf("pages = %d, %d; want 2, 1", len(first), len(rest))
+	}
+	if rest[0].ID == first[0].ID || rest[0].ID == first[1].ID {
+		t.Error("second page repeated a contact from the first")
+	}
+}
+
+func TestMarkdownModifiedSinceAndDeletions(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	checkpoint := time.Now().Add(-time.Hour)
+
+	old := models.NewContact("Old")
+	old.CreatedAt = checkpoint.Add(-time.Hour)
+	old.UpdatedAt = checkpoint.Add(-time.Hour)
+	fresh := models.NewContact("Fresh")
+	for _, c := range []*models.Contact{old, fresh} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+
+	got, err := store.GetContactsModifiedSince(checkpoint)
+	if err != nil {
+		t.Fatalf("GetContactsModifiedSince: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != fresh.ID {
+		t.Errorf("modified since = %v, want only Fresh", got)
+	}
+
+	if err := store.DeleteContact(old.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+	deletions, err := store.ListDeletionsSince(checkpoint)
+	if err != nil {
+		t.Fatalf("ListDeletionsSince: %v", err)
+	}
+	if len(deletions) != 1 || deletions[0].EntityID != old.ID || deletions[0].EntityType != EntityContact {
+		t.Errorf("deletions = %+v, want the Old contact", deletions)
+	}
+}
+
+func TestMarkdownReadChangeFeed(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	c := models.NewContact("Fed")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	c.Email = "fed@example.com"
+	if err := store.UpdateContact(c); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	if err := store.DeleteContact(c.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+
+	events, err := store.ReadChangeFeed(0, 0)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	ops := make([]string, len(events))
+	for i, e := range events {
+		ops[i] = e.Op
+	}
+	if strings.Join(ops, ",") != "create,update,delete" {
+		t.Errorf("ops = %v, want create,update,delete", ops)
+	}
+
+	rest, err := store.ReadChangeFeed(events[0].Seq, 0)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	if len(rest) != 2 {
+		t.Errorf("events after first = %d, want 2", len(rest))
+	}
+}
+
+func TestMarkdownContactPhoto(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	c := models.NewContact("Alice")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	if _, _, err := store.GetContactPhoto(c.ID); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("GetContactPhoto before set: err = %v, want ErrPhotoNotFound", err)
+	}
+	if err := store.SetContactPhoto(c.ID, "image/png", []byte("png-bytes")); err != nil {
+		t.Fatalf("SetContactPhoto: %v", err)
+	}
+	data, contentType, err := store.GetContactPhoto(c.ID)
+	if err != nil {
+		t.Fatalf("GetContactPhoto: %v", err)
+	}
+	if string(data) != "png-bytes" || contentType != "image/png" {
+		t.Errorf("GetContactPhoto = %q %q, want png-bytes image/png", data, contentType)
+	}
+	if err := store.SetContactPhoto(c.ID, "text/plain", []byte("x")); !errors.Is(err, ErrInvalidPhoto) {
+		t.Errorf("non-image type: err = %v, want ErrInvalidPhoto", err)
+	}
+
+	if err := store.DeleteContact(c.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(store.photosDir(), c.ID.String())); !os.IsNotExist(err) {
+		t.Errorf("photo file left behind after delete: %v", err)
+	}
+}
+
+func TestMarkdownGetByIDs(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	alice := models.NewContact("Alice")
+	if err := store.CreateContact(alice); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	contacts, err := store.GetContactsByIDs([]uuid.UUID{alice.ID, acme.ID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetContactsByIDs: %v", err)
+	}
+	if len(contacts) != 1 || contacts[alice.ID].Name != "Alice" {
+		t.Errorf("GetContactsByIDs = %v, want only Alice", contacts)
+	}
+	companies, err := store.GetCompaniesByIDs([]uuid.UUID{alice.ID, acme.ID})
+	if err != nil {
+		t.Fatalf("GetCompaniesByIDs: %v", err)
+	}
+	if len(companies) != 1 || companies[acme.ID].Name != "Acme" {
+		t.Errorf("GetCompaniesByIDs = %v, want only Acme", companies)
+	}
+}
+
+func TestMarkdownSource(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	imported := models.NewContact("Alice")
+	imported.Source = "csv"
+	if err := store.CreateContact(imported); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := store.CreateContact(models.NewContact("Bob")); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	acme := models.NewCompany("Acme")
+	acme.Source = "csv"
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	contacts, err := store.ListContacts(&ContactFilter{Source: "csv"})
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].Source != "csv" {
+		t.Errorf("ListContacts source=csv = %v, want only Alice", contacts)
+	}
+	got, err := store.GetCompany(acme.ID)
+	if err != nil {
+		t.Fatalf("GetCompany: %v", err)
+	}
+	if got.Source != "csv" {
+		t.Errorf("company Source = %q, want csv", got.Source)
+	}
+}
+
+func TestMarkdownReassignCompanyContacts(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	from, to, partner, alice, bob := reassignFixture(t, store)
+
+	n, err := store.ReassignCompanyContacts(from.ID, to.ID)
+	if err != nil {
+		t.Fatalf("ReassignCompanyContacts: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("moved = %d, want 2", n)
+	}
+	checkReassigned(t, store, from, to, partner, alice, bob)
+
+	if _, err := store.ReassignCompanyContacts(from.ID, uuid.New()); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("missing target: err = %v, want ErrCompanyNotFound", err)
+	}
+}
+
+func TestMarkdownSettings(t *testing.T) {
+	checkSettings(t, newTestMarkdownStore(t))
+}
+
+func TestMarkdownEnforceUniqueEmail(t *testing.T) {
+	store, err := NewMarkdownStoreWithOptions(t.TempDir(), Options{EnforceUniqueEmail: true})
+	if err != nil {
+		t.Fatalf("NewMarkdownStoreWithOptions: %v", err)
+	}
+	checkUniqueEmailEnforced(t, store)
+}
+
+func TestMarkdownListContactsByEmailDomain(t *testing.T) {
+	checkContactsByEmailDomain(t, newTestMarkdownStore(t))
+}
+
+func TestMarkdownCheckIntegrityDuplicates(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	ids := newTestEntities(t, store, 2)
+
+	older := models.NewRelationship(ids[0], ids[1], "colleague", "")
+	older.CreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := models.NewRelationship(ids[1], ids[0], "colleague", "")
+	newer.CreatedAt = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	// CreateRelationship refuses duplicates, so write the file directly.
+	entries := []relationshipEntry{relationshipToEntry(older), relationshipToEntry(newer)}
+	if err := mdstore.WriteYAML(store.relationshipsFile(), entries); err != nil {
+		t.Fatalf("write relationships: %v", err)
+	}
+
+	report, err := store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	if len(report.DuplicateRelationships) != 1 || report.DuplicateRelationships[0] != older.ID {
+		t.Errorf("DuplicateRelationships = %v, want [%s]", report.DuplicateRelationships, older.ID)
+	}
+}
+
+func TestMarkdownChangeLogAppends(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	legacy := "- seq: 7\n  entity_type: contact\n  entity_id: " + uuid.New().String() +
+		"\n  op: create\n  changed_at: \"" + formatTime(time.Now()) + "\"\n"
+	if err := os.WriteFile(store.changesFile(), []byte(legacy), 0o600); err != nil {
+		t.Fatalf("write legacy change log: %v", err)
+	}
+
+	c := models.NewContact("Fed")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := store.DeleteContact(c.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+
+	data, err := os.ReadFile(store.changesFile())
+	if err != nil {
+		t.Fatalf("read change log: %v", err)
+	}
+	if !strings.HasPrefix(string(data), legacy) {
+		t.Errorf("change log rewrote legacy entries:\n%s", data)
+	}
+	lines := strings.Split(strings.TrimSuffix(strings.TrimPrefix(string(data), legacy), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "- {") || !strings.HasPrefix(lines[1], "- {") {
+		t.Errorf("appended entries = %q, want two single-line entries", lines)
+	}
+	events, err := store.ReadChangeFeed(0, 0)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	if len(events) != 3 || events[1].Seq != 8 || events[2].Seq != 9 {
+		t.Errorf("events = %+v, want seqs 7, 8, 9", events)
+	}
+}
+
+func TestMarkdownChangeLogConcurrentWriters(t *testing.T) {
+	store := newTestMarkdownStore(t)
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.CreateContact(models.NewContact(fmt.Sprintf("Writer %d", i))); err != nil {
+				t.Errorf("CreateContact: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	events, err := store.ReadChangeFeed(0, writers*2)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	if len(events) != writers {
+		t.Fatalf("events = %d, want %d", len(events), writers)
+	}
+	for i, e := range events {
+		if want := int64(i + 1); e.Seq != want {
+			t.Errorf("events[%d].Seq = %d, want %d", i, e.Seq, want)
+		}
+	}
+}
+
+func TestMarkdownChangeLoggedBeforeWrite(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	// A directory in place of the log makes every append fail.
+	if err := os.Mkdir(store.changesFile(), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	c := models.NewContact("Unlogged")
+	if err := store.CreateContact(c); err == nil {
+		t.Fatal("CreateContact with unwritable log: expected error, got nil")
+	}
+	if _, err := store.GetContact(c.ID); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("GetContact after failed create: err = %v, want ErrContactNotFound", err)
+	}
+}
+
+func TestMarkdownPruneChangeLogs(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewMarkdownStore(dir)
+	if err != nil {
+		t.Fatalf("NewMarkdownStore: %v", err)
+	}
+	past := formatTime(time.Now().Add(-DefaultChangeRetention - time.Hour))
+	changes := []changeEntry{
+		{Seq: 1, EntityType: EntityContact, EntityID: uuid.New().String(), Op: ChangeCreate, ChangedAt: past},
+		{Seq: 2, EntityType: EntityContact, EntityID: uuid.New().String(), Op: ChangeCreate, ChangedAt: past},
+	}
+	if err := mdstore.WriteYAML(store.changesFile(), changes); err != nil {
+		t.Fatalf("write change log: %v", err)
+	}
+	deletions := []deletionEntry{{EntityType: EntityContact, EntityID: uuid.New().String(), DeletedAt: past}}
+	if err := mdstore.WriteYAML(store.deletionsFile(), deletions); err != nil {
+		t.Fatalf("write deletion log: %v", err)
+	}
+
+	store, err = NewMarkdownStore(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	events, err := store.ReadChangeFeed(0, 0)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	if len(events) != 1 || events[0].Seq != 2 {
+		t.Errorf("events after prune = %+v, want only the newest", events)
+	}
+	c := models.NewContact("New")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if events, _ := store.ReadChangeFeed(2, 0); len(events) != 1 || events[0].Seq != 3 {
+		t.Errorf("events after prune and create = %+v, want seq 3", events)
+	}
+	if got, _ := store.ListDeletionsSince(time.Time{}); len(got) != 1 {
+		t.Errorf("deletions after prune = %+v, want the newest kept", got)
+	}
+}
+
+func TestMarkdownGetOrCreateContactRollsBackOnLinkFailure(t *testing.T) {
+	store := newTestMarkdownStore(t)
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	// A directory in place of the relationships file makes linking fail.
+	if err := os.Mkdir(store.relationshipsFile(), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	if _, _, err := store.GetOrCreateContact("Jane Doe", "jane@acme.com", &acme.ID); err == nil {
+		t.Fatal("GetOrCreateContact with failing link: expected error, got nil")
+	}
+	if all, _ := store.GetAllContacts(); len(all) != 0 {
+		t.Errorf("contacts = %d, want 0 after the failed link", len(all))
+	}
+}
@@ -0,0 +1,240 @@
+// ABOUTME: Backend-independent copy of every record from one storage backend to another.
+// ABOUTME: Preserves IDs and timestamps and skips records the destination already holds, so reruns are safe.
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// MigrateReport counts the records MigrateStore copied, the records the
+// destination already held, and the records that failed to copy.
+type MigrateReport struct {
+	Contacts      int      `json:"contacts"`
+	Companies     int      `json:"companies"`
+	Relationships int      `json:"relationships"`
+	Attachments   int      `json:"attachments"`
+	CompanyNotes  int      `json:"company_notes"`
+	ContactNotes  int      `json:"contact_notes"`
+	Photos        int      `json:"photos"`
+	Settings      int      `json:"settings"`
+	Skipped       int      `json:"skipped"`
+	Errors        []string `json:"errors,omitempty"`
+}
+
+// MigrateStore copies every company, contact, note, contact photo,
+// relationship, attachment, and setting from src to dst, preserving IDs and timestamps. Entities are copied before
+// the records that reference them. A record that fails to copy is noted in
+// the report and skipped; an error is returned only when src cannot be read.
+func MigrateStore(src, dst Storage) (*MigrateReport, error) {
+	report := &MigrateReport{}
+	var entityIDs, companyIDs []uuid.UUID
+
+	err := src.ForEachCompany(func(c *models.Company) error {
+		companyIDs = append(companyIDs, c.ID)
+		entityIDs = append(entityIDs, c.ID)
+		if _, err := dst.GetCompany(c.ID); err == nil {
+			report.Skipped++
+			return nil
+		}
+		report.record(&report.Companies, "company", c.ID, dst.CreateCompany(c))
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("read companies: %w", err)
+	}
+	if err := migrateCompanyNotes(src, dst, companyIDs, report); err != nil {
+		return report, err
+	}
+
+	var contactIDs []uuid.UUID
+	err = src.ForEachContact(func(c *models.Contact) error {
+		entityIDs = append(entityIDs, c.ID)
+		contactIDs = append(contactIDs, c.ID)
+		if _, err := dst.GetContact(c.ID); err == nil {
+			report.Skipped++
+			return nil
+		}
+		if err := dst.CreateContact(c); err != nil {
+			report.record(&report.Contacts, "contact", c.ID, err)
+			return nil
+		}
+		report.Contacts++
+		return migratePhoto(src, dst, c.ID, report)
+	})
+	if err != nil {
+		return report, fmt.Errorf("read contacts: %w", err)
+	}
+	if err := migrateContactNotes(src, dst, contactIDs, report); err != nil {
+		return report, err
+	}
+
+	if err := migrateRelationships(src, dst, entityIDs, report); err != nil {
+		return report, err
+	}
+	if err := migrateAttachments(src, dst, entityIDs, report); err != nil {
+		return report, err
+	}
+	if err := migrateSettings(src, dst, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// migrateSettings copies each setting whose key dst does not already hold,
+// leaving values already set in dst untouched.
+func migrateSettings(src, dst Storage, report *MigrateReport) error {
+	settings, err := src.ListSettings()
+	if err != nil {
+		return fmt.Errorf("read settings: %w", err)
+	}
+	existing, err := dst.ListSettings()
+	if err != nil {
+		return fmt.Errorf("read destination settings: %w", err)
+	}
+	for key, value := range settings {
+		if _, ok := existing[key]; ok {
+			report.Skipped++
+			continue
+		}
+		if err := dst.SetSetting(key, value); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("setting %s: %v", key, err))
+			continue
+		}
+		report.Settings++
+	}
+	return nil
+}
+
+// migrateCompanyNotes copies each company's notes that dst does not
+// already hold.
+func migrateCompanyNotes(src, dst Storage, companyIDs []uuid.UUID, report *MigrateReport) error {
+	for _, id := range companyIDs {
+		notes, err := src.ListCompanyNotes(id)
+		if err != nil {
+			return fmt.Errorf("read company notes: %w", err)
+		}
+		if len(notes) == 0 {
+			continue
+		}
+		existing, err := dst.ListCompanyNotes(id)
+		if err != nil {
+			return fmt.Errorf("read destination company notes: %w", err)
+		}
+		have := make(map[uuid.UUID]bool, len(existing))
+		for _, n := range existing {
+			have[n.ID] = true
+		}
+		for _, n := range notes {
+			if have[n.ID] {
+				report.Skipped++
+				continue
+			}
+			report.record(&report.CompanyNotes, "company note", n.ID, dst.AddCompanyNote(n))
+		}
+	}
+	return nil
+}
+
+// migrateContactNotes copies each contact's notes that dst does not
+// already hold.
+func migrateContactNotes(src, dst Storage, contactIDs []uuid.UUID, report *MigrateReport) error {
+	for _, id := range contactIDs {
+		notes, err := src.ListContactNotes(id)
+		if err != nil {
+			return fmt.Errorf("read contact notes: %w", err)
+		}
+		if len(notes) == 0 {
+			continue
+		}
+		existing, err := dst.ListContactNotes(id)
+		if err != nil {
+			return fmt.Errorf("read destination contact notes: %w", err)
+		}
+		have := make(map[uuid.UUID]bool, len(existing))
+		for _, n := range existing {
+			have[n.ID] = true
+		}
+		for _, n := range notes {
+			if have[n.ID] {
+				report.Skipped++
+				continue
+			}
+			report.record(&report.ContactNotes, "contact note", n.ID, dst.AddContactNote(n))
+		}
+	}
+	return nil
+}
+
+// migratePhoto copies the photo of a newly copied contact, if it has one.
+func migratePhoto(src, dst Storage, id uuid.UUID, report *MigrateReport) error {
+	data, contentType, err := src.GetContactPhoto(id)
+	if errors.Is(err, ErrPhotoNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read photo %s: %w", id, err)
+	}
+	report.record(&report.Photos, "photo", id, dst.SetContactPhoto(id, contentType, data))
+	return nil
+}
+
+// migrateRelationships copies each relationship once, however many of its
+// endpoints list it.
+func migrateRelationships(src, dst Storage, entityIDs []uuid.UUID, report *MigrateReport) error {
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range entityIDs {
+		rels, err := src.ListRelationships(id)
+		if err != nil {
+			return fmt.Errorf("read relationships: %w", err)
+		}
+		for _, rel := range rels {
+			if seen[rel.ID] {
+				continue
+			}
+			seen[rel.ID] = true
+			err := dst.CreateRelationship(rel)
+			if errors.Is(err, ErrDuplicateRelationship) {
+				report.Skipped++
+				continue
+			}
+			report.record(&report.Relationships, "relationship", rel.ID, err)
+		}
+	}
+	return nil
+}
+
+// migrateAttachments copies each entity's attachments, including inline
+// data, which ListAttachments leaves out.
+func migrateAttachments(src, dst Storage, entityIDs []uuid.UUID, report *MigrateReport) error {
+	for _, id := range entityIDs {
+		attachments, err := src.ListAttachments(id)
+		if err != nil {
+			return fmt.Errorf("read attachments: %w", err)
+		}
+		for _, meta := range attachments {
+			if _, err := dst.GetAttachment(meta.ID); err == nil {
+				report.Skipped++
+				continue
+			}
+			a, err := src.GetAttachment(meta.ID)
+			if err != nil {
+				return fmt.Errorf("read attachment %s: %w", meta.ID, err)
+			}
+			report.record(&report.Attachments, "attachment", a.ID, dst.AddAttachment(a))
+		}
+	}
+	return nil
+}
+
+// record counts a successful copy or notes why it failed.
+func (r *MigrateReport) record(count *int, kind string, id uuid.UUID, err error) {
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s %s: %v", kind, id, err))
+		return
+	}
+	*count++
+}
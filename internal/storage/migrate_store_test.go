@@ -0,0 +1,106 @@
+// ABOUTME: Tests for copying every record between storage backends.
+// ABOUTME: Migrates SQLite into markdown and checks IDs, timestamps, links, and inline data survive a rerun.
+package storage
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestMigrateStore(t *testing.T) {
+	src := newTestStore(t)
+	dst := newTestMarkdownStore(t)
+
+	created := time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)
+	acme := models.NewCompany("Acme")
+	acme.CreatedAt, acme.UpdatedAt = created, created
+	if err := src.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	jane := models.NewContact("Jane")
+	jane.Email = "jane@acme.com"
+	if err := src.CreateContact(jane); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := src.CreateRelationship(models.NewRelationship(jane.ID, acme.ID, "works_at", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	if err := src.SetContactPhoto(jane.ID, "image/png", []byte("png")); err != nil {
+		t.Fatalf("SetContactPhoto: %v", err)
+	}
+	if err := src.AddCompanyNote(models.NewCompanyNote(acme.ID, "Met at the conference")); err != nil {
+		t.Fatalf("AddCompanyNote: %v", err)
+	}
+	if err := src.AddContactNote(models.NewContactNote(jane.ID, "Prefers email")); err != nil {
+		t.Fatalf("AddContactNote: %v", err)
+	}
+	note := models.NewAttachment(EntityContact, jane.ID, "note.txt")
+	note.Data = []byte("hello")
+	if err := src.AddAttachment(note); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	if err := src.SetSetting("theme", "dark"); err != nil {
+		t.Fatalf("SetSetting: %v", err)
+	}
+
+	report, err := MigrateStore(src, dst)
+	if err != nil {
+		t.Fatalf("MigrateStore: %v", err)
+	}
+	if report.Companies != 1 || report.Contacts != 1 || report.Relationships != 1 || report.Attachments != 1 || report.Settings != 1 || len(report.Errors) != 0 {
+		t.Fatalf("report = %+v, want one of each and no errors", report)
+	}
+
+	gotCo, err := dst.GetCompany(acme.ID)
+	if err != nil {
+		t.Fatalf("GetCompany in dst: %v", err)
+	}
+	if !gotCo.CreatedAt.Equal(created) {
+		t.Errorf("company created_at = %v, want %v", gotCo.CreatedAt, created)
+	}
+	gotJane, err := dst.GetContact(jane.ID)
+	if err != nil {
+		t.Fatalf("GetContact in dst: %v", err)
+	}
+	if gotJane.Email != "jane@acme.com" {
+		t.Errorf("contact email = %q, want jane@acme.com", gotJane.Email)
+	}
+	rels, err := dst.ListRelationships(jane.ID)
+	if err != nil || len(rels) != 1 {
+		t.Errorf("dst relationships = %v (err %v), want 1", rels, err)
+	}
+	att, err := dst.GetAttachment(note.ID)
+	if err != nil {
+		t.Fatalf("GetAttachment in dst: %v", err)
+	}
+	if !bytes.Equal(att.Data, []byte("hello")) {
+		t.Errorf("attachment data = %q, want hello", att.Data)
+	}
+	if theme, ok, err := dst.GetSetting("theme"); err != nil || !ok || theme != "dark" {
+		t.Errorf("dst setting theme = %q, %v (err %v), want dark", theme, ok, err)
+	}
+	notes, err := dst.ListCompanyNotes(acme.ID)
+	if err != nil || len(notes) != 1 || notes[0].Content != "Met at the conference" {
+		t.Errorf("dst company notes = %v (err %v), want the conference note", notes, err)
+	}
+	contactNotes, err := dst.ListContactNotes(jane.ID)
+	if err != nil || len(contactNotes) != 1 {
+		t.Errorf("dst contact notes = %v (err %v), want one", contactNotes, err)
+	}
+	photo, photoType, err := dst.GetContactPhoto(jane.ID)
+	if err != nil || string(photo) != "png" || photoType != "image/png" {
+		t.Errorf("dst photo = %q %q (err %v), want png image/png", photo, photoType, err)
+	}
+
+	again, err := MigrateStore(src, dst)
+	if err != nil {
+		t.Fatalf("second MigrateStore: %v", err)
+	}
+	if again.Skipped != 7 || again.Contacts+again.Companies+again.CompanyNotes+again.ContactNotes+again.Relationships+again.Attachments+again.Settings != 0 {
+		t.Errorf("rerun report = %+v, want everything skipped", again)
+	}
+}
@@ -0,0 +1,81 @@
+// ABOUTME: Backend-independent pieces of the notes history kept on contacts and companies.
+// ABOUTME: Describes each entity type's notes, validates content, and converts to the typed models.
+package storage
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// noteKind describes where the notes for one entity type are kept, so both
+// backends share one implementation across contacts and companies.
+type noteKind struct {
+	entityType  string // EntityContact or EntityCompany
+	table       string // SQLite notes table
+	ownerColumn string // SQLite column and YAML key holding the owner's ID
+	ownerTable  string // SQLite table the owner lives in
+	file        string // markdown YAML file under the data directory
+	notFound    error  // returned when the owner does not exist
+}
+
+var (
+	companyNotes = noteKind{
+		entityType:  EntityCompany,
+		table:       "company_notes",
+		ownerColumn: "company_id",
+		ownerTable:  "companies",
+		file:        "_company_notes.yaml",
+		notFound:    ErrCompanyNotFound,
+	}
+	contactNotes = noteKind{
+		entityType:  EntityContact,
+		table:       "contact_notes",
+		ownerColumn: "contact_id",
+		ownerTable:  "contacts",
+		file:        "_contact_notes.yaml",
+		notFound:    ErrContactNotFound,
+	}
+)
+
+// note holds the fields CompanyNote and ContactNote share.
+type note struct {
+	ID        uuid.UUID
+	OwnerID   uuid.UUID
+	Content   string
+	CreatedAt time.Time
+}
+
+// validateNoteContent reports ErrNoteContentRequired for blank content.
+func validateNoteContent(content string) error {
+	if strings.TrimSpace(content) == "" {
+		return ErrNoteContentRequired
+	}
+	return nil
+}
+
+func fromCompanyNote(n *models.CompanyNote) note {
+	return note{ID: n.ID, OwnerID: n.CompanyID, Content: n.Content, CreatedAt: n.CreatedAt}
+}
+
+func fromContactNote(n *models.ContactNote) note {
+	return note{ID: n.ID, OwnerID: n.ContactID, Content: n.Content, CreatedAt: n.CreatedAt}
+}
+
+func toCompanyNotes(notes []note) []*models.CompanyNote {
+	var out []*models.CompanyNote
+	for _, n := range notes {
+		out = append(out, &models.CompanyNote{ID: n.ID, CompanyID: n.OwnerID, Content: n.Content, CreatedAt: n.CreatedAt})
+	}
+	return out
+}
+
+func toContactNotes(notes []note) []*models.ContactNote {
+	var out []*models.ContactNote
+	for _, n := range notes {
+		out = append(out, &models.ContactNote{ID: n.ID, ContactID: n.OwnerID, Content: n.Content, CreatedAt: n.CreatedAt})
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+// ABOUTME: Tests for the notes history on contacts and companies across both backends.
+// ABOUTME: One table covers ordering, validation, deletion, and cleanup when the owner is deleted.
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// noteOps adapts one entity type's typed note methods to the shared note
+// shape so a single test body can exercise both.
+type noteOps struct {
+	kind        noteKind
+	createOwner func(Storage) (uuid.UUID, error)
+	deleteOwner func(Storage, uuid.UUID) error
+	add         func(Storage, note) error
+	list        func(Storage, uuid.UUID) ([]note, error)
+	remove      func(Storage, uuid.UUID) error
+}
+
+var noteOpsByKind = []noteOps{
+	{
+		kind: companyNotes,
+		createOwner: func(s Storage) (uuid.UUID, error) {
+			c := models.NewCompany("Acme")
+			return c.ID, s.CreateCompany(c)
+		},
+		deleteOwner: func(s Storage, id uuid.UUID) error { return s.DeleteCompany(id) },
+		add: func(s Storage, n note) error {
+			return s.AddCompanyNote(&models.CompanyNote{ID: n.ID, CompanyID: n.OwnerID, Content: n.Content, CreatedAt: n.CreatedAt})
+		},
+		list: func(s Storage, id uuid.UUID) ([]note, error) {
+			notes, err := s.ListCompanyNotes(id)
+			var out []note
+			for _, n := range notes {
+				out = append(out, fromCompanyNote(n))
+			}
+			return out, err
+		},
+		remove: func(s Storage, id uuid.UUID) error { return s.DeleteCompanyNote(id) },
+	},
+	{
+		kind: contactNotes,
+		createOwner: func(s Storage) (uuid.UUID, error) {
+			c := models.NewContact("Alice")
+			return c.ID, s.CreateContact(c)
+		},
+		deleteOwner: func(s Storage, id uuid.UUID) error { return s.DeleteContact(id) },
+		add: func(s Storage, n note) error {
+			return s.AddContactNote(&models.ContactNote{ID: n.ID, ContactID: n.OwnerID, Content: n.Content, CreatedAt: n.CreatedAt})
+		},
+		list: func(s Storage, id uuid.UUID) ([]note, error) {
+			notes, err := s.ListContactNotes(id)
+			var out []note
+			for _, n := range notes {
+				out = append(out, fromContactNote(n))
+			}
+			return out, err
+		},
+		remove: func(s Storage, id uuid.UUID) error { return s.DeleteContactNote(id) },
+	},
+}
+
+func TestNotes(t *testing.T) {
+	backends := map[string]func(*testing.T) Storage{
+		"sqlite":   func(t *testing.T) Storage { return newTestStore(t) },
+		"markdown": func(t *testing.T) Storage { return newTestMarkdownStore(t) },
+	}
+	for backend, open := range backends {
+		for _, ops := range noteOpsByKind {
+			t.Run(backend+"/"+ops.kind.entityType, func(t *testing.T) {
+				checkNotes(t, open(t), ops)
+			})
+		}
+	}
+}
+
+// checkNotes verifies one entity type's notes on store.
+func checkNotes(t *testing.T, store Storage, ops noteOps) {
+	t.Helper()
+	owner, err := ops.createOwner(store)
+	if err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+
+	newNote := func(content string, at time.Time) note {
+		return note{ID: uuid.New(), OwnerID: owner, Content: content, CreatedAt: at}
+	}
+	first := newNote("Intro call went well", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	second := newNote("Sent proposal", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	for _, n := range []note{second, first} {
+		if err := ops.add(store, n); err != nil {
+			t.Fatalf("add note: %v", err)
+		}
+	}
+
+	if err := ops.add(store, newNote("  ", time.Now())); !errors.Is(err, ErrNoteContentRequired) {
+		t.Errorf("blank note: err = %v, want ErrNoteContentRequired", err)
+	}
+	orphan := newNote("hello", time.Now())
+	orphan.OwnerID = uuid.New()
+	if err := ops.add(store, orphan); !errors.Is(err, ops.kind.notFound) {
+		t.Errorf("unknown owner: err = %v, want %v", err, ops.kind.notFound)
+	}
+
+	notes, err := ops.list(store, owner)
+	if err != nil {
+		t.Fatalf("list notes: %v", err)
+	}
+	if len(notes) != 2 || notes[0].ID != first.ID || notes[1].Content != "Sent proposal" {
+		t.Fatalf("notes = %+v, want oldest first", notes)
+	}
+
+	if err := ops.remove(store, first.ID); err != nil {
+		t.Fatalf("delete note: %v", err)
+	}
+	if err := ops.remove(store, first.ID); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("second delete: err = %v, want ErrNoteNotFound", err)
+	}
+
+	if err := ops.deleteOwner(store, owner); err != nil {
+		t.Fatalf("delete owner: %v", err)
+	}
+	notes, err = ops.list(store, owner)
+	if err != nil {
+		t.Fatalf("list notes after owner delete: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("notes left after owner delete: %+v", notes)
+	}
+	if err := ops.remove(store, second.ID); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("note survived owner delete: err = %v, want ErrNoteNotFound", err)
+	}
+}
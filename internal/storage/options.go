@@ -0,0 +1,90 @@
+// ABOUTME: Optional behavior switches shared by every storage backend.
+// ABOUTME: Passed to the *WithOptions constructors; the zero value preserves default behavior.
+package storage
+
+import "time"
+
+// Options configures optional storage behavior.
+type Options struct {
+	// TrackAccess records each contact and company read so ListRecent can
+	// return recently viewed entities. Off by default because it turns every
+	// read into a read plus a write.
+	TrackAccess bool
+
+	// EnforceUniqueEmail rejects a contact whose normalized email another
+	// contact already uses. Off by default because shared inboxes are
+	// legitimate; blank emails never conflict.
+	EnforceUniqueEmail bool
+
+	// IdempotencyTTL is how long an idempotency key keeps mapping to the
+	// entity it created. Zero means DefaultIdempotencyTTL.
+	IdempotencyTTL time.Duration
+
+	// ChangeRetention is how long change feed and deletion log entries are
+	// kept; older entries are pruned when the store is opened. Zero means
+	// DefaultChangeRetention. Consumers that fall further behind than this
+	// must resync from a full export.
+	ChangeRetention time.Duration
+
+	// DefaultLimit caps list results when a filter omits a limit. Zero means
+	// the package DefaultLimit.
+	DefaultLimit int
+
+	// MaxLimit is the largest limit a filter may request; larger requests are
+	// clamped. Zero means the package MaxLimit.
+	MaxLimit int
+}
+
+// DefaultLimit and MaxLimit bound ListContacts and ListCompanies when
+// Options leaves them unset.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// listLimit returns the effective limit for a requested list size.
+func (o Options) listLimit(requested int) int {
+	maxLimit := o.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = MaxLimit
+	}
+	def := o.DefaultLimit
+	if def <= 0 {
+		def = DefaultLimit
+	}
+	switch {
+	case requested <= 0:
+		return min(def, maxLimit)
+	case requested > maxLimit:
+		return maxLimit
+	default:
+		return requested
+	}
+}
+
+// DefaultIdempotencyTTL is the idempotency key lifetime used when
+// Options.IdempotencyTTL is unset.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyTTL returns the effective idempotency key lifetime.
+func (o Options) idempotencyTTL() time.Duration {
+	if o.IdempotencyTTL <= 0 {
+		return DefaultIdempotencyTTL
+	}
+	return o.IdempotencyTTL
+}
+
+// DefaultChangeRetention is the change log retention used when
+// Options.ChangeRetention is unset.
+const DefaultChangeRetention = 90 * 24 * time.Hour
+
+// changeRetention returns the effective change log retention.
+func (o Options) changeRetention() time.Duration {
+	if o.ChangeRetention <= 0 {
+		return DefaultChangeRetention
+	}
+	return o.ChangeRetention
+}
+
+// maxRecentAccess bounds the number of access records kept per backend.
+const maxRecentAccess = 200
@@ -0,0 +1,39 @@
+// ABOUTME: Backend-independent helpers for the settings key/value store.
+// ABOUTME: Validates keys and reads and writes JSON-encoded setting values.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// validateSettingKey reports ErrSettingKeyRequired for a blank key.
+func validateSettingKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return ErrSettingKeyRequired
+	}
+	return nil
+}
+
+// GetSettingJSON decodes the JSON value stored under key into v. It
+// reports false, leaving v untouched, when the key is unset.
+func GetSettingJSON(s Storage, key string, v any) (bool, error) {
+	raw, ok, err := s.GetSetting(key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(raw), v); err != nil {
+		return false, fmt.Errorf("decode setting %q: %w", key, err)
+	}
+	return true, nil
+}
+
+// SetSettingJSON stores v under key as JSON.
+func SetSettingJSON(s Storage, key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode setting %q: %w", key, err)
+	}
+	return s.SetSetting(key, string(raw))
+}
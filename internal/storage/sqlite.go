@@ -16,14 +16,26 @@ import (
 type SqliteStore struct {
 	db     *sql.DB
 	dbPath string
+	opts   Options
+}
+
+// execer runs a statement on either the database or a transaction, so
+// insert helpers can be shared by standalone writes and multi-step ones.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
 }
 
 // Compile-time check that SqliteStore satisfies the Storage interface.
 var _ Storage = (*SqliteStore)(nil)
 
-// NewSqliteStore creates a new SqliteStore, ensuring parent directories exist,
-// opening the database with foreign keys and WAL mode, and initializing the schema.
+// NewSqliteStore creates a new SqliteStore with default options.
 func NewSqliteStore(dbPath string) (*SqliteStore, error) {
+	return NewSqliteStoreWithOptions(dbPath, Options{})
+}
+
+// NewSqliteStoreWithOptions creates a new SqliteStore, ensuring parent directories
+// exist, opening the database with foreign keys and WAL mode, and initializing the schema.
+func NewSqliteStoreWithOptions(dbPath string, opts Options) (*SqliteStore, error) {
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0o750); err != nil {
 		return nil, fmt.Errorf("create parent dirs: %w", err)
 	}
@@ -39,17 +51,21 @@ func NewSqliteStore(dbPath string) (*SqliteStore, error) {
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	store := &SqliteStore{db: db, dbPath: dbPath}
+	store := &SqliteStore{db: db, dbPath: dbPath, opts: opts}
 	if err := store.initSchema(); err != nil {
 		_ = db.Close()
 		return nil, fmt.Errorf("init schema: %w", err)
 	}
+	if err := store.pruneChangeLogs(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
 
 	return store, nil
 }
 
-// initSchema creates all tables, indexes, FTS5 virtual tables, and triggers
-// inside a single transaction.
+// initSchema creates all tables, indexes, FTS5 virtual tables, and triggers,
+// and migrates older databases forward, inside a single transaction.
 func (s *SqliteStore) initSchema() error {
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -57,8 +73,32 @@ func (s *SqliteStore) initSchema() error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	stmts := append(tableStatements(), ftsStatements()...)
-	for _, stmt := range stmts {
+	for _, stmt := range tableStatements() {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("exec schema statement: %w", err)
+		}
+	}
+	if err := addMissingColumns(tx); err != nil {
+		return err
+	}
+	if err := normalizeTimestamps(tx); err != nil {
+		return err
+	}
+	for _, stmt := range changeFeedStatements() {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("exec schema statement: %w", err)
+		}
+	}
+	if err := ensureRelationshipPairIndex(tx); err != nil {
+		return err
+	}
+	if err := migratePhoneKeys(tx); err != nil {
+		return err
+	}
+	if err := s.syncUniqueEmailIndex(tx); err != nil {
+		return err
+	}
+	for _, stmt := range ftsStatements() {
 		if _, err := tx.Exec(stmt); err != nil {
 			return fmt.Errorf("exec schema statement: %w", err)
 		}
@@ -99,10 +139,65 @@ func tableStatements() []string {
 			context TEXT DEFAULT '',
 			created_at DATETIME NOT NULL
 		)`,
+		`CREATE TABLE IF NOT EXISTS attachments (
+			id TEXT PRIMARY KEY,
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			filename TEXT NOT NULL,
+			content_type TEXT DEFAULT '',
+			size INTEGER NOT NULL DEFAULT 0,
+			path TEXT DEFAULT '',
+			data BLOB,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS company_notes (
+			id TEXT PRIMARY KEY,
+			company_id TEXT NOT NULL,
+			content TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS contact_notes (
+			id TEXT PRIMARY KEY,
+			contact_id TEXT NOT NULL,
+			content TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS recent_access (
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			last_accessed DATETIME NOT NULL,
+			access_count INTEGER NOT NULL DEFAULT 1,
+			PRIMARY KEY (entity_type, entity_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			entity_type TEXT NOT NULL,
+			key TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			PRIMARY KEY (entity_type, key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS deletions (
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			deleted_at DATETIME NOT NULL
+		)`,
 		`CREATE INDEX IF NOT EXISTS idx_contacts_id ON contacts(id)`,
 		`CREATE INDEX IF NOT EXISTS idx_companies_id ON companies(id)`,
 		`CREATE INDEX IF NOT EXISTS idx_relationships_source_id ON relationships(source_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_relationships_target_id ON relationships(target_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_attachments_entity_id ON attachments(entity_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_company_notes_company_id ON company_notes(company_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_contact_notes_contact_id ON contact_notes(contact_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_recent_access_last_accessed ON recent_access(last_accessed)`,
+		`CREATE INDEX IF NOT EXISTS idx_contacts_updated_at ON contacts(updated_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_companies_updated_at ON companies(updated_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_deletions_deleted_at ON deletions(deleted_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_deletions_entity_id ON deletions(entity_id)`,
 	}
 }
 
@@ -151,10 +246,14 @@ func escapeFTS5Query(query string) string {
 	return `"` + escaped + `"`
 }
 
-// Close closes the underlying database connection.
+// Close checkpoints the write-ahead log and closes the database.
 func (s *SqliteStore) Close() error {
 	if s.db == nil {
 		return nil
 	}
+	// Fold the WAL back into the main file so a clean shutdown leaves a
+	// self-contained database. Failure here is not fatal; SQLite replays the
+	// WAL on next open.
+	_, _ = s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
 	return s.db.Close()
 }
@@ -0,0 +1,123 @@
+// ABOUTME: SQLite attachment operations for files linked to contacts and companies.
+// ABOUTME: Stores small files inline as blobs and larger ones as path references.
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// AddAttachment validates and inserts a new attachment.
+func (s *SqliteStore) AddAttachment(a *models.Attachment) error {
+	if err := validateAttachment(a); err != nil {
+		return err
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO attachments (id, entity_type, entity_id, filename, content_type, size, path, data, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID.String(), a.EntityType, a.EntityID.String(), a.Filename, a.ContentType,
+		a.Size, a.Path, a.Data, a.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert attachment: %w", err)
+	}
+	return nil
+}
+
+// GetAttachment retrieves an attachment including its inline data,
+// returning ErrAttachmentNotFound on miss.
+func (s *SqliteStore) GetAttachment(id uuid.UUID) (*models.Attachment, error) {
+	var a models.Attachment
+	var idStr, entityIDStr string
+	var createdAt time.Time
+
+	err := s.db.QueryRow(`
+		SELECT id, entity_type, entity_id, filename, content_type, size, path, data, created_at
+		FROM attachments WHERE id = ?`, id.String(),
+	).Scan(&idStr, &a.EntityType, &entityIDStr, &a.Filename, &a.ContentType, &a.Size, &a.Path, &a.Data, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan attachment: %w", err)
+	}
+
+	if err := setAttachmentIDs(&a, idStr, entityIDStr); err != nil {
+		return nil, err
+	}
+	a.CreatedAt = createdAt.UTC()
+	return &a, nil
+}
+
+// ListAttachments returns attachment metadata for the given entity, oldest
+// first. Inline data is not loaded; use GetAttachment for the contents.
+func (s *SqliteStore) ListAttachments(entityID uuid.UUID) ([]*models.Attachment, error) {
+	rows, err := s.db.Query(`
+		SELECT id, entity_type, entity_id, filename, content_type, size, path, created_at
+		FROM attachments WHERE entity_id = ?
+		ORDER BY created_at`, entityID.String())
+	if err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		var a models.Attachment
+		var idStr, entityIDStr string
+		var createdAt time.Time
+
+		if err := rows.Scan(&idStr, &a.EntityType, &entityIDStr, &a.Filename, &a.ContentType, &a.Size, &a.Path, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan attachment row: %w", err)
+		}
+		if err := setAttachmentIDs(&a, idStr, entityIDStr); err != nil {
+			return nil, err
+		}
+		a.CreatedAt = createdAt.UTC()
+		attachments = append(attachments, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate attachment rows: %w", err)
+	}
+	return attachments, nil
+}
+
+// DeleteAttachment removes an attachment by UUID, returning
+// ErrAttachmentNotFound if no row matches.
+func (s *SqliteStore) DeleteAttachment(id uuid.UUID) error {
+	res, err := s.db.Exec("DELETE FROM attachments WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
+
+// setAttachmentIDs parses the stored attachment and entity UUIDs onto a.
+func setAttachmentIDs(a *models.Attachment, idStr, entityIDStr string) error {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return fmt.Errorf("parse attachment id: %w", err)
+	}
+	entityID, err := uuid.Parse(entityIDStr)
+	if err != nil {
+		return fmt.Errorf("parse entity_id: %w", err)
+	}
+	a.ID = id
+	a.EntityID = entityID
+	return nil
+}
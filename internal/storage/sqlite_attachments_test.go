@@ -0,0 +1,106 @@
+// ABOUTME: Tests for SQLite attachment operations.
+// ABOUTME: Covers blob and path storage, size limits, listing, and deletion.
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestAddAndGetAttachmentBlob(t *testing.T) {
+	store := newTestStore(t)
+
+	entityID := uuid.New()
+	a := models.NewAttachment("contact", entityID, "notes.txt")
+	a.ContentType = "text/plain"
+	a.Data = []byte("hello")
+
+	if err := store.AddAttachment(a); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	got, err := store.GetAttachment(a.ID)
+	if err != nil {
+		t.Fatalf("GetAttachment: %v", err)
+	}
+	if got.Filename != "notes.txt" {
+		t.Errorf("Filename = %q, want %q", got.Filename, "notes.txt")
+	}
+	if got.EntityID != entityID {
+		t.Errorf("EntityID = %v, want %v", got.EntityID, entityID)
+	}
+	if got.Size != 5 {
+		t.Errorf("Size = %d, want 5", got.Size)
+	}
+	if !bytes.Equal(got.Data, []byte("hello")) {
+		t.Errorf("Data = %q, want %q", got.Data, "hello")
+	}
+}
+
+func TestAddAttachmentValidation(t *testing.T) {
+	store := newTestStore(t)
+
+	neither := models.NewAttachment("contact", uuid.New(), "empty.bin")
+	if err := store.AddAttachment(neither); !errors.Is(err, ErrInvalidAttachment) {
+		t.Errorf("no path or data: got %v, want ErrInvalidAttachment", err)
+	}
+
+	both := models.NewAttachment("contact", uuid.New(), "both.bin")
+	both.Path = "/tmp/both.bin"
+	both.Data = []byte("x")
+	if err := store.AddAttachment(both); !errors.Is(err, ErrInvalidAttachment) {
+		t.Errorf("path and data: got %v, want ErrInvalidAttachment", err)
+	}
+
+	big := models.NewAttachment("contact", uuid.New(), "big.bin")
+	big.Data = make([]byte, MaxAttachmentBlobSize+1)
+	if err := store.AddAttachment(big); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("oversized blob: got %v, want ErrAttachmentTooLarge", err)
+	}
+}
+
+func TestListAndDeleteAttachments(t *testing.T) {
+	store := newTestStore(t)
+
+	entityID := uuid.New()
+	a1 := models.NewAttachment("company", entityID, "contract.pdf")
+	a1.Path = "/docs/contract.pdf"
+	a1.Size = 2048
+	a2 := models.NewAttachment("company", entityID, "logo.png")
+	a2.Data = []byte{0x89, 0x50}
+	other := models.NewAttachment("company", uuid.New(), "other.txt")
+	other.Path = "/docs/other.txt"
+
+	for _, a := range []*models.Attachment{a1, a2, other} {
+		if err := store.AddAttachment(a); err != nil {
+			t.Fatalf("AddAttachment: %v", err)
+		}
+	}
+
+	list, err := store.ListAttachments(entityID)
+	if err != nil {
+		t.Fatalf("ListAttachments: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("len = %d, want 2", len(list))
+	}
+	for _, a := range list {
+		if a.Data != nil {
+			t.Errorf("ListAttachments should not load data, got %d bytes for %s", len(a.Data), a.Filename)
+		}
+	}
+
+	if err := store.DeleteAttachment(a1.ID); err != nil {
+		t.Fatalf("DeleteAttachment: %v", err)
+	}
+	if _, err := store.GetAttachment(a1.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("GetAttachment after delete: got %v, want ErrAttachmentNotFound", err)
+	}
+	if err := store.DeleteAttachment(a1.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("second DeleteAttachment: got %v, want ErrAttachmentNotFound", err)
+	}
+}
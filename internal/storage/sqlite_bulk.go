@@ -0,0 +1,88 @@
+// ABOUTME: SQLite bulk fetch of contacts and companies by ID.
+// ABOUTME: Batches IDs into chunked IN queries to stay under SQLite's bound-parameter limit.
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// maxInParams caps the IDs bound in a single IN clause. SQLite builds
+// before 3.32 reject statements with more than 999 parameters.
+const maxInParams = 500
+
+// GetContactsByIDs returns the contacts with the given IDs, keyed by ID.
+// IDs with no matching contact are absent from the map. Unlike GetContact
+// it does not record access.
+func (s *SqliteStore) GetContactsByIDs(ids []uuid.UUID) (map[uuid.UUID]*models.Contact, error) {
+	result := make(map[uuid.UUID]*models.Contact, len(ids))
+	for _, chunk := range idChunks(ids) {
+		rows, err := s.db.Query(`
+			SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+			FROM contacts WHERE id IN (`+placeholderList(len(chunk))+`)`, chunk...)
+		if err != nil {
+			return nil, fmt.Errorf("get contacts by ids: %w", err)
+		}
+		contacts, err := scanContactRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range contacts {
+			result[c.ID] = c
+		}
+	}
+	return result, nil
+}
+
+// GetCompaniesByIDs returns the companies with the given IDs, keyed by ID.
+// IDs with no matching company are absent from the map.
+func (s *SqliteStore) GetCompaniesByIDs(ids []uuid.UUID) (map[uuid.UUID]*models.Company, error) {
+	result := make(map[uuid.UUID]*models.Company, len(ids))
+	for _, chunk := range idChunks(ids) {
+		rows, err := s.db.Query(`
+			SELECT id, name, domain, fields, tags, created_at, updated_at, version, source
+			FROM companies WHERE id IN (`+placeholderList(len(chunk))+`)`, chunk...)
+		if err != nil {
+			return nil, fmt.Errorf("get companies by ids: %w", err)
+		}
+		companies, err := scanCompanyRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range companies {
+			result[c.ID] = c
+		}
+	}
+	return result, nil
+}
+
+// idChunks deduplicates ids and splits them into query arguments of at
+// most maxInParams each.
+func idChunks(ids []uuid.UUID) [][]any {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	var chunks [][]any
+	var chunk []any
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		chunk = append(chunk, id.String())
+		if len(chunk) == maxInParams {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// placeholderList returns n comma-separated bind placeholders.
+func placeholderList(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,60 @@
+// ABOUTME: Tests for SQLite bulk fetch of contacts and companies by ID.
+// ABOUTME: Covers missing and duplicate IDs and ID lists longer than one IN chunk.
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestGetContactsByIDs(t *testing.T) {
+	store := newTestStore(t)
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+
+	// Pad past one chunk so the second query has to find Bob.
+	ids := []uuid.UUID{alice.ID, alice.ID}
+	for range maxInParams {
+		ids = append(ids, uuid.New())
+	}
+	ids = append(ids, bob.ID)
+
+	got, err := store.GetContactsByIDs(ids)
+	if err != nil {
+		t.Fatalf("GetContactsByIDs: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("GetContactsByIDs returned %d contacts, want 2", len(got))
+	}
+	if got[alice.ID].Name != "Alice" || got[bob.ID].Name != "Bob" {
+		t.Errorf("GetContactsByIDs = %v", got)
+	}
+
+	empty, err := store.GetContactsByIDs(nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("GetContactsByIDs(nil) = %v, %v; want empty map", empty, err)
+	}
+}
+
+func TestGetCompaniesByIDs(t *testing.T) {
+	store := newTestStore(t)
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	got, err := store.GetCompaniesByIDs([]uuid.UUID{acme.ID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetCompaniesByIDs: %v", err)
+	}
+	if len(got) != 1 || got[acme.ID].Name != "Acme" {
+		t.Errorf("GetCompaniesByIDs = %v, want only Acme", got)
+	}
+}
@@ -0,0 +1,29 @@
+// ABOUTME: SQLite cleanup of records that depend on a deleted contact or company.
+// ABOUTME: Shared by DeleteContact and DeleteCompany so both cascade the same way.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// deleteDependents removes the relationships, attachments, and notes that
+// belong to entity id, inside the caller's transaction. Attachments stored
+// by path only lose their metadata; the referenced files are left alone.
+func deleteDependents(tx *sql.Tx, id uuid.UUID) error {
+	if err := deleteRelationshipsFor(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM attachments WHERE entity_id = ?", id.String()); err != nil {
+		return fmt.Errorf("delete attachments: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM company_notes WHERE company_id = ?", id.String()); err != nil {
+		return fmt.Errorf("delete company notes: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM contact_notes WHERE contact_id = ?", id.String()); err != nil {
+		return fmt.Errorf("delete contact notes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// ABOUTME: SQLite queries for incremental export: modified-since, the deletion log, and the change feed.
+// ABOUTME: Triggers append every contact and company write to change_feed with an increasing sequence.
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// GetContactsModifiedSince returns contacts updated strictly after since,
+// oldest change first.
+func (s *SqliteStore) GetContactsModifiedSince(since time.Time) ([]*models.Contact, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+		FROM contacts WHERE updated_at > ?
+		ORDER BY updated_at, id`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("list contacts modified since: %w", err)
+	}
+	return scanContactRows(rows)
+}
+
+// GetCompaniesModifiedSince returns companies updated strictly after since,
+// oldest change first.
+func (s *SqliteStore) GetCompaniesModifiedSince(since time.Time) ([]*models.Company, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, domain, fields, tags, created_at, updated_at, version, source
+		FROM companies WHERE updated_at > ?
+		ORDER BY updated_at, id`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("list companies modified since: %w", err)
+	}
+	return scanCompanyRows(rows)
+}
+
+// ListDeletionsSince returns contacts and companies deleted strictly after
+// since, oldest first.
+func (s *SqliteStore) ListDeletionsSince(since time.Time) ([]Deletion, error) {
+	rows, err := s.db.Query(`
+		SELECT entity_type, entity_id, deleted_at FROM deletions
+		WHERE deleted_at > ?
+		ORDER BY deleted_at`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("list deletions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var deletions []Deletion
+	for rows.Next() {
+		var d Deletion
+		var idStr string
+		if err := rows.Scan(&d.EntityType, &idStr, &d.DeletedAt); err != nil {
+			return nil, fmt.Errorf("scan deletion: %w", err)
+		}
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse deletion id: %w", err)
+		}
+		d.EntityID = id
+		d.DeletedAt = d.DeletedAt.UTC()
+		deletions = append(deletions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate deletions: %w", err)
+	}
+	return deletions, nil
+}
+
+// WasDeleted reports whether the deletion log records entity id.
+func (s *SqliteStore) WasDeleted(entityType string, id uuid.UUID) (bool, error) {
+	var found int
+	err := s.db.QueryRow("SELECT 1 FROM deletions WHERE entity_id = ? AND entity_type = ? LIMIT 1",
+		id.String(), entityType).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("lookup deletion: %w", err)
+	}
+	return true, nil
+}
+
+// recordDeletion logs the deletion of entity id inside the caller's
+// transaction.
+func recordDeletion(tx *sql.Tx, entityType string, id uuid.UUID) error {
+	_, err := tx.Exec("INSERT INTO deletions (entity_type, entity_id, deleted_at) VALUES (?, ?, ?)",
+		entityType, id.String(), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("record deletion: %w", err)
+	}
+	return nil
+}
+
+// changeFeedStatements returns DDL for the change_feed table and the
+// triggers that fill it. Recording in triggers keeps the feed in the same
+// transaction as the write it describes; changed_at is RFC 3339 text with
+// millisecond precision.
+func changeFeedStatements() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS change_feed (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			op TEXT NOT NULL,
+			changed_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_change_feed_changed_at ON change_feed(changed_at)`,
+		`CREATE TRIGGER IF NOT EXISTS contacts_feed_ai AFTER INSERT ON contacts BEGIN
+			INSERT INTO change_feed (entity_type, entity_id, op, changed_at) VALUES ('contact', new.id, 'create', strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS contacts_feed_au AFTER UPDATE ON contacts BEGIN
+			INSERT INTO change_feed (entity_type, entity_id, op, changed_at) VALUES ('contact', new.id, 'update', strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS contacts_feed_ad AFTER DELETE ON contacts BEGIN
+			INSERT INTO change_feed (entity_type, entity_id, op, changed_at) VALUES ('contact', old.id, 'delete', strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS companies_feed_ai AFTER INSERT ON companies BEGIN
+			INSERT INTO change_feed (entity_type, entity_id, op, changed_at) VALUES ('company', new.id, 'create', strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS companies_feed_au AFTER UPDATE ON companies BEGIN
+			INSERT INTO change_feed (entity_type, entity_id, op, changed_at) VALUES ('company', new.id, 'update', strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS companies_feed_ad AFTER DELETE ON companies BEGIN
+			INSERT INTO change_feed (entity_type, entity_id, op, changed_at) VALUES ('company', old.id, 'delete', strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+		END`,
+	}
+}
+
+// ReadChangeFeed returns up to limit change events with a sequence greater
+// than afterSeq, in sequence order. A non-positive limit uses the store's
+// default list limit.
+func (s *SqliteStore) ReadChangeFeed(afterSeq int64, limit int) ([]ChangeEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT seq, entity_type, entity_id, op, changed_at FROM change_feed
+		WHERE seq > ? ORDER BY seq LIMIT ?`, afterSeq, s.opts.listLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("read change feed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []ChangeEvent
+	for rows.Next() {
+		var e ChangeEvent
+		var idStr, changedAt string
+		if err := rows.Scan(&e.Seq, &e.EntityType, &idStr, &e.Op, &changedAt); err != nil {
+			return nil, fmt.Errorf("scan change event: %w", err)
+		}
+		if e.EntityID, err = uuid.Parse(idStr); err != nil {
+			return nil, fmt.Errorf("parse change event id: %w", err)
+		}
+		if e.ChangedAt, err = time.Parse(time.RFC3339Nano, strings.TrimSpace(changedAt)); err != nil {
+			return nil, fmt.Errorf("parse change event time: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate change feed: %w", err)
+	}
+	return events, nil
+}
+
+// pruneChangeLogs drops change feed and deletion entries older than the
+// retention period. AUTOINCREMENT keeps change sequence numbers increasing
+// even when every event is pruned.
+func (s *SqliteStore) pruneChangeLogs() error {
+	cutoff := time.Now().UTC().Add(-s.opts.changeRetention())
+	if _, err := s.db.Exec("DELETE FROM change_feed WHERE changed_at < ?",
+		cutoff.Format("2006-01-02T15:04:05.000Z")); err != nil {
+		return fmt.Errorf("prune change feed: %w", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM deletions WHERE deleted_at < ?", cutoff); err != nil {
+		return fmt.Errorf("prune deletion log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+// ABOUTME: Tests for SQLite modified-since queries and the deletion log.
+// ABOUTME: Verifies only later updates are returned and deletes are recorded per entity type.
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestGetContactsModifiedSince(t *testing.T) {
+	store := newTestStore(t)
+	checkpoint := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	old := models.NewContact("Old")
+	old.UpdatedAt = checkpoint.Add(-time.Hour)
+	changed := models.NewContact("Changed")
+	changed.UpdatedAt = checkpoint.Add(time.Hour)
+	for _, c := range []*models.Contact{old, changed} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+
+	got, err := store.GetContactsModifiedSince(checkpoint)
+	if err != nil {
+		t.Fatalf("GetContactsModifiedSince: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != changed.ID {
+		t.Errorf("modified since = %v, want only Changed", got)
+	}
+}
+
+func TestGetCompaniesModifiedSince(t *testing.T) {
+	store := newTestStore(t)
+	checkpoint := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	old := models.NewCompany("Old")
+	old.UpdatedAt = checkpoint.Add(-time.Hour)
+	if err := store.CreateCompany(old); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	old.Touch()
+	if err := store.UpdateCompany(old); err != nil {
+		t.Fatalf("UpdateCompany: %v", err)
+	}
+	got, err := store.GetCompaniesModifiedSince(checkpoint)
+	if err != nil {
+		t.Fatalf("GetCompaniesModifiedSince: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != old.ID {
+		t.Errorf("modified since = %v, want the updated company", got)
+	}
+}
+
+func TestListDeletionsSince(t *testing.T) {
+	store := newTestStore(t)
+	before := time.Now().Add(-time.Second)
+
+	c := models.NewContact("Gone")
+	co := models.NewCompany("Gone Inc")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := store.CreateCompany(co); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	if err := store.DeleteContact(c.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+	if err := store.DeleteCompany(co.ID); err != nil {
+		t.Fatalf("DeleteCompany: %v", err)
+	}
+
+	got, err := store.ListDeletionsSince(before)
+	if err != nil {
+		t.Fatalf("ListDeletionsSince: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("deletions = %+v, want 2", got)
+	}
+	if got[0].EntityType != EntityContact || got[0].EntityID != c.ID {
+		t.Errorf("first deletion = %+v, want contact %s", got[0], c.ID)
+	}
+	if got[1].EntityType != EntityCompany || got[1].EntityID != co.ID {
+		t.Errorf("second deletion = %+v, want company %s", got[1], co.ID)
+	}
+
+	later, err := store.ListDeletionsSince(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("ListDeletionsSince: %v", err)
+	}
+	if len(later) != 0 {
+		t.Errorf("deletions after now = %+v, want none", later)
+	}
+}
+
+func TestWasDeleted(t *testing.T) {
+	backends := map[string]func(*testing.T) Storage{
+		"sqlite":   func(t *testing.T) Storage { return newTestStore(t) },
+		"markdown": func(t *testing.T) Storage { return newTestMarkdownStore(t) },
+	}
+	for backend, open := range backends {
+		t.Run(backend, func(t *testing.T) {
+			store := open(t)
+			gone := models.NewContact("Gone")
+			kept := models.NewContact("Kept")
+			for _, c := range []*models.Contact{gone, kept} {
+				if err := store.CreateContact(c); err != nil {
+					t.Fatalf("CreateContact: %v", err)
+				}
+			}
+			if err := store.DeleteContact(gone.ID); err != nil {
+				t.Fatalf("DeleteContact: %v", err)
+			}
+
+			for _, tc := range []struct {
+				entityType string
+				id         uuid.UUID
+				want       bool
+			}{
+				{EntityContact, gone.ID, true},
+				{EntityContact, kept.ID, false},
+				{EntityCompany, gone.ID, false},
+				{EntityContact, uuid.New(), false},
+			} {
+				got, err := store.WasDeleted(tc.entityType, tc.id)
+				if err != nil {
+					t.Fatalf("WasDeleted: %v", err)
+				}
+				if got != tc.want {
+					t.Errorf("WasDeleted(%s, %s) = %v, want %v", tc.entityType, tc.id, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadChangeFeed(t *testing.T) {
+	store := newTestStore(t)
+
+	c := models.NewContact("Fed")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	c.Email = "fed@example.com"
+	if err := store.UpdateContact(c); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	co := models.NewCompany("Fed Inc")
+	if err := store.CreateCompany(co); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	if err := store.DeleteContact(c.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+
+	events, err := store.ReadChangeFeed(0, 0)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	want := []struct {
+		entity string
+		op     string
+	}{
+		{EntityContact, ChangeCreate},
+		{EntityContact, ChangeUpdate},
+		{EntityCompany, ChangeCreate},
+		{EntityContact, ChangeDelete},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want %d", events, len(want))
+	}
+	for i, w := range want {
+		if events[i].EntityType != w.entity || events[i].Op != w.op {
+			t.Errorf("event %d = %s %s, want %s %s", i, events[i].EntityType, events[i].Op, w.entity, w.op)
+		}
+		if i > 0 && events[i].Seq <= events[i-1].Seq {
+			t.Errorf("event %d seq %d does not follow %d", i, events[i].Seq, events[i-1].Seq)
+		}
+		if events[i].ChangedAt.IsZero() {
+			t.Errorf("event %d has no timestamp", i)
+		}
+	}
+
+	rest, err := store.ReadChangeFeed(events[1].Seq, 1)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	if len(rest) != 1 || rest[0].Seq != events[2].Seq {
+		t.Errorf("resumed feed = %+v, want only event %d", rest, events[2].Seq)
+	}
+}
+
+func TestPruneChangeLogs(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore: %v", err)
+	}
+	old := models.NewContact("Old")
+	if err := store.CreateContact(old); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := store.DeleteContact(old.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+	events, err := store.ReadChangeFeed(0, 0)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	lastSeq := events[len(events)-1].Seq
+	past := time.Now().UTC().Add(-DefaultChangeRetention - time.Hour)
+	if _, err := store.db.Exec("UPDATE change_feed SET changed_at = ?", past.Format("2006-01-02T15:04:05.000Z")); err != nil {
+		t.Fatalf("backdate change feed: %v", err)
+	}
+	if _, err := store.db.Exec("UPDATE deletions SET deleted_at = ?", past); err != nil {
+		t.Fatalf("backdate deletions: %v", err)
+	}
+	_ = store.Close()
+
+	store, err = NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if events, _ := store.ReadChangeFeed(0, 0); len(events) != 0 {
+		t.Errorf("change feed after prune = %+v, want empty", events)
+	}
+	if deletions, _ := store.ListDeletionsSince(time.Time{}); len(deletions) != 0 {
+		t.Errorf("deletions after prune = %+v, want empty", deletions)
+	}
+	c := models.NewContact("New")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	events, err = store.ReadChangeFeed(0, 0)
+	if err != nil {
+		t.Fatalf("ReadChangeFeed: %v", err)
+	}
+	if len(events) != 1 || events[0].Seq <= lastSeq {
+		t.Errorf("events after prune = %+v, want one event after seq %d", events, lastSeq)
+	}
+}
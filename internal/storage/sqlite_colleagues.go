@@ -0,0 +1,45 @@
+// ABOUTME: SQLite queries backing colleague relationship suggestions.
+// ABOUTME: Loads company memberships in one query and checks candidate pairs in batches.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SuggestColleagueRelationships proposes links between contacts that share
+// a company but have no relationship with each other yet.
+func (s *SqliteStore) SuggestColleagueRelationships(limit int) ([]*ColleagueSuggestion, error) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT co.id, co.name, c.id, c.name
+		FROM relationships r
+		JOIN companies co ON co.id IN (r.source_id, r.target_id)
+		JOIN contacts c ON c.id IN (r.source_id, r.target_id)
+		ORDER BY co.name, co.id, c.name, c.id`)
+	if err != nil {
+		return nil, fmt.Errorf("list company members: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var members []companyMember
+	for rows.Next() {
+		var m companyMember
+		var companyID, contactID string
+		if err := rows.Scan(&companyID, &m.companyName, &contactID, &m.contactName); err != nil {
+			return nil, fmt.Errorf("scan company member: %w", err)
+		}
+		if m.companyID, err = uuid.Parse(companyID); err != nil {
+			return nil, fmt.Errorf("parse company id: %w", err)
+		}
+		if m.contactID, err = uuid.Parse(contactID); err != nil {
+			return nil, fmt.Errorf("parse contact id: %w", err)
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate company members: %w", err)
+	}
+
+	return buildColleagueSuggestions(members, s.RelationshipExistsMany, limit)
+}
@@ -0,0 +1,70 @@
+// ABOUTME: Tests for SQLite colleague relationship suggestions.
+// ABOUTME: Verifies pairing within companies, skipping related pairs, and the limit cap.
+package storage
+
+import (
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestSuggestColleagueRelationships(t *testing.T) {
+	store := newTestStore(t)
+
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	carol := models.NewContact("Carol")
+	dave := models.NewContact("Dave")
+	for _, c := range []*models.Contact{alice, bob, carol, dave} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	rels := []*models.Relationship{
+		models.NewRelationship(alice.ID, acme.ID, "works_at", ""),
+		models.NewRelationship(acme.ID, bob.ID, "employs", ""),
+		models.NewRelationship(carol.ID, acme.ID, "works_at", ""),
+		// Alice and Carol already know each other, so they are not suggested.
+		models.NewRelationship(carol.ID, alice.ID, "knows", ""),
+	}
+	for _, r := range rels {
+		if err := store.CreateRelationship(r); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+
+	got, err := store.SuggestColleagueRelationships(0)
+	if err != nil {
+		t.Fatalf("SuggestColleagueRelationships: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("suggestions = %d, want 2 (Alice-Bob, Bob-Carol)", len(got))
+	}
+	for _, sg := range got {
+		if sg.CompanyID != acme.ID || sg.CompanyName != "Acme" {
+			t.Errorf("suggestion company = %s, want Acme", sg.CompanyName)
+		}
+		if pairKey(sg.SourceID, sg.TargetID) == pairKey(alice.ID, carol.ID) {
+			t.Error("already-related pair Alice-Carol was suggested")
+		}
+		if sg.SourceID == dave.ID || sg.TargetID == dave.ID {
+			t.Error("Dave has no company and should not be suggested")
+		}
+	}
+	if got[0].SourceName != "Alice" || got[0].TargetName != "Bob" {
+		t.Errorf("first suggestion = %s-%s, want Alice-Bob", got[0].SourceName, got[0].TargetName)
+	}
+
+	limited, err := store.SuggestColleagueRelationships(1)
+	if err != nil {
+		t.Fatalf("SuggestColleagueRelationships(1): %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("limited suggestions = %d, want 1", len(limited))
+	}
+}
@@ -26,11 +26,11 @@ func (s *SqliteStore) CreateCompany(c *models.Company) error {
 	}
 
 	_, err = s.db.Exec(`
-		INSERT INTO companies (id, name, domain, fields, tags, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		INSERT INTO companies (id, name, domain, fields, tags, created_at, updated_at, version, source)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		c.ID.String(), c.Name, c.Domain,
 		string(fieldsJSON), string(tagsJSON),
-		c.CreatedAt.UTC(), c.UpdatedAt.UTC(),
+		c.CreatedAt.UTC(), c.UpdatedAt.UTC(), max(c.Version, 1), c.Source,
 	)
 	if err != nil {
 		return fmt.Errorf("insert company: %w", err)
@@ -41,9 +41,14 @@ func (s *SqliteStore) CreateCompany(c *models.Company) error {
 // GetCompany retrieves a company by UUID, returning ErrCompanyNotFound on miss.
 func (s *SqliteStore) GetCompany(id uuid.UUID) (*models.Company, error) {
 	row := s.db.QueryRow(`
-		SELECT id, name, domain, fields, tags, created_at, updated_at
+		SELECT id, name, domain, fields, tags, created_at, updated_at, version, source
 		FROM companies WHERE id = ?`, id.String())
-	return scanCompany(row)
+	c, err := scanCompany(row)
+	if err != nil {
+		return nil, err
+	}
+	s.recordAccess(EntityCompany, c.ID)
+	return c, nil
 }
 
 // GetCompanyByPrefix finds a company whose ID starts with the given prefix.
@@ -55,7 +60,7 @@ func (s *SqliteStore) GetCompanyByPrefix(prefix string) (*models.Company, error)
 	}
 
 	rows, err := s.db.Query(`
-		SELECT id, name, domain, fields, tags, created_at, updated_at
+		SELECT id, name, domain, fields, tags, created_at, updated_at, version, source
 		FROM companies WHERE id LIKE ?`, prefix+"%")
 	if err != nil {
 		return nil, fmt.Errorf("query by prefix: %w", err)
@@ -70,6 +75,7 @@ func (s *SqliteStore) GetCompanyByPrefix(prefix string) (*models.Company, error)
 	case 0:
 		return nil, ErrCompanyNotFound
 	case 1:
+		s.recordAccess(EntityCompany, companies[0].ID)
 		return companies[0], nil
 	default:
 		return nil, ErrAmbiguousPrefix
@@ -77,12 +83,31 @@ func (s *SqliteStore) GetCompanyByPrefix(prefix string) (*models.Company, error)
 }
 
 // ListCompanies returns companies matching the optional filter criteria.
+// A missing or non-positive Limit uses the store's default limit, and larger
+// limits are clamped to its maximum; use GetAllCompanies to read every row.
 func (s *SqliteStore) ListCompanies(filter *CompanyFilter) ([]*models.Company, error) {
+	f := CompanyFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	f.Limit = s.opts.listLimit(f.Limit)
+	return s.listCompanies(&f)
+}
+
+// GetAllCompanies returns every company with no limit applied. The result
+// is unbounded, so reserve it for callers that genuinely need the full set.
+func (s *SqliteStore) GetAllCompanies() ([]*models.Company, error) {
+	return s.listCompanies(nil)
+}
+
+// listCompanies returns companies matching filter; a nil filter or
+// non-positive Limit returns all matches.
+func (s *SqliteStore) listCompanies(filter *CompanyFilter) ([]*models.Company, error) {
 	if filter != nil && filter.Search != "" {
 		return s.listCompaniesFTS(filter)
 	}
 
-	query := "SELECT id, name, domain, fields, tags, created_at, updated_at FROM companies"
+	query := "SELECT id, name, domain, fields, tags, created_at, updated_at, version, source FROM companies"
 	var args []any
 	var clauses []string
 
@@ -90,6 +115,10 @@ func (s *SqliteStore) ListCompanies(filter *CompanyFilter) ([]*models.Company, e
 		clauses = append(clauses, "EXISTS (SELECT 1 FROM json_each(tags) WHERE json_each.value = ?)")
 		args = append(args, *filter.Tag)
 	}
+	if filter != nil && filter.Source != "" {
+		clauses = append(clauses, "source = ?")
+		args = append(args, filter.Source)
+	}
 
 	if len(clauses) > 0 {
 		query += " WHERE " + strings.Join(clauses, " AND ")
@@ -114,7 +143,7 @@ func (s *SqliteStore) listCompaniesFTS(filter *CompanyFilter) ([]*models.Company
 	escaped := escapeFTS5Query(filter.Search)
 
 	query := `
-		SELECT c.id, c.name, c.domain, c.fields, c.tags, c.created_at, c.updated_at
+		SELECT c.id, c.name, c.domain, c.fields, c.tags, c.created_at, c.updated_at, c.version, c.source
 		FROM companies c
 		JOIN companies_fts fts ON c.rowid = fts.rowid
 		WHERE companies_fts MATCH ?`
@@ -124,6 +153,10 @@ func (s *SqliteStore) listCompaniesFTS(filter *CompanyFilter) ([]*models.Company
 		query += " AND EXISTS (SELECT 1 FROM json_each(c.tags) WHERE json_each.value = ?)"
 		args = append(args, *filter.Tag)
 	}
+	if filter.Source != "" {
+		query += " AND c.source = ?"
+		args = append(args, filter.Source)
+	}
 
 	query += " ORDER BY rank"
 
@@ -139,8 +172,9 @@ func (s *SqliteStore) listCompaniesFTS(filter *CompanyFilter) ([]*models.Company
 	return scanCompanyRows(rows)
 }
 
-// UpdateCompany updates an existing company, returning ErrCompanyNotFound
-// if no row matches.
+// UpdateCompany updates an existing company and increments its Version. It
+// returns ErrCompanyNotFound if no row matches and ErrVersionConflict if the
+// row has changed since c was read.
 func (s *SqliteStore) UpdateCompany(c *models.Company) error {
 	fieldsJSON, err := json.Marshal(c.Fields)
 	if err != nil {
@@ -152,11 +186,11 @@ func (s *SqliteStore) UpdateCompany(c *models.Company) error {
 	}
 
 	res, err := s.db.Exec(`
-		UPDATE companies SET name=?, domain=?, fields=?, tags=?, updated_at=?
-		WHERE id=?`,
+		UPDATE companies SET name=?, domain=?, fields=?, tags=?, updated_at=?, source=?, version=version+1
+		WHERE id=? AND version=?`,
 		c.Name, c.Domain,
 		string(fieldsJSON), string(tagsJSON),
-		c.UpdatedAt.UTC(), c.ID.String(),
+		c.UpdatedAt.UTC(), c.Source, c.ID.String(), c.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("update company: %w", err)
@@ -167,15 +201,22 @@ func (s *SqliteStore) UpdateCompany(c *models.Company) error {
 		return fmt.Errorf("rows affected: %w", err)
 	}
 	if n == 0 {
-		return ErrCompanyNotFound
+		return s.updateMissError("companies", c.ID, ErrCompanyNotFound)
 	}
+	c.Version++
 	return nil
 }
 
-// DeleteCompany removes a company by UUID, returning ErrCompanyNotFound
-// if no row matches.
+// DeleteCompany removes a company by UUID along with its relationships and
+// attachments, returning ErrCompanyNotFound if no row matches.
 func (s *SqliteStore) DeleteCompany(id uuid.UUID) error {
-	res, err := s.db.Exec("DELETE FROM companies WHERE id = ?", id.String())
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec("DELETE FROM companies WHERE id = ?", id.String())
 	if err != nil {
 		return fmt.Errorf("delete company: %w", err)
 	}
@@ -187,7 +228,16 @@ func (s *SqliteStore) DeleteCompany(id uuid.UUID) error {
 	if n == 0 {
 		return ErrCompanyNotFound
 	}
-	return nil
+	if err := deleteDependents(tx, id); err != nil {
+		return err
+	}
+	if err := recordDeletion(tx, EntityCompany, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete company: %w", err)
+	}
+	return s.forgetAccess(id)
 }
 
 // scanCompany scans a single company row and unmarshals JSON fields.
@@ -196,7 +246,7 @@ func scanCompany(row *sql.Row) (*models.Company, error) {
 	var idStr, fieldsStr, tagsStr string
 	var createdAt, updatedAt time.Time
 
-	err := row.Scan(&idStr, &c.Name, &c.Domain, &fieldsStr, &tagsStr, &createdAt, &updatedAt)
+	err := row.Scan(&idStr, &c.Name, &c.Domain, &fieldsStr, &tagsStr, &createdAt, &updatedAt, &c.Version, &c.Source)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrCompanyNotFound
 	}
@@ -209,8 +259,8 @@ func scanCompany(row *sql.Row) (*models.Company, error) {
 		return nil, fmt.Errorf("parse company id: %w", err)
 	}
 	c.ID = id
-	c.CreatedAt = createdAt
-	c.UpdatedAt = updatedAt
+	c.CreatedAt = createdAt.UTC()
+	c.UpdatedAt = updatedAt.UTC()
 
 	if err := json.Unmarshal([]byte(fieldsStr), &c.Fields); err != nil {
 		return nil, fmt.Errorf("unmarshal fields: %w", err)
@@ -228,31 +278,11 @@ func scanCompanyRows(rows *sql.Rows) ([]*models.Company, error) {
 
 	var companies []*models.Company
 	for rows.Next() {
-		var c models.Company
-		var idStr, fieldsStr, tagsStr string
-		var createdAt, updatedAt time.Time
-
-		err := rows.Scan(&idStr, &c.Name, &c.Domain, &fieldsStr, &tagsStr, &createdAt, &updatedAt)
+		c, err := scanCompanyRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("scan company row: %w", err)
-		}
-
-		id, err := uuid.Parse(idStr)
-		if err != nil {
-			return nil, fmt.Errorf("parse company id: %w", err)
-		}
-		c.ID = id
-		c.CreatedAt = createdAt
-		c.UpdatedAt = updatedAt
-
-		if err := json.Unmarshal([]byte(fieldsStr), &c.Fields); err != nil {
-			return nil, fmt.Errorf("unmarshal fields: %w", err)
-		}
-		if err := json.Unmarshal([]byte(tagsStr), &c.Tags); err != nil {
-			return nil, fmt.Errorf("unmarshal tags: %w", err)
+			return nil, err
 		}
-
-		companies = append(companies, &c)
+		companies = append(companies, c)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -261,3 +291,32 @@ func scanCompanyRows(rows *sql.Rows) ([]*models.Company, error) {
 
 	return companies, nil
 }
+
+// scanCompanyRow scans the current row of a company result set.
+func scanCompanyRow(rows *sql.Rows) (*models.Company, error) {
+	var c models.Company
+	var idStr, fieldsStr, tagsStr string
+	var createdAt, updatedAt time.Time
+
+	err := rows.Scan(&idStr, &c.Name, &c.Domain, &fieldsStr, &tagsStr, &createdAt, &updatedAt, &c.Version, &c.Source)
+	if err != nil {
+		return nil, fmt.Errorf("scan company row: %w", err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("parse company id: %w", err)
+	}
+	c.ID = id
+	c.CreatedAt = createdAt.UTC()
+	c.UpdatedAt = updatedAt.UTC()
+
+	if err := json.Unmarshal([]byte(fieldsStr), &c.Fields); err != nil {
+		return nil, fmt.Errorf("unmarshal fields: %w", err)
+	}
+	if err := json.Unmarshal([]byte(tagsStr), &c.Tags); err != nil {
+		return nil, fmt.Errorf("unmarshal tags: %w", err)
+	}
+
+	return &c, nil
+}
@@ -121,6 +121,43 @@ func TestUpdateCompanyNotFound(t *testing.T) {
 	}
 }
 
+func TestDeleteCompanyRemovesDependents(t *testing.T) {
+	store := newTestStore(t)
+
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	ids := newTestEntities(t, store, 2)
+	for _, rel := range []*models.Relationship{
+		models.NewRelationship(ids[0], acme.ID, "works_at", ""),
+		models.NewRelationship(ids[0], ids[1], "knows", ""),
+	} {
+		if err := store.CreateRelationship(rel); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+	a := models.NewAttachment(EntityCompany, acme.ID, "logo.png")
+	a.Data = []byte("png")
+	if err := store.AddAttachment(a); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	if err := store.DeleteCompany(acme.ID); err != nil {
+		t.Fatalf("DeleteCompany: %v", err)
+	}
+
+	for table, want := range map[string]int{"relationships": 1, "attachments": 0} {
+		var n int
+		if err := store.db.QueryRow("SELECT count(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if n != want {
+			t.Errorf("%s left = %d, want %d", table, n, want)
+		}
+	}
+}
+
 func TestDeleteCompany(t *testing.T) {
 	store := newTestStore(t)
 
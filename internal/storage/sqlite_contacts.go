@@ -15,7 +15,20 @@ import (
 )
 
 // CreateContact inserts a new contact, marshaling Fields and Tags to JSON.
+// With Options.EnforceUniqueEmail set it returns ErrDuplicateEmail if
+// another contact already uses the email.
 func (s *SqliteStore) CreateContact(c *models.Contact) error {
+	if s.opts.EnforceUniqueEmail {
+		if err := checkUniqueEmail(s, c); err != nil {
+			return err
+		}
+	}
+	return insertContact(s.db, c)
+}
+
+// insertContact writes c as a new row through ex, which is the database or
+// a transaction the caller commits.
+func insertContact(ex execer, c *models.Contact) error {
 	fieldsJSON, err := json.Marshal(c.Fields)
 	if err != nil {
 		return fmt.Errorf("marshal fields: %w", err)
@@ -25,15 +38,15 @@ func (s *SqliteStore) CreateContact(c *models.Contact) error {
 		return fmt.Errorf("marshal tags: %w", err)
 	}
 
-	_, err = s.db.Exec(`
-		INSERT INTO contacts (id, name, email, phone, fields, tags, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		c.ID.String(), c.Name, c.Email, c.Phone,
+	_, err = ex.Exec(`
+		INSERT INTO contacts (id, name, email, phone, phone_key, fields, tags, created_at, updated_at, do_not_contact, version, source)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.ID.String(), c.Name, c.Email, c.Phone, phoneSearchKey(c.Phone),
 		string(fieldsJSON), string(tagsJSON),
-		c.CreatedAt.UTC(), c.UpdatedAt.UTC(),
+		c.CreatedAt.UTC(), c.UpdatedAt.UTC(), c.DoNotContact, max(c.Version, 1), c.Source,
 	)
 	if err != nil {
-		return fmt.Errorf("insert contact: %w", err)
+		return uniqueEmailError(err, "insert contact")
 	}
 	return nil
 }
@@ -41,9 +54,14 @@ func (s *SqliteStore) CreateContact(c *models.Contact) error {
 // GetContact retrieves a contact by UUID, returning ErrContactNotFound on miss.
 func (s *SqliteStore) GetContact(id uuid.UUID) (*models.Contact, error) {
 	row := s.db.QueryRow(`
-		SELECT id, name, email, phone, fields, tags, created_at, updated_at
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
 		FROM contacts WHERE id = ?`, id.String())
-	return scanContact(row)
+	c, err := scanContact(row)
+	if err != nil {
+		return nil, err
+	}
+	s.recordAccess(EntityContact, c.ID)
+	return c, nil
 }
 
 // GetContactByPrefix finds a contact whose ID starts with the given prefix.
@@ -55,7 +73,7 @@ func (s *SqliteStore) GetContactByPrefix(prefix string) (*models.Contact, error)
 	}
 
 	rows, err := s.db.Query(`
-		SELECT id, name, email, phone, fields, tags, created_at, updated_at
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
 		FROM contacts WHERE id LIKE ?`, prefix+"%")
 	if err != nil {
 		return nil, fmt.Errorf("query by prefix: %w", err)
@@ -70,6 +88,7 @@ func (s *SqliteStore) GetContactByPrefix(prefix string) (*models.Contact, error)
 	case 0:
 		return nil, ErrContactNotFound
 	case 1:
+		s.recordAccess(EntityContact, contacts[0].ID)
 		return contacts[0], nil
 	default:
 		return nil, ErrAmbiguousPrefix
@@ -77,12 +96,31 @@ func (s *SqliteStore) GetContactByPrefix(prefix string) (*models.Contact, error)
 }
 
 // ListContacts returns contacts matching the optional filter criteria.
+// A missing or non-positive Limit uses the store's default limit, and larger
+// limits are clamped to its maximum; use GetAllContacts to read every row.
 func (s *SqliteStore) ListContacts(filter *ContactFilter) ([]*models.Contact, error) {
+	f := ContactFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	f.Limit = s.opts.listLimit(f.Limit)
+	return s.listContacts(&f)
+}
+
+// GetAllContacts returns every contact with no limit applied. The result
+// is unbounded, so reserve it for callers that genuinely need the full set.
+func (s *SqliteStore) GetAllContacts() ([]*models.Contact, error) {
+	return s.listContacts(nil)
+}
+
+// listContacts returns contacts matching filter; a nil filter or
+// non-positive Limit returns all matches.
+func (s *SqliteStore) listContacts(filter *ContactFilter) ([]*models.Contact, error) {
 	if filter != nil && filter.Search != "" {
 		return s.listContactsFTS(filter)
 	}
 
-	query := "SELECT id, name, email, phone, fields, tags, created_at, updated_at FROM contacts"
+	query := "SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source FROM contacts"
 	var args []any
 	var clauses []string
 
@@ -90,6 +128,10 @@ func (s *SqliteStore) ListContacts(filter *ContactFilter) ([]*models.Contact, er
 		clauses = append(clauses, "EXISTS (SELECT 1 FROM json_each(tags) WHERE json_each.value = ?)")
 		args = append(args, *filter.Tag)
 	}
+	if filter != nil && filter.Source != "" {
+		clauses = append(clauses, "source = ?")
+		args = append(args, filter.Source)
+	}
 
 	if len(clauses) > 0 {
 		query += " WHERE " + strings.Join(clauses, " AND ")
@@ -109,12 +151,13 @@ func (s *SqliteStore) ListContacts(filter *ContactFilter) ([]*models.Contact, er
 	return scanContactRows(rows)
 }
 
-// listContactsFTS searches contacts using the FTS5 index.
+// listContactsFTS searches contacts using the FTS5 index. Phone-like
+// searches also match on normalized phone digits.
 func (s *SqliteStore) listContactsFTS(filter *ContactFilter) ([]*models.Contact, error) {
 	escaped := escapeFTS5Query(filter.Search)
 
 	query := `
-		SELECT c.id, c.name, c.email, c.phone, c.fields, c.tags, c.created_at, c.updated_at
+		SELECT c.id, c.name, c.email, c.phone, c.fields, c.tags, c.created_at, c.updated_at, c.do_not_contact, c.version, c.source
 		FROM contacts c
 		JOIN contacts_fts fts ON c.rowid = fts.rowid
 		WHERE contacts_fts MATCH ?`
@@ -124,6 +167,10 @@ func (s *SqliteStore) listContactsFTS(filter *ContactFilter) ([]*models.Contact,
 		query += " AND EXISTS (SELECT 1 FROM json_each(c.tags) WHERE json_each.value = ?)"
 		args = append(args, *filter.Tag)
 	}
+	if filter.Source != "" {
+		query += " AND c.source = ?"
+		args = append(args, filter.Source)
+	}
 
 	query += " ORDER BY rank"
 
@@ -136,12 +183,81 @@ func (s *SqliteStore) listContactsFTS(filter *ContactFilter) ([]*models.Contact,
 	if err != nil {
 		return nil, fmt.Errorf("fts search contacts: %w", err)
 	}
+	contacts, err := scanContactRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := phoneSearchDigits(filter.Search)
+	if digits == "" {
+		return contacts, nil
+	}
+	byPhone, err := s.contactsByPhone(digits, filter)
+	if err != nil {
+		return nil, err
+	}
+	contacts = appendMissingContacts(contacts, byPhone)
+	if filter.Limit > 0 && len(contacts) > filter.Limit {
+		contacts = contacts[:filter.Limit]
+	}
+	return contacts, nil
+}
+
+// contactsByPhone returns contacts whose phone number ends with digits,
+// optionally restricted by filter's tag and source. The match is a range
+// over the indexed phone_key column: every key starting with the reversed
+// digits sorts between them and the same string followed by ':', the
+// character after '9'.
+func (s *SqliteStore) contactsByPhone(digits string, filter *ContactFilter) ([]*models.Contact, error) {
+	key := phoneSearchKey(digits)
+	query := `
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+		FROM contacts
+		WHERE phone_key >= ? AND phone_key < ?`
+	args := []any{key, key + ":"}
+	if filter != nil && filter.Tag != nil {
+		query += " AND EXISTS (SELECT 1 FROM json_each(tags) WHERE json_each.value = ?)"
+		args = append(args, *filter.Tag)
+	}
+	if filter != nil && filter.Source != "" {
+		query += " AND source = ?"
+		args = append(args, filter.Source)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search contacts by phone: %w", err)
+	}
 	return scanContactRows(rows)
 }
 
-// UpdateContact updates an existing contact, returning ErrContactNotFound
-// if no row matches.
+// appendMissingContacts appends the contacts in extra whose IDs are not
+// already in base, preserving order.
+func appendMissingContacts(base, extra []*models.Contact) []*models.Contact {
+	seen := make(map[uuid.UUID]bool, len(base))
+	for _, c := range base {
+		seen[c.ID] = true
+	}
+	for _, c := range extra {
+		if !seen[c.ID] {
+			base = append(base, c)
+			seen[c.ID] = true
+		}
+	}
+	return base
+}
+
+// UpdateContact updates an existing contact and increments its Version. It
+// returns ErrContactNotFound if no row matches and ErrVersionConflict if the
+// row has changed since c was read. With Options.EnforceUniqueEmail set it
+// returns ErrDuplicateEmail if another contact already uses the email.
 func (s *SqliteStore) UpdateContact(c *models.Contact) error {
+	if s.opts.EnforceUniqueEmail {
+		if err := checkUniqueEmail(s, c); err != nil {
+			return err
+		}
+	}
 	fieldsJSON, err := json.Marshal(c.Fields)
 	if err != nil {
 		return fmt.Errorf("marshal fields: %w", err)
@@ -152,14 +268,14 @@ func (s *SqliteStore) UpdateContact(c *models.Contact) error {
 	}
 
 	res, err := s.db.Exec(`
-		UPDATE contacts SET name=?, email=?, phone=?, fields=?, tags=?, updated_at=?
-		WHERE id=?`,
-		c.Name, c.Email, c.Phone,
+		UPDATE contacts SET name=?, email=?, phone=?, phone_key=?, fields=?, tags=?, updated_at=?, do_not_contact=?, source=?, version=version+1
+		WHERE id=? AND version=?`,
+		c.Name, c.Email, c.Phone, phoneSearchKey(c.Phone),
 		string(fieldsJSON), string(tagsJSON),
-		c.UpdatedAt.UTC(), c.ID.String(),
+		c.UpdatedAt.UTC(), c.DoNotContact, c.Source, c.ID.String(), c.Version,
 	)
 	if err != nil {
-		return fmt.Errorf("update contact: %w", err)
+		return uniqueEmailError(err, "update contact")
 	}
 
 	n, err := res.RowsAffected()
@@ -167,15 +283,38 @@ func (s *SqliteStore) UpdateContact(c *models.Contact) error {
 		return fmt.Errorf("rows affected: %w", err)
 	}
 	if n == 0 {
-		return ErrContactNotFound
+		return s.updateMissError("contacts", c.ID, ErrContactNotFound)
 	}
+	c.Version++
 	return nil
 }
 
-// DeleteContact removes a contact by UUID, returning ErrContactNotFound
-// if no row matches.
+// updateMissError explains why a versioned UPDATE on table matched no rows:
+// notFound if the row is gone, ErrVersionConflict if it exists at another
+// version.
+func (s *SqliteStore) updateMissError(table string, id uuid.UUID, notFound error) error {
+	var exists bool
+	//nolint:gosec // table is one of two fixed names supplied by callers in this package
+	err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = ?)", id.String()).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check %s exists: %w", table, err)
+	}
+	if !exists {
+		return notFound
+	}
+	return ErrVersionConflict
+}
+
+// DeleteContact removes a contact by UUID along with its relationships and
+// attachments, returning ErrContactNotFound if no row matches.
 func (s *SqliteStore) DeleteContact(id uuid.UUID) error {
-	res, err := s.db.Exec("DELETE FROM contacts WHERE id = ?", id.String())
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec("DELETE FROM contacts WHERE id = ?", id.String())
 	if err != nil {
 		return fmt.Errorf("delete contact: %w", err)
 	}
@@ -187,7 +326,16 @@ func (s *SqliteStore) DeleteContact(id uuid.UUID) error {
 	if n == 0 {
 		return ErrContactNotFound
 	}
-	return nil
+	if err := deleteDependents(tx, id); err != nil {
+		return err
+	}
+	if err := recordDeletion(tx, EntityContact, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete contact: %w", err)
+	}
+	return s.forgetAccess(id)
 }
 
 // scanContact scans a single contact row and unmarshals JSON fields.
@@ -196,7 +344,7 @@ func scanContact(row *sql.Row) (*models.Contact, error) {
 	var idStr, fieldsStr, tagsStr string
 	var createdAt, updatedAt time.Time
 
-	err := row.Scan(&idStr, &c.Name, &c.Email, &c.Phone, &fieldsStr, &tagsStr, &createdAt, &updatedAt)
+	err := row.Scan(&idStr, &c.Name, &c.Email, &c.Phone, &fieldsStr, &tagsStr, &createdAt, &updatedAt, &c.DoNotContact, &c.Version, &c.Source)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrContactNotFound
 	}
@@ -209,8 +357,8 @@ func scanContact(row *sql.Row) (*models.Contact, error) {
 		return nil, fmt.Errorf("parse contact id: %w", err)
 	}
 	c.ID = id
-	c.CreatedAt = createdAt
-	c.UpdatedAt = updatedAt
+	c.CreatedAt = createdAt.UTC()
+	c.UpdatedAt = updatedAt.UTC()
 
 	if err := json.Unmarshal([]byte(fieldsStr), &c.Fields); err != nil {
 		return nil, fmt.Errorf("unmarshal fields: %w", err)
@@ -228,31 +376,11 @@ func scanContactRows(rows *sql.Rows) ([]*models.Contact, error) {
 
 	var contacts []*models.Contact
 	for rows.Next() {
-		var c models.Contact
-		var idStr, fieldsStr, tagsStr string
-		var createdAt, updatedAt time.Time
-
-		err := rows.Scan(&idStr, &c.Name, &c.Email, &c.Phone, &fieldsStr, &tagsStr, &createdAt, &updatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("scan contact row: %w", err)
-		}
-
-		id, err := uuid.Parse(idStr)
+		c, err := scanContactRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("parse contact id: %w", err)
+			return nil, err
 		}
-		c.ID = id
-		c.CreatedAt = createdAt
-		c.UpdatedAt = updatedAt
-
-		if err := json.Unmarshal([]byte(fieldsStr), &c.Fields); err != nil {
-			return nil, fmt.Errorf("unmarshal fields: %w", err)
-		}
-		if err := json.Unmarshal([]byte(tagsStr), &c.Tags); err != nil {
-			return nil, fmt.Errorf("unmarshal tags: %w", err)
-		}
-
-		contacts = append(contacts, &c)
+		contacts = append(contacts, c)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -261,3 +389,32 @@ func scanContactRows(rows *sql.Rows) ([]*models.Contact, error) {
 
 	return contacts, nil
 }
+
+// scanContactRow scans the current row of a contact result set.
+func scanContactRow(rows *sql.Rows) (*models.Contact, error) {
+	var c models.Contact
+	var idStr, fieldsStr, tagsStr string
+	var createdAt, updatedAt time.Time
+
+	err := rows.Scan(&idStr, &c.Name, &c.Email, &c.Phone, &fieldsStr, &tagsStr, &createdAt, &updatedAt, &c.DoNotContact, &c.Version, &c.Source)
+	if err != nil {
+		return nil, fmt.Errorf("scan contact row: %w", err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("parse contact id: %w", err)
+	}
+	c.ID = id
+	c.CreatedAt = createdAt.UTC()
+	c.UpdatedAt = updatedAt.UTC()
+
+	if err := json.Unmarshal([]byte(fieldsStr), &c.Fields); err != nil {
+		return nil, fmt.Errorf("unmarshal fields: %w", err)
+	}
+	if err := json.Unmarshal([]byte(tagsStr), &c.Tags); err != nil {
+		return nil, fmt.Errorf("unmarshal tags: %w", err)
+	}
+
+	return &c, nil
+}
@@ -4,7 +4,10 @@ package storage
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/crm/internal/models"
@@ -159,6 +162,33 @@ func TestDeleteContact(t *testing.T) {
 	}
 }
 
+func TestDeleteContactRemovesRelationships(t *testing.T) {
+	store := newTestStore(t)
+
+	ids := newTestEntities(t, store, 3)
+	for _, rel := range []*models.Relationship{
+		models.NewRelationship(ids[0], ids[1], "knows", ""),
+		models.NewRelationship(ids[2], ids[0], "manages", ""),
+		models.NewRelationship(ids[1], ids[2], "knows", ""),
+	} {
+		if err := store.CreateRelationship(rel); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+
+	if err := store.DeleteContact(ids[0]); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+
+	var n int
+	if err := store.db.QueryRow("SELECT count(*) FROM relationships").Scan(&n); err != nil {
+		t.Fatalf("count relationships: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("relationships left = %d, want only the one not touching the deleted contact", n)
+	}
+}
+
 func TestDeleteContactNotFound(t *testing.T) {
 	store := newTestStore(t)
 
@@ -212,6 +242,50 @@ func TestListContacts(t *testing.T) {
 	}
 }
 
+func TestListContactsLimits(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "limits.db")
+	store, err := NewSqliteStoreWithOptions(dbPath, Options{DefaultLimit: 2, MaxLimit: 3})
+	if err != nil {
+		t.Fatalf("NewSqliteStoreWithOptions: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for i := range 5 {
+		if err := store.CreateContact(models.NewContact(fmt.Sprintf("Contact %d", i))); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter *ContactFilter
+		want   int
+	}{
+		{"nil filter uses default", nil, 2},
+		{"zero limit uses default", &ContactFilter{}, 2},
+		{"limit within max", &ContactFilter{Limit: 3}, 3},
+		{"limit above max is clamped", &ContactFilter{Limit: 50}, 3},
+		{"search is clamped too", &ContactFilter{Search: "Contact", Limit: 50}, 3},
+	}
+	for _, tt := range tests {
+		got, err := store.ListContacts(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: ListContacts: %v", tt.name, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("%s: len = %d, want %d", tt.name, len(got), tt.want)
+		}
+	}
+
+	all, err := store.GetAllContacts()
+	if err != nil {
+		t.Fatalf("GetAllContacts: %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("GetAllContacts len = %d, want 5", len(all))
+	}
+}
+
 func TestListContactsSearch(t *testing.T) {
 	store := newTestStore(t)
 
@@ -236,3 +310,65 @@ func TestListContactsSearch(t *testing.T) {
 		t.Errorf("Name = %q, want %q", results[0].Name, "Heidi Searchable")
 	}
 }
+
+func TestContactTimestampsReadBackInUTC(t *testing.T) {
+	store := newTestStore(t)
+
+	zone := time.FixedZone("UTC-7", -7*60*60)
+	c := models.NewContact("Zoned")
+	c.CreatedAt = time.Date(2024, 3, 1, 9, 0, 0, 0, zone)
+	c.UpdatedAt = c.CreatedAt
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	got, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.CreatedAt.Location() != time.UTC {
+		t.Errorf("CreatedAt location = %v, want UTC", got.CreatedAt.Location())
+	}
+	if !got.CreatedAt.Equal(c.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want instant %v", got.CreatedAt, c.CreatedAt)
+	}
+}
+
+func TestContactDoNotContactRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+
+	c := models.NewContact("Opted Out")
+	c.DoNotContact = true
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	got, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if !got.DoNotContact {
+		t.Error("expected DoNotContact after create")
+	}
+
+	results, err := store.Search("opted")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results.Contacts) != 1 || !results.Contacts[0].DoNotContact {
+		t.Errorf("search results = %+v, want the flagged contact", results.Contacts)
+	}
+
+	got.DoNotContact = false
+	got.Touch()
+	if err := store.UpdateContact(got); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	cleared, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact after clear: %v", err)
+	}
+	if cleared.DoNotContact {
+		t.Error("expected DoNotContact to be cleared")
+	}
+}
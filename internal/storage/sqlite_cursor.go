@@ -0,0 +1,63 @@
+// ABOUTME: SQLite cursor-paged scans of contacts and companies.
+// ABOUTME: Pages are ordered by (created_at, id) so iteration is stable under concurrent inserts.
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// ListContactsAfter returns up to limit contacts that sort after cursor in
+// (created_at, id) order, plus the cursor to pass for the next page. A
+// non-positive limit uses the store's default list limit. The scan is done
+// when a page comes back shorter than the limit.
+func (s *SqliteStore) ListContactsAfter(cursor Cursor, limit int) ([]*models.Contact, Cursor, error) {
+	query := "SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source FROM contacts"
+	where, args := cursorWhere(cursor)
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += " ORDER BY created_at, id LIMIT ?"
+	args = append(args, s.opts.listLimit(limit))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, cursor, fmt.Errorf("list contacts after cursor: %w", err)
+	}
+	contacts, err := scanContactRows(rows)
+	if err != nil {
+		return nil, cursor, err
+	}
+	next := nextCursor(cursor, len(contacts), func(i int) (time.Time, uuid.UUID) {
+		return contacts[i].CreatedAt, contacts[i].ID
+	})
+	return contacts, next, nil
+}
+
+// ListCompaniesAfter returns up to limit companies that sort after cursor
+// in (created_at, id) order, plus the cursor to pass for the next page.
+func (s *SqliteStore) ListCompaniesAfter(cursor Cursor, limit int) ([]*models.Company, Cursor, error) {
+	query := "SELECT id, name, domain, fields, tags, created_at, updated_at, version, source FROM companies"
+	where, args := cursorWhere(cursor)
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += " ORDER BY created_at, id LIMIT ?"
+	args = append(args, s.opts.listLimit(limit))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, cursor, fmt.Errorf("list companies after cursor: %w", err)
+	}
+	companies, err := scanCompanyRows(rows)
+	if err != nil {
+		return nil, cursor, err
+	}
+	next := nextCursor(cursor, len(companies), func(i int) (time.Time, uuid.UUID) {
+		return companies[i].CreatedAt, companies[i].ID
+	})
+	return companies, next, nil
+}
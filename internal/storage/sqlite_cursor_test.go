@@ -0,0 +1,101 @@
+// ABOUTME: Tests for SQLite cursor-paged scans and cursor token encoding.
+// ABOUTME: Verifies pages cover every row once, tie-break on ID, and ignore rows inserted behind the cursor.
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestListContactsAfterPagesStably(t *testing.T) {
+	store := newTestStore(t)
+
+	// Share one timestamp so ordering within it falls back to the ID.
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		c := models.NewContact("Paged")
+		c.CreatedAt = base
+		if i == 4 {
+			c.CreatedAt = base.Add(time.Minute)
+		}
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+
+	seen := map[uuid.UUID]bool{}
+	var cursor Cursor
+	for page := 0; ; page++ {
+		contacts, next, err := store.ListContactsAfter(cursor, 2)
+		if err != nil {
+			t.Fatalf("ListContactsAfter: %v", err)
+		}
+		for _, c := range contacts {
+			if seen[c.ID] {
+				t.Errorf("contact %s returned twice", c.ID)
+			}
+			seen[c.ID] = true
+		}
+		if page == 0 {
+			// An insert that sorts before the cursor must not shift later pages.
+			early := models.NewContact("Early")
+			early.CreatedAt = base.Add(-time.Hour)
+			if err := store.CreateContact(early); err != nil {
+				t.Fatalf("CreateContact: %v", err)
+			}
+		}
+		if len(contacts) < 2 {
+			break
+		}
+		cursor = next
+	}
+	if len(seen) != 5 {
+		t.Errorf("saw %d contacts, want the 5 present when the scan began", len(seen))
+	}
+}
+
+func TestListCompaniesAfter(t *testing.T) {
+	store := newTestStore(t)
+	for _, name := range []string{"A", "B", "C"} {
+		if err := store.CreateCompany(models.NewCompany(name)); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+
+	first, next, err := store.ListCompaniesAfter(Cursor{}, 2)
+	if err != nil {
+		t.Fatalf("ListCompaniesAfter: %v", err)
+	}
+	rest, _, err := store.ListCompaniesAfter(next, 2)
+	if err != nil {
+		t.Fatalf("ListCompaniesAfter: %v", err)
+	}
+	if len(first) != 2 || len(rest) != 1 {
+		t.Fatalf("pages = %d, %d; want 2, 1", len(first), len(rest))
+	}
+	if rest[0].ID == first[0].ID || rest[0].ID == first[1].ID {
+		t.Error("second page repeated a company from the first")
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{CreatedAt: time.Date(2025, 3, 4, 5, 6, 7, 8, time.UTC), ID: uuid.New()}
+	got, err := ParseCursor(c.String())
+	if err != nil {
+		t.Fatalf("ParseCursor: %v", err)
+	}
+	if !got.CreatedAt.Equal(c.CreatedAt) || got.ID != c.ID {
+		t.Errorf("round trip = %+v, want %+v", got, c)
+	}
+
+	if zero, err := ParseCursor(""); err != nil || !zero.IsZero() {
+		t.Errorf("ParseCursor(\"\") = %+v, %v; want zero cursor", zero, err)
+	}
+	if _, err := ParseCursor("not a cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("garbage token err = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,112 @@
+// ABOUTME: SQLite eager-loading of a contact with its companies, relationships, notes, and attachments.
+// ABOUTME: Resolves relationship counterparts by joining against contacts and companies.
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// GetContactExpanded returns a contact together with the companies it is
+// linked to, every relationship annotated with its counterpart's name, and
+// its notes and attachments.
+// Unlike GetContact it does not record access; callers that resolve the
+// contact first have already done so.
+func (s *SqliteStore) GetContactExpanded(id uuid.UUID) (*ContactExpanded, error) {
+	row := s.db.QueryRow(`
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+		FROM contacts WHERE id = ?`, id.String())
+	contact, err := scanContact(row)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT DISTINCT co.id, co.name, co.domain, co.fields, co.tags, co.created_at, co.updated_at, co.version, co.source
+		FROM companies co
+		JOIN relationships r
+			ON (r.source_id = ? AND r.target_id = co.id)
+			OR (r.target_id = ? AND r.source_id = co.id)
+		ORDER BY co.name`, id.String(), id.String())
+	if err != nil {
+		return nil, fmt.Errorf("list contact companies: %w", err)
+	}
+	companies, err := scanCompanyRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	related, err := s.listRelatedEntities(id)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.ListContactNotes(id)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.ListAttachments(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContactExpanded{
+		Contact:       contact,
+		Companies:     companies,
+		Relationships: related,
+		Notes:         notes,
+		Attachments:   attachments,
+	}, nil
+}
+
+// listRelatedEntities returns every relationship touching id along with the
+// type and name of the entity on the other end.
+func (s *SqliteStore) listRelatedEntities(id uuid.UUID) ([]*RelatedEntity, error) {
+	rows, err := s.db.Query(`
+		SELECT r.id, r.source_id, r.target_id, r.type, r.context, r.created_at,
+			CASE WHEN c.id IS NOT NULL THEN 'contact' WHEN co.id IS NOT NULL THEN 'company' ELSE '' END,
+			COALESCE(c.name, co.name, '')
+		FROM relationships r
+		LEFT JOIN contacts c
+			ON c.id = CASE WHEN r.source_id = ? THEN r.target_id ELSE r.source_id END
+		LEFT JOIN companies co
+			ON co.id = CASE WHEN r.source_id = ? THEN r.target_id ELSE r.source_id END
+		WHERE r.source_id = ? OR r.target_id = ?
+		ORDER BY r.created_at`,
+		id.String(), id.String(), id.String(), id.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list related entities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var related []*RelatedEntity
+	for rows.Next() {
+		var r models.Relationship
+		var e RelatedEntity
+		var idStr, srcStr, tgtStr string
+		var createdAt time.Time
+
+		if err := rows.Scan(&idStr, &srcStr, &tgtStr, &r.Type, &r.Context, &createdAt, &e.OtherType, &e.OtherName); err != nil {
+			return nil, fmt.Errorf("scan related entity: %w", err)
+		}
+		if err := setRelationshipIDs(&r, idStr, srcStr, tgtStr); err != nil {
+			return nil, err
+		}
+		r.CreatedAt = createdAt.UTC()
+
+		e.Relationship = &r
+		e.OtherID = r.TargetID
+		if r.TargetID == id {
+			e.OtherID = r.SourceID
+		}
+		related = append(related, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate related entities: %w", err)
+	}
+	return related, nil
+}
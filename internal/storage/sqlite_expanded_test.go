@@ -0,0 +1,100 @@
+// ABOUTME: Tests for SQLite GetContactExpanded eager loading.
+// ABOUTME: Verifies companies, counterpart names, and dangling relationship handling.
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestGetContactExpanded(t *testing.T) {
+	store := newTestStore(t)
+
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	acme := models.NewCompany("Acme")
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	rels := []*models.Relationship{
+		models.NewRelationship(alice.ID, acme.ID, "works_at", ""),
+		models.NewRelationship(bob.ID, alice.ID, "knows", ""),
+		models.NewRelationship(alice.ID, uuid.New(), "knows", ""),
+	}
+	for _, r := range rels[:2] {
+		if err := store.CreateRelationship(r); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+	// CreateRelationship rejects unknown endpoints, so write the dangling row directly.
+	_, err := store.db.Exec(`
+		INSERT INTO relationships (id, source_id, target_id, type, context, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		rels[2].ID.String(), rels[2].SourceID.String(), rels[2].TargetID.String(),
+		rels[2].Type, rels[2].Context, rels[2].CreatedAt,
+	)
+	if err != nil {
+		t.Fatalf("insert dangling relationship: %v", err)
+	}
+
+	if err := store.AddContactNote(models.NewContactNote(alice.ID, "Met at the conference")); err != nil {
+		t.Fatalf("AddContactNote: %v", err)
+	}
+	att := models.NewAttachment(EntityContact, alice.ID, "resume.pdf")
+	att.Data = []byte("pdf")
+	if err := store.AddAttachment(att); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	got, err := store.GetContactExpanded(alice.ID)
+	if err != nil {
+		t.Fatalf("GetContactExpanded: %v", err)
+	}
+	if got.Contact.Name != "Alice" {
+		t.Errorf("Contact.Name = %q, want Alice", got.Contact.Name)
+	}
+	if len(got.Companies) != 1 || got.Companies[0].ID != acme.ID {
+		t.Errorf("Companies = %v, want [Acme]", got.Companies)
+	}
+	if len(got.Relationships) != 3 {
+		t.Fatalf("Relationships len = %d, want 3", len(got.Relationships))
+	}
+	if len(got.Notes) != 1 || got.Notes[0].Content != "Met at the conference" {
+		t.Errorf("Notes = %v, want the conference note", got.Notes)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].Filename != "resume.pdf" {
+		t.Errorf("Attachments = %v, want resume.pdf", got.Attachments)
+	}
+
+	byRel := make(map[uuid.UUID]*RelatedEntity)
+	for _, e := range got.Relationships {
+		byRel[e.Relationship.ID] = e
+	}
+	if e := byRel[rels[0].ID]; e.OtherType != EntityCompany || e.OtherName != "Acme" {
+		t.Errorf("works_at counterpart = %s %q, want company Acme", e.OtherType, e.OtherName)
+	}
+	if e := byRel[rels[1].ID]; e.OtherID != bob.ID || e.OtherType != EntityContact || e.OtherName != "Bob" {
+		t.Errorf("knows counterpart = %v %s %q, want contact Bob", e.OtherID, e.OtherType, e.OtherName)
+	}
+	if e := byRel[rels[2].ID]; e.OtherType != "" || e.OtherName != "" {
+		t.Errorf("dangling counterpart = %s %q, want empty", e.OtherType, e.OtherName)
+	}
+}
+
+func TestGetContactExpandedNotFound(t *testing.T) {
+	store := newTestStore(t)
+
+	_, err := store.GetContactExpanded(uuid.New())
+	if !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("expected ErrContactNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// ABOUTME: SQLite idempotency key storage so create operations are safe to retry.
+// ABOUTME: Maps (entity type, key) to the created entity ID until the key expires.
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClaimIdempotencyKey reserves key for the entity about to be created as id.
+// The insert relies on the (entity_type, key) primary key, so of two
+// concurrent claims exactly one succeeds. If an unexpired claim already
+// holds the key, it returns that claim's entity ID and false.
+func (s *SqliteStore) ClaimIdempotencyKey(entityType, key string, id uuid.UUID) (uuid.UUID, bool, error) {
+	now := time.Now().UTC()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("DELETE FROM idempotency_keys WHERE created_at < ?", now.Add(-s.opts.idempotencyTTL())); err != nil {
+		return uuid.Nil, false, fmt.Errorf("prune idempotency keys: %w", err)
+	}
+	res, err := tx.Exec(`
+		INSERT INTO idempotency_keys (entity_type, key, entity_id, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (entity_type, key) DO NOTHING`,
+		entityType, key, id.String(), now,
+	)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("claim idempotency key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("rows affected: %w", err)
+	}
+	claimed := n == 1
+	if !claimed {
+		var idStr string
+		if err := tx.QueryRow("SELECT entity_id FROM idempotency_keys WHERE entity_type = ? AND key = ?",
+			entityType, key).Scan(&idStr); err != nil {
+			return uuid.Nil, false, fmt.Errorf("read idempotency claim: %w", err)
+		}
+		if id, err = uuid.Parse(idStr); err != nil {
+			return uuid.Nil, false, fmt.Errorf("parse idempotency entity id: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return id, claimed, nil
+}
+
+// ReleaseIdempotencyKey drops key's claim if it is still held for id, so a
+// failed create can be retried or a deleted entity created again.
+func (s *SqliteStore) ReleaseIdempotencyKey(entityType, key string, id uuid.UUID) error {
+	if _, err := s.db.Exec("DELETE FROM idempotency_keys WHERE entity_type = ? AND key = ? AND entity_id = ?",
+		entityType, key, id.String()); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
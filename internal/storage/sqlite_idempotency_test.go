@@ -0,0 +1,69 @@
+// ABOUTME: Tests for SQLite idempotency key storage.
+// ABOUTME: Covers claiming, release, per-type scoping, and expiry.
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestIdempotencyKeyExpires(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := NewSqliteStoreWithOptions(dbPath, Options{IdempotencyTTL: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewSqliteStoreWithOptions: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, claimed, err := store.ClaimIdempotencyKey(EntityContact, "k1", uuid.New()); err != nil || !claimed {
+		t.Fatalf("ClaimIdempotencyKey = claimed:%v err:%v, want claimed", claimed, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	id := uuid.New()
+	if held, claimed, err := store.ClaimIdempotencyKey(EntityContact, "k1", id); err != nil || !claimed || held != id {
+		t.Errorf("claim after expiry = %v claimed:%v err:%v, want %v claimed", held, claimed, err, id)
+	}
+}
+
+func TestClaimIdempotencyKey(t *testing.T) {
+	checkClaimIdempotencyKey(t, newTestStore(t))
+}
+
+// checkClaimIdempotencyKey exercises ClaimIdempotencyKey and
+// ReleaseIdempotencyKey against any backend.
+func checkClaimIdempotencyKey(t *testing.T, store Storage) {
+	t.Helper()
+
+	first := uuid.New()
+	held, claimed, err := store.ClaimIdempotencyKey(EntityContact, "k1", first)
+	if err != nil || !claimed || held != first {
+		t.Fatalf("first claim = %v claimed:%v err:%v, want %v claimed", held, claimed, err, first)
+	}
+
+	second := uuid.New()
+	held, claimed, err = store.ClaimIdempotencyKey(EntityContact, "k1", second)
+	if err != nil || claimed || held != first {
+		t.Errorf("second claim = %v claimed:%v err:%v, want %v unclaimed", held, claimed, err, first)
+	}
+	if _, claimed, _ := store.ClaimIdempotencyKey(EntityCompany, "k1", second); !claimed {
+		t.Error("key claimed for contacts should not block companies")
+	}
+
+	if err := store.ReleaseIdempotencyKey(EntityContact, "k1", second); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey with other ID: %v", err)
+	}
+	if held, claimed, _ := store.ClaimIdempotencyKey(EntityContact, "k1", second); claimed || held != first {
+		t.Errorf("release with another ID dropped the claim: got %v claimed:%v", held, claimed)
+	}
+
+	if err := store.ReleaseIdempotencyKey(EntityContact, "k1", first); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey: %v", err)
+	}
+	if held, claimed, err := store.ClaimIdempotencyKey(EntityContact, "k1", second); err != nil || !claimed || held != second {
+		t.Errorf("claim after release = %v claimed:%v err:%v, want %v claimed", held, claimed, err, second)
+	}
+}
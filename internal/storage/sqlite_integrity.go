@@ -0,0 +1,90 @@
+// ABOUTME: SQLite integrity check for references to deleted contacts and companies.
+// ABOUTME: Finds relationships, attachments, and notes whose entity rows no longer exist, and duplicate relationships.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CheckIntegrity reports relationships with a missing endpoint, attachments
+// and notes whose owning entity is missing, and duplicate relationships left
+// by databases that predate the relationship pair index.
+func (s *SqliteStore) CheckIntegrity() (*IntegrityReport, error) {
+	rels, err := s.queryIDs(`
+		SELECT r.id FROM relationships r
+		WHERE NOT EXISTS (SELECT 1 FROM contacts WHERE id = r.source_id)
+			AND NOT EXISTS (SELECT 1 FROM companies WHERE id = r.source_id)
+		UNION
+		SELECT r.id FROM relationships r
+		WHERE NOT EXISTS (SELECT 1 FROM contacts WHERE id = r.target_id)
+			AND NOT EXISTS (SELECT 1 FROM companies WHERE id = r.target_id)
+		ORDER BY 1`)
+	if err != nil {
+		return nil, fmt.Errorf("find dangling relationships: %w", err)
+	}
+	atts, err := s.queryIDs(`
+		SELECT a.id FROM attachments a
+		WHERE NOT EXISTS (SELECT 1 FROM contacts WHERE id = a.entity_id)
+			AND NOT EXISTS (SELECT 1 FROM companies WHERE id = a.entity_id)
+		ORDER BY a.id`)
+	if err != nil {
+		return nil, fmt.Errorf("find orphaned attachments: %w", err)
+	}
+	dups, err := s.queryIDs(`
+		SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY min(source_id, target_id), max(source_id, target_id), type
+				ORDER BY created_at DESC, rowid DESC
+			) AS dup
+			FROM relationships
+		) WHERE dup > 1
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("find duplicate relationships: %w", err)
+	}
+	report := &IntegrityReport{DanglingRelationships: rels, OrphanedAttachments: atts, DuplicateRelationships: dups}
+	if report.OrphanedCompanyNotes, err = s.orphanedNotes(companyNotes); err != nil {
+		return nil, err
+	}
+	if report.OrphanedContactNotes, err = s.orphanedNotes(contactNotes); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// orphanedNotes returns the notes in k's table whose owner no longer exists.
+func (s *SqliteStore) orphanedNotes(k noteKind) ([]uuid.UUID, error) {
+	ids, err := s.queryIDs(`
+		SELECT n.id FROM ` + k.table + ` n
+		WHERE NOT EXISTS (SELECT 1 FROM ` + k.ownerTable + ` WHERE id = n.` + k.ownerColumn + `)
+		ORDER BY n.id`)
+	if err != nil {
+		return nil, fmt.Errorf("find orphaned %s notes: %w", k.entityType, err)
+	}
+	return ids, nil
+}
+
+// queryIDs runs a query selecting a single UUID column.
+func (s *SqliteStore) queryIDs(query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var idStr string
+		if err := rows.Scan(&idStr); err != nil {
+			return nil, err
+		}
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
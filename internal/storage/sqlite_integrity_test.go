@@ -0,0 +1,74 @@
+// ABOUTME: Tests for the SQLite integrity check.
+// ABOUTME: Inserts references to missing entities directly and checks they are reported.
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	store := newTestStore(t)
+
+	ids := newTestEntities(t, store, 2)
+	if err := store.CreateRelationship(models.NewRelationship(ids[0], ids[1], "knows", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	kept := models.NewAttachment(EntityContact, ids[0], "cv.pdf")
+	kept.Path = "/tmp/cv.pdf"
+	if err := store.AddAttachment(kept); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	report, err := store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	if report.Total() != 0 {
+		t.Fatalf("clean store reported %+v", report)
+	}
+
+	// The store's own writes never dangle, so break references directly.
+	danglingRel, orphanAtt := uuid.New(), uuid.New()
+	_, err = store.db.Exec(`INSERT INTO relationships (id, source_id, target_id, type, context, created_at)
+		VALUES (?, ?, ?, 'knows', '', ?)`, danglingRel.String(), ids[0].String(), uuid.New().String(), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("insert dangling relationship: %v", err)
+	}
+	_, err = store.db.Exec(`INSERT INTO attachments (id, entity_type, entity_id, filename, path, created_at)
+		VALUES (?, 'contact', ?, 'gone.txt', '/tmp/gone.txt', ?)`, orphanAtt.String(), uuid.New().String(), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("insert orphaned attachment: %v", err)
+	}
+	orphanCompanyNote, orphanContactNote := uuid.New(), uuid.New()
+	_, err = store.db.Exec(`INSERT INTO company_notes (id, company_id, content, created_at) VALUES (?, ?, 'gone', ?)`,
+		orphanCompanyNote.String(), uuid.New().String(), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("insert orphaned company note: %v", err)
+	}
+	_, err = store.db.Exec(`INSERT INTO contact_notes (id, contact_id, content, created_at) VALUES (?, ?, 'gone', ?)`,
+		orphanContactNote.String(), uuid.New().String(), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("insert orphaned contact note: %v", err)
+	}
+
+	report, err = store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	if len(report.DanglingRelationships) != 1 || report.DanglingRelationships[0] != danglingRel {
+		t.Errorf("DanglingRelationships = %v, want [%s]", report.DanglingRelationships, danglingRel)
+	}
+	if len(report.OrphanedAttachments) != 1 || report.OrphanedAttachments[0] != orphanAtt {
+		t.Errorf("OrphanedAttachments = %v, want [%s]", report.OrphanedAttachments, orphanAtt)
+	}
+	if len(report.OrphanedCompanyNotes) != 1 || report.OrphanedCompanyNotes[0] != orphanCompanyNote {
+		t.Errorf("OrphanedCompanyNotes = %v, want [%s]", report.OrphanedCompanyNotes, orphanCompanyNote)
+	}
+	if len(report.OrphanedContactNotes) != 1 || report.OrphanedContactNotes[0] != orphanContactNote {
+		t.Errorf("OrphanedContactNotes = %v, want [%s]", report.OrphanedContactNotes, orphanContactNote)
+	}
+}
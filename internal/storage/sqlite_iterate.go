@@ -0,0 +1,63 @@
+// ABOUTME: SQLite streaming iteration over contacts and companies.
+// ABOUTME: Invokes a callback per row so full scans never hold the whole table in memory.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+// ForEachContact calls fn for every contact, one row at a time, stopping at
+// and returning the first error fn returns. Contacts arrive in no particular
+// order. fn runs while the query is open, so it must not write to the store.
+func (s *SqliteStore) ForEachContact(fn func(*models.Contact) error) error {
+	rows, err := s.db.Query(`
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+		FROM contacts`)
+	if err != nil {
+		return fmt.Errorf("iterate contacts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		c, err := scanContactRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate contact rows: %w", err)
+	}
+	return nil
+}
+
+// ForEachCompany calls fn for every company, one row at a time, stopping at
+// and returning the first error fn returns. Companies arrive in no particular
+// order. fn runs while the query is open, so it must not write to the store.
+func (s *SqliteStore) ForEachCompany(fn func(*models.Company) error) error {
+	rows, err := s.db.Query(`
+		SELECT id, name, domain, fields, tags, created_at, updated_at, version, source
+		FROM companies`)
+	if err != nil {
+		return fmt.Errorf("iterate companies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		c, err := scanCompanyRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate company rows: %w", err)
+	}
+	return nil
+}
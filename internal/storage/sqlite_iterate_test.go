@@ -0,0 +1,69 @@
+// ABOUTME: Tests for SQLite streaming iteration over contacts and companies.
+// ABOUTME: Verifies every row is visited and that callback errors stop iteration.
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestForEachContact(t *testing.T) {
+	store := newTestStore(t)
+	newTestEntities(t, store, 3)
+
+	seen := 0
+	err := store.ForEachContact(func(c *models.Contact) error {
+		if c.Name == "" {
+			t.Errorf("contact %s has no name", c.ID)
+		}
+		seen++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachContact: %v", err)
+	}
+	if seen != 3 {
+		t.Errorf("visited %d contacts, want 3", seen)
+	}
+}
+
+func TestForEachContactStopsOnError(t *testing.T) {
+	store := newTestStore(t)
+	newTestEntities(t, store, 3)
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := store.ForEachContact(func(*models.Contact) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("callback ran %d times, want 1", calls)
+	}
+}
+
+func TestForEachCompany(t *testing.T) {
+	store := newTestStore(t)
+	for _, name := range []string{"Acme", "Globex"} {
+		if err := store.CreateCompany(models.NewCompany(name)); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+
+	names := make(map[string]bool)
+	err := store.ForEachCompany(func(c *models.Company) error {
+		names[c.Name] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachCompany: %v", err)
+	}
+	if len(names) != 2 || !names["Acme"] || !names["Globex"] {
+		t.Errorf("visited %v, want Acme and Globex", names)
+	}
+}
@@ -0,0 +1,181 @@
+// ABOUTME: SQLite lookups of contacts by email and companies by domain or name.
+// ABOUTME: Returns the oldest record when several match; GetOrCreate variants create on a miss.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// GetContactByEmail returns the contact with the given email address,
+// ignoring case and surrounding whitespace. Returns ErrContactNotFound if
+// none matches.
+func (s *SqliteStore) GetContactByEmail(email string) (*models.Contact, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return nil, ErrContactNotFound
+	}
+	row := s.db.QueryRow(`
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+		FROM contacts WHERE lower(trim(email)) = ?
+		ORDER BY created_at LIMIT 1`, email)
+	return scanContact(row)
+}
+
+// ListContactsByEmailDomain returns every contact whose email address is at
+// domain, newest first. The domain is normalized, and only exact domain
+// matches count, so "acme.com" does not match "eng.acme.com".
+func (s *SqliteStore) ListContactsByEmailDomain(domain string) ([]*models.Contact, error) {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return nil, nil
+	}
+
+	// Narrow with LIKE, then apply the exact normalized comparison in Go.
+	rows, err := s.db.Query(`
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+		FROM contacts WHERE lower(trim(email)) LIKE ?
+		ORDER BY created_at DESC`, "%@%"+domain+"%")
+	if err != nil {
+		return nil, fmt.Errorf("list contacts by email domain: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var contacts []*models.Contact
+	for rows.Next() {
+		c, err := scanContactRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		if EmailDomain(c.Email) == domain {
+			contacts = append(contacts, c)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// GetCompanyByDomain returns the company whose domain matches after
+// normalization (case, scheme, "www." and path are ignored). Returns
+// ErrCompanyNotFound if none matches.
+func (s *SqliteStore) GetCompanyByDomain(domain string) (*models.Company, error) {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return nil, ErrCompanyNotFound
+	}
+
+	// Narrow with LIKE, then apply the exact normalized comparison in Go.
+	rows, err := s.db.Query(`
+		SELECT id, name, domain, fields, tags, created_at, updated_at, version, source
+		FROM companies WHERE lower(domain) LIKE ?
+		ORDER BY created_at`, "%"+domain+"%")
+	if err != nil {
+		return nil, fmt.Errorf("query company by domain: %w", err)
+	}
+	companies, err := scanCompanyRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range companies {
+		if NormalizeDomain(c.Domain) == domain {
+			return c, nil
+		}
+	}
+	return nil, ErrCompanyNotFound
+}
+
+// GetOrCreateCompany returns the oldest company named name, creating one if
+// none exists. Surrounding whitespace is ignored; otherwise the name must
+// match exactly. The bool reports whether a company was created.
+func (s *SqliteStore) GetOrCreateCompany(name string) (*models.Company, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, ErrNameRequired
+	}
+	row := s.db.QueryRow(`
+		SELECT id, name, domain, fields, tags, created_at, updated_at, version, source
+		FROM companies WHERE trim(name) = ?
+		ORDER BY created_at LIMIT 1`, name)
+	c, err := scanCompany(row)
+	if err == nil {
+		return c, false, nil
+	}
+	if !errors.Is(err, ErrCompanyNotFound) {
+		return nil, false, err
+	}
+
+	c = models.NewCompany(name)
+	if err := s.CreateCompany(c); err != nil {
+		return nil, false, err
+	}
+	return c, true, nil
+}
+
+// GetOrCreateContact returns the contact with email if there is one, else
+// the oldest contact named name (surrounding whitespace ignored), else a
+// newly created contact. A new contact is linked to companyID with a
+// "works_at" relationship when companyID is non-nil; existing contacts are
+// returned unchanged. The bool reports whether a contact was created.
+func (s *SqliteStore) GetOrCreateContact(name, email string, companyID *uuid.UUID) (*models.Contact, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, ErrNameRequired
+	}
+	c, err := s.GetContactByEmail(email)
+	if err == nil {
+		return c, false, nil
+	}
+	if !errors.Is(err, ErrContactNotFound) {
+		return nil, false, err
+	}
+	row := s.db.QueryRow(`
+		SELECT id, name, email, phone, fields, tags, created_at, updated_at, do_not_contact, version, source
+		FROM contacts WHERE trim(name) = ?
+		ORDER BY created_at LIMIT 1`, name)
+	c, err = scanContact(row)
+	if err == nil {
+		return c, false, nil
+	}
+	if !errors.Is(err, ErrContactNotFound) {
+		return nil, false, err
+	}
+
+	if companyID != nil {
+		if _, err := s.GetCompany(*companyID); err != nil {
+			return nil, false, err
+		}
+	}
+	c = models.NewContact(name)
+	c.Email = strings.TrimSpace(email)
+	if s.opts.EnforceUniqueEmail {
+		if err := checkUniqueEmail(s, c); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// Insert the contact and its relationship together so a failed link
+	// leaves no unlinked contact behind.
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := insertContact(tx, c); err != nil {
+		return nil, false, err
+	}
+	if companyID != nil {
+		if err := insertRelationship(tx, models.NewRelationship(c.ID, *companyID, worksAtType, "")); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return c, true, nil
+}
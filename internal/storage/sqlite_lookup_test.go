@@ -0,0 +1,203 @@
+// ABOUTME: Tests for SQLite contact-by-email and company-by-domain lookups.
+// ABOUTME: Verifies case-insensitive matching, domain normalization, and not-found errors.
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestGetContactByEmail(t *testing.T) {
+	store := newTestStore(t)
+
+	c := models.NewContact("Jane")
+	c.Email = "Jane@Acme.com"
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	got, err := store.GetContactByEmail(" jane@acme.COM ")
+	if err != nil {
+		t.Fatalf("GetContactByEmail: %v", err)
+	}
+	if got.ID != c.ID {
+		t.Errorf("GetContactByEmail ID = %v, want %v", got.ID, c.ID)
+	}
+
+	if _, err := store.GetContactByEmail("nobody@acme.com"); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("expected ErrContactNotFound, got %v", err)
+	}
+	if _, err := store.GetContactByEmail(""); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("empty email: expected ErrContactNotFound, got %v", err)
+	}
+}
+
+func TestGetCompanyByDomain(t *testing.T) {
+	store := newTestStore(t)
+
+	acme := models.NewCompany("Acme")
+	acme.Domain = "https://www.Acme.com/"
+	other := models.NewCompany("Notacme")
+	other.Domain = "notacme.com"
+	for _, c := range []*models.Company{acme, other} {
+		if err := store.CreateCompany(c); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+
+	got, err := store.GetCompanyByDomain("acme.com")
+	if err != nil {
+		t.Fatalf("GetCompanyByDomain: %v", err)
+	}
+	if got.ID != acme.ID {
+		t.Errorf("GetCompanyByDomain = %s, want Acme", got.Name)
+	}
+
+	if _, err := store.GetCompanyByDomain("example.com"); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("expected ErrCompanyNotFound, got %v", err)
+	}
+}
+
+func TestGetOrCreateCompany(t *testing.T) {
+	store := newTestStore(t)
+
+	created, isNew, err := store.GetOrCreateCompany("  Acme  ")
+	if err != nil {
+		t.Fatalf("GetOrCreateCompany: %v", err)
+	}
+	if !isNew || created.Name != "Acme" {
+		t.Fatalf("first call = (%q, %v), want a new company named Acme", created.Name, isNew)
+	}
+
+	again, isNew, err := store.GetOrCreateCompany("Acme")
+	if err != nil {
+		t.Fatalf("GetOrCreateCompany: %v", err)
+	}
+	if isNew || again.ID != created.ID {
+		t.Errorf("second call = (%v, %v), want existing %v", again.ID, isNew, created.ID)
+	}
+
+	// Only surrounding whitespace is ignored; a different case is a different name.
+	other, isNew, err := store.GetOrCreateCompany("ACME")
+	if err != nil {
+		t.Fatalf("GetOrCreateCompany: %v", err)
+	}
+	if !isNew || other.ID == created.ID {
+		t.Errorf("ACME = (%v, %v), want a new company", other.ID, isNew)
+	}
+
+	if _, _, err := store.GetOrCreateCompany("   "); !errors.Is(err, ErrNameRequired) {
+		t.Errorf("blank name err = %v, want ErrNameRequired", err)
+	}
+}
+
+func TestGetOrCreateContact(t *testing.T) {
+	store := newTestStore(t)
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	created, isNew, err := store.GetOrCreateContact(" Jane Doe ", "jane@acme.com", &acme.ID)
+	if err != nil {
+		t.Fatalf("GetOrCreateContact: %v", err)
+	}
+	if !isNew || created.Name != "Jane Doe" || created.Email != "jane@acme.com" {
+		t.Fatalf("first call = (%+v, %v), want new Jane Doe with email", created, isNew)
+	}
+	rels, err := store.ListRelationships(created.ID)
+	if err != nil {
+		t.Fatalf("ListRelationships: %v", err)
+	}
+	if len(rels) != 1 || rels[0].TargetID != acme.ID || rels[0].Type != "works_at" {
+		t.Errorf("relationships = %+v, want one works_at link to Acme", rels)
+	}
+
+	// Email wins over name.
+	byEmail, isNew, err := store.GetOrCreateContact("J. Doe", "JANE@acme.com", nil)
+	if err != nil {
+		t.Fatalf("GetOrCreateContact by email: %v", err)
+	}
+	if isNew || byEmail.ID != created.ID {
+		t.Errorf("email match = (%v, %v), want existing %v", byEmail.ID, isNew, created.ID)
+	}
+
+	// With no email match, fall back to the exact name.
+	byName, isNew, err := store.GetOrCreateContact("Jane Doe", "jane@personal.example", nil)
+	if err != nil {
+		t.Fatalf("GetOrCreateContact by name: %v", err)
+	}
+	if isNew || byName.ID != created.ID {
+		t.Errorf("name match = (%v, %v), want existing %v", byName.ID, isNew, created.ID)
+	}
+
+	missing := uuid.New()
+	if _, _, err := store.GetOrCreateContact("Nobody", "", &missing); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("unknown company err = %v, want ErrCompanyNotFound", err)
+	}
+	if all, _ := store.GetAllContacts(); len(all) != 1 {
+		t.Errorf("contacts = %d, want 1 (no contact created for an unknown company)", len(all))
+	}
+}
+
+func TestGetOrCreateContactRollsBackOnLinkFailure(t *testing.T) {
+	store := newTestStore(t)
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	if _, err := store.db.Exec(`CREATE TRIGGER fail_relationships BEFORE INSERT ON relationships BEGIN
+		SELECT RAISE(ABORT, 'relationship insert failed');
+	END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	if _, _, err := store.GetOrCreateContact("Jane Doe", "jane@acme.com", &acme.ID); err == nil {
+		t.Fatal("GetOrCreateContact with failing link: expected error, got nil")
+	}
+	if all, _ := store.GetAllContacts(); len(all) != 0 {
+		t.Errorf("contacts = %d, want 0 after the failed link", len(all))
+	}
+}
+
+// checkContactsByEmailDomain verifies exact, normalized domain matching on
+// either backend.
+func checkContactsByEmailDomain(t *testing.T, store Storage) {
+	t.Helper()
+	for name, email := range map[string]string{
+		"Jane":  "Jane@ACME.com ",
+		"Raj":   "raj@acme.com",
+		"Sub":   "sub@eng.acme.com",
+		"Other": "other@notacme.com",
+		"None":  "",
+	} {
+		c := models.NewContact(name)
+		c.Email = email
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact %s: %v", name, err)
+		}
+	}
+
+	got, err := store.ListContactsByEmailDomain("https://www.Acme.com/")
+	if err != nil {
+		t.Fatalf("ListContactsByEmailDomain: %v", err)
+	}
+	names := map[string]bool{}
+	for _, c := range got {
+		names[c.Name] = true
+	}
+	if len(got) != 2 || !names["Jane"] || !names["Raj"] {
+		t.Errorf("ListContactsByEmailDomain = %v, want Jane and Raj", names)
+	}
+
+	if got, err := store.ListContactsByEmailDomain(" "); err != nil || len(got) != 0 {
+		t.Errorf("blank domain = %d contacts (err %v), want none", len(got), err)
+	}
+}
+
+func TestListContactsByEmailDomain(t *testing.T) {
+	checkContactsByEmailDomain(t, newTestStore(t))
+}
@@ -0,0 +1,244 @@
+// ABOUTME: Additive schema migrations for existing SQLite databases.
+// ABOUTME: Adds columns introduced after a table's first release, backfills new constraints, and rewrites legacy timestamps in UTC.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// columnMigration describes a column added to a table after its first release.
+type columnMigration struct {
+	table  string
+	column string
+	ddl    string
+}
+
+// columnMigrations lists added columns in release order. New columns go
+// here rather than into the CREATE TABLE statements, so fresh and existing
+// databases converge on the same schema.
+var columnMigrations = []columnMigration{
+	{table: "contacts", column: "do_not_contact", ddl: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "contacts", column: "version", ddl: "INTEGER NOT NULL DEFAULT 1"},
+	{table: "companies", column: "version", ddl: "INTEGER NOT NULL DEFAULT 1"},
+	{table: "contacts", column: "phone_key", ddl: "TEXT NOT NULL DEFAULT ''"},
+	{table: "contacts", column: "photo", ddl: "BLOB"},
+	{table: "contacts", column: "photo_type", ddl: "TEXT NOT NULL DEFAULT ''"},
+	{table: "contacts", column: "source", ddl: "TEXT NOT NULL DEFAULT ''"},
+	{table: "companies", column: "source", ddl: "TEXT NOT NULL DEFAULT ''"},
+}
+
+// addMissingColumns applies every column migration whose column is absent.
+func addMissingColumns(tx *sql.Tx) error {
+	for _, m := range columnMigrations {
+		exists, err := columnExists(tx, m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.ddl)
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("add column %s.%s: %w", m.table, m.column, err)
+		}
+	}
+	return nil
+}
+
+// columnExists reports whether table has a column with the given name.
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return false, fmt.Errorf("table info %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, fmt.Errorf("scan table info: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("iterate table info: %w", err)
+	}
+	return false, nil
+}
+
+// ensureRelationshipPairIndex adds the unique index that allows at most one
+// relationship of each type between a pair of entities, regardless of
+// direction. Databases created before the index existed may hold duplicates;
+// those are never removed on open. The index is left off until
+// "crm doctor --fix" removes them, and CreateRelationship's own check keeps
+// new duplicates out in the meantime.
+func ensureRelationshipPairIndex(tx *sql.Tx) error {
+	var n int
+	err := tx.QueryRow(
+		"SELECT count(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_relationships_pair'",
+	).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("check relationship pair index: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	var groups int
+	err = tx.QueryRow(`
+		SELECT count(*) FROM (
+			SELECT 1 FROM relationships
+			GROUP BY min(source_id, target_id), max(source_id, target_id), type
+			HAVING count(*) > 1
+		)`).Scan(&groups)
+	if err != nil {
+		return fmt.Errorf("check duplicate relationships: %w", err)
+	}
+	if groups > 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(`CREATE UNIQUE INDEX idx_relationships_pair
+		ON relationships(min(source_id, target_id), max(source_id, target_id), type)`)
+	if err != nil {
+		return fmt.Errorf("create relationship pair index: %w", err)
+	}
+	return nil
+}
+
+// migratePhoneKeys fills phone_key for contacts saved before the column
+// existed and indexes it. The index is created here rather than in
+// tableStatements because the column may only just have been added.
+func migratePhoneKeys(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, phone FROM contacts WHERE phone_key = '' AND phone != ''")
+	if err != nil {
+		return fmt.Errorf("select phones to backfill: %w", err)
+	}
+	pending := map[string]string{}
+	for rows.Next() {
+		var id, phone string
+		if err := rows.Scan(&id, &phone); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan phone: %w", err)
+		}
+		if key := phoneSearchKey(phone); key != "" {
+			pending[id] = key
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("close phone rows: %w", err)
+	}
+	for id, key := range pending {
+		if _, err := tx.Exec("UPDATE contacts SET phone_key = ? WHERE id = ?", key, id); err != nil {
+			return fmt.Errorf("backfill phone key: %w", err)
+		}
+	}
+	if _, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_contacts_phone_key ON contacts(phone_key)"); err != nil {
+		return fmt.Errorf("create phone key index: %w", err)
+	}
+	return nil
+}
+
+// utcTimestampsVersion is the user_version recorded once every DATETIME
+// column has been rewritten in UTC.
+const utcTimestampsVersion = 1
+
+// normalizeTimestamps rewrites every DATETIME value in UTC, once per
+// database. Rows saved before writes were made UTC carry the writer's local
+// offset, and since these columns compare as text, mixed offsets sort out of
+// order in ORDER BY, cursor pages, and modified-since queries.
+func normalizeTimestamps(tx *sql.Tx) error {
+	var version int
+	if err := tx.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if version >= utcTimestampsVersion {
+		return nil
+	}
+
+	// Rewriting a timestamp is not an edit, so keep it out of the change
+	// feed; initSchema recreates these triggers after this step.
+	for _, trigger := range []string{"contacts_feed_au", "companies_feed_au"} {
+		if _, err := tx.Exec("DROP TRIGGER IF EXISTS " + trigger); err != nil {
+			return fmt.Errorf("drop %s: %w", trigger, err)
+		}
+	}
+
+	columns, err := datetimeColumns(tx)
+	if err != nil {
+		return err
+	}
+	for _, c := range columns {
+		if err := rewriteUTC(tx, c[0], c[1]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", utcTimestampsVersion)); err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+	return nil
+}
+
+// datetimeColumns returns the (table, column) pairs declared DATETIME.
+func datetimeColumns(tx *sql.Tx) ([][2]string, error) {
+	rows, err := tx.Query(`
+		SELECT m.name, p.name FROM sqlite_master m, pragma_table_info(m.name) p
+		WHERE m.type = 'table' AND upper(p.type) = 'DATETIME'
+		ORDER BY m.name, p.cid`)
+	if err != nil {
+		return nil, fmt.Errorf("list datetime columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns [][2]string
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, fmt.Errorf("scan datetime column: %w", err)
+		}
+		columns = append(columns, [2]string{table, column})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate datetime columns: %w", err)
+	}
+	return columns, nil
+}
+
+// rewriteUTC stores each value of table.column that is not already UTC
+// back in UTC. Values the driver cannot parse as times are left alone.
+func rewriteUTC(tx *sql.Tx, table, column string) error {
+	//nolint:gosec // table and column come from sqlite_master, not user input
+	rows, err := tx.Query(fmt.Sprintf(`SELECT rowid, %q FROM %q WHERE %q NOT LIKE '%% +0000 UTC'`, column, table, column))
+	if err != nil {
+		return fmt.Errorf("select %s.%s: %w", table, column, err)
+	}
+	pending := map[int64]time.Time{}
+	for rows.Next() {
+		var rowid int64
+		var value any
+		if err := rows.Scan(&rowid, &value); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan %s.%s: %w", table, column, err)
+		}
+		if t, ok := value.(time.Time); ok {
+			pending[rowid] = t.UTC()
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("close %s.%s rows: %w", table, column, err)
+	}
+
+	//nolint:gosec // table and column come from sqlite_master, not user input
+	update := fmt.Sprintf(`UPDATE %q SET %q = ? WHERE rowid = ?`, table, column)
+	for rowid, t := range pending {
+		if _, err := tx.Exec(update, t, rowid); err != nil {
+			return fmt.Errorf("rewrite %s.%s: %w", table, column, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// ABOUTME: Tests for additive SQLite column migrations.
+// ABOUTME: Opens databases created with an older schema and checks they are upgraded in place without losing rows.
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMigrateAddsMissingColumns(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+
+	// Create a contacts table as it looked before do_not_contact existed.
+	old, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, err = old.Exec(`CREATE TABLE contacts (
+		rowid INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT UNIQUE NOT NULL,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL DEFAULT '',
+		phone TEXT NOT NULL DEFAULT '',
+		fields TEXT NOT NULL DEFAULT '{}',
+		tags TEXT NOT NULL DEFAULT '[]',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+	_, err = old.Exec(`INSERT INTO contacts (id, name, phone, created_at, updated_at)
+		VALUES ('11111111-1111-1111-1111-111111111111', 'Legacy', '(555) 010-0199', '2024-01-01 00:00:00', '2024-01-01 00:00:00')`)
+	if err != nil {
+		t.Fatalf("insert legacy row: %v", err)
+	}
+	_ = old.Close()
+
+	store, err := NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore on old schema: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	if !tableColumnExists(store.db, "contacts", "do_not_contact") {
+		t.Fatal("expected do_not_contact column to be added")
+	}
+	contacts, err := store.ListContacts(nil)
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].Name != "Legacy" || contacts[0].DoNotContact || contacts[0].Version != 1 {
+		t.Errorf("legacy contacts = %+v, want one Legacy contact at version 1 without the flag", contacts)
+	}
+
+	found, err := store.ListContacts(&ContactFilter{Search: "5550100199"})
+	if err != nil {
+		t.Fatalf("ListContacts by phone: %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("phone search on legacy row found %d contacts, want 1 after backfill", len(found))
+	}
+}
+
+func TestMigrateKeepsDuplicateRelationships(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "dupes.db")
+
+	store, err := NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore: %v", err)
+	}
+	ids := newTestEntities(t, store, 2)
+
+	// Simulate a database from before the pair index existed.
+	if _, err := store.db.Exec("DROP INDEX idx_relationships_pair"); err != nil {
+		t.Fatalf("drop index: %v", err)
+	}
+	rows := []struct {
+		id, src, tgt, created string
+	}{
+		{"aaaaaaaa-0000-0000-0000-000000000001", ids[0].String(), ids[1].String(), "2024-01-01 00:00:00"},
+		{"aaaaaaaa-0000-0000-0000-000000000002", ids[1].String(), ids[0].String(), "2024-06-01 00:00:00"},
+		{"aaaaaaaa-0000-0000-0000-000000000003", ids[0].String(), ids[1].String(), "2024-03-01 00:00:00"},
+	}
+	for _, r := range rows {
+		_, err := store.db.Exec(`INSERT INTO relationships (id, source_id, target_id, type, context, created_at)
+			VALUES (?, ?, ?, 'colleague', '', ?)`, r.id, r.src, r.tgt, r.created)
+		if err != nil {
+			t.Fatalf("insert duplicate: %v", err)
+		}
+	}
+	_ = store.Close()
+
+	store, err = NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	rels, err := store.ListRelationships(ids[0])
+	if err != nil {
+		t.Fatalf("ListRelationships: %v", err)
+	}
+	if len(rels) != len(rows) {
+		t.Fatalf("reopen kept %d relationships, want all %d", len(rels), len(rows))
+	}
+
+	report, err := store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	want := []string{rows[0].id, rows[2].id}
+	if len(report.DuplicateRelationships) != 2 ||
+		report.DuplicateRelationships[0].String() != want[0] || report.DuplicateRelationships[1].String() != want[1] {
+		t.Fatalf("DuplicateRelationships = %v, want %v", report.DuplicateRelationships, want)
+	}
+
+	// Once the duplicates are removed, the next open adds the index.
+	for _, id := range report.DuplicateRelationships {
+		if err := store.DeleteRelationship(id); err != nil {
+			t.Fatalf("DeleteRelationship: %v", err)
+		}
+	}
+	_ = store.Close()
+	store, err = NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen after cleanup: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	var n int
+	if err := store.db.QueryRow(
+		"SELECT count(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_relationships_pair'",
+	).Scan(&n); err != nil || n != 1 {
+		t.Errorf("pair index present = %d (err %v), want 1 after cleanup", n, err)
+	}
+}
+
+func TestMigrateNormalizesTimestamps(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "zones.db")
+
+	store, err := NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore: %v", err)
+	}
+	// Simulate rows written before timestamps were stored in UTC: "west"
+	// is later in real time but its local-offset text sorts first.
+	west, east := uuid.New(), uuid.New()
+	for _, r := range []struct {
+		id uuid.UUID
+		at string
+	}{
+		{west, "2024-01-01 10:00:00 -0800 PST"},   // 18:00 UTC
+		{east, "2024-01-01 12:00:00.5 +0000 UTC"}, // 12:00 UTC
+	} {
+		_, err := store.db.Exec(`INSERT INTO contacts (id, name, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+			r.id.String(), r.id.String(), r.at, r.at)
+		if err != nil {
+			t.Fatalf("insert legacy contact: %v", err)
+		}
+	}
+	if _, err := store.db.Exec("PRAGMA user_version = 0"); err != nil {
+		t.Fatalf("reset user_version: %v", err)
+	}
+	var seq int64
+	if err := store.db.QueryRow("SELECT coalesce(max(seq), 0) FROM change_feed").Scan(&seq); err != nil {
+		t.Fatalf("read change feed: %v", err)
+	}
+	_ = store.Close()
+
+	store, err = NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	var raw string
+	if err := store.db.QueryRow("SELECT created_at || '' FROM contacts WHERE id = ?", west.String()).Scan(&raw); err != nil {
+		t.Fatalf("read raw created_at: %v", err)
+	}
+	if raw != "2024-01-01 18:00:00 +0000 UTC" {
+		t.Errorf("created_at = %q, want rewritten in UTC", raw)
+	}
+
+	page, cursor, err := store.ListContactsAfter(Cursor{}, 1)
+	if err != nil || len(page) != 1 || page[0].ID != east {
+		t.Fatalf("first page = %v (err %v), want east", page, err)
+	}
+	page, _, err = store.ListContactsAfter(cursor, 1)
+	if err != nil || len(page) != 1 || page[0].ID != west {
+		t.Fatalf("second page = %v (err %v), want west", page, err)
+	}
+
+	modified, err := store.GetContactsModifiedSince(time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetContactsModifiedSince: %v", err)
+	}
+	if len(modified) != 1 || modified[0].ID != west {
+		t.Errorf("modified since 15:00 UTC = %v, want only west", modified)
+	}
+
+	events, err := store.ReadChangeFeed(seq, 10)
+	if err != nil || len(events) != 0 {
+		t.Errorf("change feed after normalizing = %v (err %v), want no events", events, err)
+	}
+}
@@ -0,0 +1,124 @@
+// ABOUTME: SQLite storage for the running notes history kept on contacts and companies.
+// ABOUTME: Each note is a timestamped row in contact_notes or company_notes, listed oldest first.
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// AddCompanyNote appends a note to a company's history. It returns
+// ErrNoteContentRequired for blank content and ErrCompanyNotFound if the
+// company does not exist.
+func (s *SqliteStore) AddCompanyNote(n *models.CompanyNote) error {
+	return s.addNote(companyNotes, fromCompanyNote(n))
+}
+
+// ListCompanyNotes returns a company's notes, oldest first.
+func (s *SqliteStore) ListCompanyNotes(companyID uuid.UUID) ([]*models.CompanyNote, error) {
+	notes, err := s.listNotes(companyNotes, companyID)
+	return toCompanyNotes(notes), err
+}
+
+// DeleteCompanyNote removes a note by UUID, returning ErrNoteNotFound if no
+// row matches.
+func (s *SqliteStore) DeleteCompanyNote(id uuid.UUID) error {
+	return s.deleteNote(companyNotes, id)
+}
+
+// AddContactNote appends a note to a contact's history. It returns
+// ErrNoteContentRequired for blank content and ErrContactNotFound if the
+// contact does not exist.
+func (s *SqliteStore) AddContactNote(n *models.ContactNote) error {
+	return s.addNote(contactNotes, fromContactNote(n))
+}
+
+// ListContactNotes returns a contact's notes, oldest first.
+func (s *SqliteStore) ListContactNotes(contactID uuid.UUID) ([]*models.ContactNote, error) {
+	notes, err := s.listNotes(contactNotes, contactID)
+	return toContactNotes(notes), err
+}
+
+// DeleteContactNote removes a note by UUID, returning ErrNoteNotFound if no
+// row matches.
+func (s *SqliteStore) DeleteContactNote(id uuid.UUID) error {
+	return s.deleteNote(contactNotes, id)
+}
+
+// addNote inserts n into k's table after checking its owner exists.
+func (s *SqliteStore) addNote(k noteKind, n note) error {
+	if err := validateNoteContent(n.Content); err != nil {
+		return err
+	}
+	var ok bool
+	if err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM "+k.ownerTable+" WHERE id = ?)", n.OwnerID.String()).Scan(&ok); err != nil {
+		return fmt.Errorf("check %s exists: %w", k.entityType, err)
+	}
+	if !ok {
+		return k.notFound
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO `+k.table+` (id, `+k.ownerColumn+`, content, created_at)
+		VALUES (?, ?, ?, ?)`,
+		n.ID.String(), n.OwnerID.String(), n.Content, n.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert %s note: %w", k.entityType, err)
+	}
+	return nil
+}
+
+// listNotes returns the notes in k's table for ownerID, oldest first.
+func (s *SqliteStore) listNotes(k noteKind, ownerID uuid.UUID) ([]note, error) {
+	rows, err := s.db.Query(`
+		SELECT id, `+k.ownerColumn+`, content, created_at
+		FROM `+k.table+` WHERE `+k.ownerColumn+` = ?
+		ORDER BY created_at, rowid`, ownerID.String())
+	if err != nil {
+		return nil, fmt.Errorf("list %s notes: %w", k.entityType, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var notes []note
+	for rows.Next() {
+		var n note
+		var idStr, ownerIDStr string
+		var createdAt time.Time
+		if err := rows.Scan(&idStr, &ownerIDStr, &n.Content, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan %s note: %w", k.entityType, err)
+		}
+		if n.ID, err = uuid.Parse(idStr); err != nil {
+			return nil, fmt.Errorf("parse note id: %w", err)
+		}
+		if n.OwnerID, err = uuid.Parse(ownerIDStr); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", k.ownerColumn, err)
+		}
+		n.CreatedAt = createdAt.UTC()
+		notes = append(notes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s notes: %w", k.entityType, err)
+	}
+	return notes, nil
+}
+
+// deleteNote removes the note id from k's table, returning ErrNoteNotFound
+// if no row matches.
+func (s *SqliteStore) deleteNote(k noteKind, id uuid.UUID) error {
+	res, err := s.db.Exec("DELETE FROM "+k.table+" WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("delete %s note: %w", k.entityType, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
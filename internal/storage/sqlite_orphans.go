@@ -0,0 +1,47 @@
+// ABOUTME: SQLite queries for finding orphaned contacts and companies.
+// ABOUTME: Uses anti-joins over relationships to detect entities missing a counterpart.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+// ListContactsWithoutCompany returns contacts that have no relationship,
+// in either direction, to any existing company.
+func (s *SqliteStore) ListContactsWithoutCompany() ([]*models.Contact, error) {
+	rows, err := s.db.Query(`
+		SELECT c.id, c.name, c.email, c.phone, c.fields, c.tags, c.created_at, c.updated_at, c.do_not_contact, c.version, c.source
+		FROM contacts c
+		WHERE NOT EXISTS (
+			SELECT 1 FROM relationships r
+			JOIN companies co
+				ON (r.source_id = c.id AND r.target_id = co.id)
+				OR (r.target_id = c.id AND r.source_id = co.id)
+		)
+		ORDER BY c.created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list contacts without company: %w", err)
+	}
+	return scanContactRows(rows)
+}
+
+// ListEmptyCompanies returns companies that have no relationship, in either
+// direction, to any existing contact.
+func (s *SqliteStore) ListEmptyCompanies() ([]*models.Company, error) {
+	rows, err := s.db.Query(`
+		SELECT co.id, co.name, co.domain, co.fields, co.tags, co.created_at, co.updated_at, co.version, co.source
+		FROM companies co
+		WHERE NOT EXISTS (
+			SELECT 1 FROM relationships r
+			JOIN contacts c
+				ON (r.source_id = c.id AND r.target_id = co.id)
+				OR (r.target_id = c.id AND r.source_id = co.id)
+		)
+		ORDER BY co.created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list empty companies: %w", err)
+	}
+	return scanCompanyRows(rows)
+}
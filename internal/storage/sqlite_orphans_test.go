@@ -0,0 +1,90 @@
+// ABOUTME: Tests for SQLite orphan detection queries.
+// ABOUTME: Verifies contacts without companies and companies without contacts are found.
+package storage
+
+import (
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestListContactsWithoutCompany(t *testing.T) {
+	store := newTestStore(t)
+
+	employed := models.NewContact("Employed")
+	loner := models.NewContact("Loner")
+	friend := models.NewContact("Friend")
+	acme := models.NewCompany("Acme")
+	for _, c := range []*models.Contact{employed, loner, friend} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	// Contact-to-company link in either direction counts; contact-to-contact does not.
+	rels := []*models.Relationship{
+		models.NewRelationship(acme.ID, employed.ID, "employs", ""),
+		models.NewRelationship(loner.ID, friend.ID, "knows", ""),
+	}
+	for _, r := range rels {
+		if err := store.CreateRelationship(r); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+
+	got, err := store.ListContactsWithoutCompany()
+	if err != nil {
+		t.Fatalf("ListContactsWithoutCompany: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	for _, c := range got {
+		if c.ID == employed.ID {
+			t.Error("employed contact should not be listed")
+		}
+	}
+}
+
+func TestListEmptyCompanies(t *testing.T) {
+	store := newTestStore(t)
+
+	alice := models.NewContact("Alice")
+	acme := models.NewCompany("Acme")
+	ghost := models.NewCompany("Ghost Inc")
+	parent := models.NewCompany("Parent Co")
+	if err := store.CreateContact(alice); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	for _, co := range []*models.Company{acme, ghost, parent} {
+		if err := store.CreateCompany(co); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+
+	rels := []*models.Relationship{
+		models.NewRelationship(alice.ID, acme.ID, "works_at", ""),
+		models.NewRelationship(ghost.ID, parent.ID, "subsidiary_of", ""),
+	}
+	for _, r := range rels {
+		if err := store.CreateRelationship(r); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+
+	got, err := store.ListEmptyCompanies()
+	if err != nil {
+		t.Fatalf("ListEmptyCompanies: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	for _, co := range got {
+		if co.ID == acme.ID {
+			t.Error("company with a contact should not be listed")
+		}
+	}
+}
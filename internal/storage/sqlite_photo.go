@@ -0,0 +1,59 @@
+// ABOUTME: SQLite storage for contact photos.
+// ABOUTME: Keeps the image bytes in contacts.photo, outside the columns a contact normally loads.
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SetContactPhoto stores data as the contact's photo, replacing any
+// previous one. Empty data removes the photo. The contact record itself,
+// including its updated_at and version, is left unchanged.
+func (s *SqliteStore) SetContactPhoto(id uuid.UUID, contentType string, data []byte) error {
+	if err := validatePhoto(contentType, data); err != nil {
+		return err
+	}
+	var photo any
+	if len(data) > 0 {
+		photo = data
+	} else {
+		contentType = ""
+	}
+
+	res, err := s.db.Exec("UPDATE contacts SET photo = ?, photo_type = ? WHERE id = ?",
+		photo, contentType, id.String())
+	if err != nil {
+		return fmt.Errorf("set contact photo: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrContactNotFound
+	}
+	return nil
+}
+
+// GetContactPhoto returns the contact's photo and its content type. It
+// returns ErrContactNotFound for an unknown contact and ErrPhotoNotFound
+// when the contact has no photo.
+func (s *SqliteStore) GetContactPhoto(id uuid.UUID) ([]byte, string, error) {
+	var data []byte
+	var contentType string
+	err := s.db.QueryRow("SELECT photo, photo_type FROM contacts WHERE id = ?", id.String()).Scan(&data, &contentType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrContactNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("get contact photo: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, "", ErrPhotoNotFound
+	}
+	return data, contentType, nil
+}
@@ -0,0 +1,72 @@
+// ABOUTME: Tests for SQLite contact photo storage.
+// ABOUTME: Covers set, replace, clear, validation, and keeping photos out of contact reads.
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestContactPhoto(t *testing.T) {
+	store := newTestStore(t)
+	c := models.NewContact("Alice")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	if _, _, err := store.GetContactPhoto(c.ID); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("GetContactPhoto before set: err = %v, want ErrPhotoNotFound", err)
+	}
+
+	if err := store.SetContactPhoto(c.ID, "image/jpeg", []byte("jpeg-bytes")); err != nil {
+		t.Fatalf("SetContactPhoto: %v", err)
+	}
+	data, contentType, err := store.GetContactPhoto(c.ID)
+	if err != nil {
+		t.Fatalf("GetContactPhoto: %v", err)
+	}
+	if !bytes.Equal(data, []byte("jpeg-bytes")) || contentType != "image/jpeg" {
+		t.Errorf("GetContactPhoto = %q %q, want jpeg-bytes image/jpeg", data, contentType)
+	}
+
+	got, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.Version != c.Version || !got.UpdatedAt.Equal(c.UpdatedAt) {
+		t.Errorf("setting a photo changed the contact: version %d updated %v", got.Version, got.UpdatedAt)
+	}
+
+	if err := store.SetContactPhoto(c.ID, "", nil); err != nil {
+		t.Fatalf("SetContactPhoto clear: %v", err)
+	}
+	if _, _, err := store.GetContactPhoto(c.ID); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("GetContactPhoto after clear: err = %v, want ErrPhotoNotFound", err)
+	}
+}
+
+func TestContactPhotoValidation(t *testing.T) {
+	store := newTestStore(t)
+	c := models.NewContact("Alice")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	if err := store.SetContactPhoto(c.ID, "application/pdf", []byte("x")); !errors.Is(err, ErrInvalidPhoto) {
+		t.Errorf("non-image type: err = %v, want ErrInvalidPhoto", err)
+	}
+	big := make([]byte, MaxContactPhotoSize+1)
+	if err := store.SetContactPhoto(c.ID, "image/png", big); !errors.Is(err, ErrPhotoTooLarge) {
+		t.Errorf("oversized photo: err = %v, want ErrPhotoTooLarge", err)
+	}
+	if err := store.SetContactPhoto(uuid.New(), "image/png", []byte("x")); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("unknown contact: err = %v, want ErrContactNotFound", err)
+	}
+	if _, _, err := store.GetContactPhoto(uuid.New()); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("GetContactPhoto unknown contact: err = %v, want ErrContactNotFound", err)
+	}
+}
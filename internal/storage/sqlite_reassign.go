@@ -0,0 +1,94 @@
+// ABOUTME: SQLite bulk move of a company's contacts to another company.
+// ABOUTME: Repoints contact-company relationships in one transaction, dropping ones the target already has.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ReassignCompanyContacts moves every contact related to company from over
+// to company to, keeping each relationship's type, context, and direction.
+// A relationship whose equivalent already joins the contact to to is
+// removed instead of duplicated. It returns the number of contacts moved,
+// or ErrCompanyNotFound if either company does not exist.
+func (s *SqliteStore) ReassignCompanyContacts(from, to uuid.UUID) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, id := range []uuid.UUID{from, to} {
+		var ok bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM companies WHERE id = ?)", id.String()).Scan(&ok); err != nil {
+			return 0, fmt.Errorf("check company exists: %w", err)
+		}
+		if !ok {
+			return 0, fmt.Errorf("%w: %s", ErrCompanyNotFound, id)
+		}
+	}
+	if from == to {
+		return 0, nil
+	}
+
+	type member struct {
+		relID, contactID, relType string
+	}
+	rows, err := tx.Query(`
+		SELECT r.id, c.id, r.type
+		FROM relationships r
+		JOIN contacts c ON c.id = CASE WHEN r.source_id = ? THEN r.target_id ELSE r.source_id END
+		WHERE r.source_id = ? OR r.target_id = ?`,
+		from.String(), from.String(), from.String())
+	if err != nil {
+		return 0, fmt.Errorf("list company contacts: %w", err)
+	}
+	var members []member
+	for rows.Next() {
+		var m member
+		if err := rows.Scan(&m.relID, &m.contactID, &m.relType); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("scan company contact: %w", err)
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, fmt.Errorf("iterate company contacts: %w", err)
+	}
+	_ = rows.Close()
+
+	moved := make(map[string]bool)
+	for _, m := range members {
+		var exists bool
+		err := tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM relationships
+				WHERE type = ? AND ((source_id = ? AND target_id = ?) OR (source_id = ? AND target_id = ?)))`,
+			m.relType, m.contactID, to.String(), to.String(), m.contactID,
+		).Scan(&exists)
+		if err != nil {
+			return 0, fmt.Errorf("check target relationship: %w", err)
+		}
+		if exists {
+			_, err = tx.Exec("DELETE FROM relationships WHERE id = ?", m.relID)
+		} else {
+			_, err = tx.Exec(`
+				UPDATE relationships SET
+					source_id = CASE WHEN source_id = ? THEN ? ELSE source_id END,
+					target_id = CASE WHEN target_id = ? THEN ? ELSE target_id END
+				WHERE id = ?`,
+				from.String(), to.String(), from.String(), to.String(), m.relID)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reassign relationship: %w", err)
+		}
+		moved[m.contactID] = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit reassign contacts: %w", err)
+	}
+	return len(moved), nil
+}
@@ -0,0 +1,101 @@
+// ABOUTME: Tests for moving a company's contacts to another company in SQLite.
+// ABOUTME: Covers moved and already-linked contacts, company-to-company links, and missing companies.
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// reassignFixture creates companies from, to, and partner; contact alice
+// works at from; bob works at both from and to; and from partners with
+// partner.
+func reassignFixture(t *testing.T, store Storage) (from, to, partner *models.Company, alice, bob *models.Contact) {
+	t.Helper()
+	from, to, partner = models.NewCompany("Old Co"), models.NewCompany("New Co"), models.NewCompany("Partner")
+	for _, c := range []*models.Company{from, to, partner} {
+		if err := store.CreateCompany(c); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+	alice, bob = models.NewContact("Alice"), models.NewContact("Bob")
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	for _, rel := range []*models.Relationship{
+		models.NewRelationship(alice.ID, from.ID, "works_at", "engineer"),
+		models.NewRelationship(bob.ID, from.ID, "works_at", ""),
+		models.NewRelationship(to.ID, bob.ID, "works_at", ""),
+		models.NewRelationship(from.ID, partner.ID, "partner", ""),
+	} {
+		if err := store.CreateRelationship(rel); err != nil {
+			t.Fatalf("CreateRelationship: %v", err)
+		}
+	}
+	return from, to, partner, alice, bob
+}
+
+// checkReassigned verifies the state reassignFixture should be in after
+// moving from's contacts to to.
+func checkReassigned(t *testing.T, store Storage, from, to, partner *models.Company, alice, bob *models.Contact) {
+	t.Helper()
+	rels, err := store.ListRelationships(from.ID)
+	if err != nil {
+		t.Fatalf("ListRelationships(from): %v", err)
+	}
+	if len(rels) != 1 || rels[0].TargetID != partner.ID {
+		t.Errorf("from relationships = %v, want only the partner link", rels)
+	}
+
+	aliceRels, err := store.ListRelationships(alice.ID)
+	if err != nil {
+		t.Fatalf("ListRelationships(alice): %v", err)
+	}
+	if len(aliceRels) != 1 || aliceRels[0].TargetID != to.ID || aliceRels[0].Context != "engineer" {
+		t.Errorf("alice relationships = %+v, want works_at New Co (engineer)", aliceRels)
+	}
+	bobRels, err := store.ListRelationships(bob.ID)
+	if err != nil {
+		t.Fatalf("ListRelationships(bob): %v", err)
+	}
+	if len(bobRels) != 1 {
+		t.Errorf("bob relationships = %+v, want one link to New Co", bobRels)
+	}
+}
+
+func TestReassignCompanyContacts(t *testing.T) {
+	store := newTestStore(t)
+	from, to, partner, alice, bob := reassignFixture(t, store)
+
+	n, err := store.ReassignCompanyContacts(from.ID, to.ID)
+	if err != nil {
+		t.Fatalf("ReassignCompanyContacts: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("moved = %d, want 2", n)
+	}
+	checkReassigned(t, store, from, to, partner, alice, bob)
+
+	if n, err := store.ReassignCompanyContacts(from.ID, to.ID); err != nil || n != 0 {
+		t.Errorf("second ReassignCompanyContacts = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestReassignCompanyContactsMissingCompany(t *testing.T) {
+	store := newTestStore(t)
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	if _, err := store.ReassignCompanyContacts(acme.ID, uuid.New()); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("missing target: err = %v, want ErrCompanyNotFound", err)
+	}
+	if _, err := store.ReassignCompanyContacts(uuid.New(), acme.ID); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("missing source: err = %v, want ErrCompanyNotFound", err)
+	}
+}
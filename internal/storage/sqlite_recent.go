@@ -0,0 +1,76 @@
+// ABOUTME: SQLite access tracking for the "recently viewed" list.
+// ABOUTME: Upserts a bounded recent_access table on reads when Options.TrackAccess is set.
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// recordAccess notes a read of the given entity when access tracking is
+// enabled. Tracking is best-effort: failures never fail the read itself.
+func (s *SqliteStore) recordAccess(entityType string, id uuid.UUID) {
+	if !s.opts.TrackAccess {
+		return
+	}
+	_, _ = s.db.Exec(`
+		INSERT INTO recent_access (entity_type, entity_id, last_accessed, access_count)
+		VALUES (?, ?, ?, 1)
+		ON CONFLICT (entity_type, entity_id)
+		DO UPDATE SET last_accessed = excluded.last_accessed, access_count = access_count + 1`,
+		entityType, id.String(), time.Now().UTC(),
+	)
+	_, _ = s.db.Exec(`
+		DELETE FROM recent_access WHERE rowid NOT IN (
+			SELECT rowid FROM recent_access ORDER BY last_accessed DESC LIMIT ?
+		)`, maxRecentAccess)
+}
+
+// forgetAccess removes any access record for a deleted entity.
+func (s *SqliteStore) forgetAccess(id uuid.UUID) error {
+	if _, err := s.db.Exec("DELETE FROM recent_access WHERE entity_id = ?", id.String()); err != nil {
+		return fmt.Errorf("delete access record: %w", err)
+	}
+	return nil
+}
+
+// ListRecent returns the IDs of the most recently read entities, newest first.
+// An empty entityType includes both contacts and companies.
+func (s *SqliteStore) ListRecent(entityType string, limit int) ([]uuid.UUID, error) {
+	query := "SELECT entity_id FROM recent_access"
+	var args []any
+	if entityType != "" {
+		query += " WHERE entity_type = ?"
+		args = append(args, entityType)
+	}
+	query += " ORDER BY last_accessed DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list recent: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var idStr string
+		if err := rows.Scan(&idStr); err != nil {
+			return nil, fmt.Errorf("scan recent: %w", err)
+		}
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse recent id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recent: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,117 @@
+// ABOUTME: Tests for SQLite recently-viewed access tracking.
+// ABOUTME: Covers opt-in behavior, ordering, type filtering, and cleanup on delete.
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+// newTrackingStore creates a SqliteStore with access tracking enabled.
+func newTrackingStore(t *testing.T) *SqliteStore {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := NewSqliteStoreWithOptions(dbPath, Options{TrackAccess: true})
+	if err != nil {
+		t.Fatalf("NewSqliteStoreWithOptions: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestListRecentDisabledByDefault(t *testing.T) {
+	store := newTestStore(t)
+
+	c := models.NewContact("Alice")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if _, err := store.GetContact(c.ID); err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+
+	ids, err := store.ListRecent("", 10)
+	if err != nil {
+		t.Fatalf("ListRecent: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected no recent items without TrackAccess, got %d", len(ids))
+	}
+}
+
+func TestListRecentOrderingAndFilter(t *testing.T) {
+	store := newTrackingStore(t)
+
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	acme := models.NewCompany("Acme")
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	reads := []func() error{
+		func() error { _, err := store.GetContact(alice.ID); return err },
+		func() error { _, err := store.GetCompany(acme.ID); return err },
+		func() error { _, err := store.GetContactByPrefix(bob.ID.String()[:8]); return err },
+	}
+	for _, read := range reads {
+		if err := read(); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	all, err := store.ListRecent("", 0)
+	if err != nil {
+		t.Fatalf("ListRecent: %v", err)
+	}
+	want := []uuid.UUID{bob.ID, acme.ID, alice.ID}
+	if len(all) != len(want) {
+		t.Fatalf("ListRecent len = %d, want %d", len(all), len(want))
+	}
+	for i := range want {
+		if all[i] != want[i] {
+			t.Errorf("ListRecent[%d] = %v, want %v", i, all[i], want[i])
+		}
+	}
+
+	contacts, err := store.ListRecent(EntityContact, 1)
+	if err != nil {
+		t.Fatalf("ListRecent(contact): %v", err)
+	}
+	if len(contacts) != 1 || contacts[0] != bob.ID {
+		t.Errorf("ListRecent(contact, 1) = %v, want [%v]", contacts, bob.ID)
+	}
+}
+
+func TestListRecentForgetsDeleted(t *testing.T) {
+	store := newTrackingStore(t)
+
+	c := models.NewContact("Alice")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if _, err := store.GetContact(c.ID); err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if err := store.DeleteContact(c.ID); err != nil {
+		t.Fatalf("DeleteContact: %v", err)
+	}
+
+	ids, err := store.ListRecent("", 0)
+	if err != nil {
+		t.Fatalf("ListRecent: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected deleted contact to be forgotten, got %v", ids)
+	}
+}
@@ -3,6 +3,8 @@
 package storage
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,9 +12,52 @@ import (
 	"github.com/harperreed/crm/internal/models"
 )
 
-// CreateRelationship inserts a new relationship.
+// CreateRelationship inserts a new relationship. It returns ErrSelfRelationship
+// if both ends are the same entity, ErrEndpointNotFound if either end is not
+// an existing contact or company, and ErrDuplicateRelationship if a
+// relationship of the same type already joins the pair in either direction.
 func (s *SqliteStore) CreateRelationship(rel *models.Relationship) error {
-	_, err := s.db.Exec(`
+	if err := s.validateRelationship(rel); err != nil {
+		return err
+	}
+	_, err := s.findRelationship(rel.SourceID, rel.TargetID, rel.Type)
+	if err == nil {
+		return ErrDuplicateRelationship
+	}
+	if !errors.Is(err, ErrRelationshipNotFound) {
+		return err
+	}
+	return insertRelationship(s.db, rel)
+}
+
+// CreateOrUpdateRelationship inserts rel, or, if a relationship of the same
+// type already joins the pair in either direction, updates that relationship's
+// context instead. On update, rel is rewritten to match the stored row so
+// callers see the existing ID, direction, and creation time.
+func (s *SqliteStore) CreateOrUpdateRelationship(rel *models.Relationship) error {
+	if err := s.validateRelationship(rel); err != nil {
+		return err
+	}
+	existing, err := s.findRelationship(rel.SourceID, rel.TargetID, rel.Type)
+	if errors.Is(err, ErrRelationshipNotFound) {
+		return insertRelationship(s.db, rel)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec("UPDATE relationships SET context = ? WHERE id = ?",
+		rel.Context, existing.ID.String()); err != nil {
+		return fmt.Errorf("update relationship: %w", err)
+	}
+	existing.Context = rel.Context
+	*rel = *existing
+	return nil
+}
+
+// insertRelationship writes rel as a new row through ex.
+func insertRelationship(ex execer, rel *models.Relationship) error {
+	_, err := ex.Exec(`
 		INSERT INTO relationships (id, source_id, target_id, type, context, created_at)
 		VALUES (?, ?, ?, ?, ?, ?)`,
 		rel.ID.String(), rel.SourceID.String(), rel.TargetID.String(),
@@ -24,6 +69,62 @@ func (s *SqliteStore) CreateRelationship(rel *models.Relationship) error {
 	return nil
 }
 
+// validateRelationship checks that rel joins two distinct, existing entities.
+func (s *SqliteStore) validateRelationship(rel *models.Relationship) error {
+	if rel.SourceID == rel.TargetID {
+		return ErrSelfRelationship
+	}
+	for _, id := range []uuid.UUID{rel.SourceID, rel.TargetID} {
+		ok, err := s.entityExists(id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrEndpointNotFound, id)
+		}
+	}
+	return nil
+}
+
+// findRelationship returns the relationship of relType joining a and b in
+// either direction, or ErrRelationshipNotFound.
+func (s *SqliteStore) findRelationship(a, b uuid.UUID, relType string) (*models.Relationship, error) {
+	var r models.Relationship
+	var idStr, srcStr, tgtStr string
+	var createdAt time.Time
+	err := s.db.QueryRow(`
+		SELECT id, source_id, target_id, type, context, created_at
+		FROM relationships
+		WHERE type = ? AND ((source_id = ? AND target_id = ?) OR (source_id = ? AND target_id = ?))`,
+		relType, a.String(), b.String(), b.String(), a.String(),
+	).Scan(&idStr, &srcStr, &tgtStr, &r.Type, &r.Context, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRelationshipNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find relationship: %w", err)
+	}
+	if err := setRelationshipIDs(&r, idStr, srcStr, tgtStr); err != nil {
+		return nil, err
+	}
+	r.CreatedAt = createdAt.UTC()
+	return &r, nil
+}
+
+// entityExists reports whether id belongs to a contact or a company.
+func (s *SqliteStore) entityExists(id uuid.UUID) (bool, error) {
+	var ok bool
+	err := s.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM contacts WHERE id = ?)
+			OR EXISTS (SELECT 1 FROM companies WHERE id = ?)`,
+		id.String(), id.String(),
+	).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("check entity exists: %w", err)
+	}
+	return ok, nil
+}
+
 // ListRelationships returns all relationships where the given entityID appears
 // as either source or target (bidirectional lookup).
 func (s *SqliteStore) ListRelationships(entityID uuid.UUID) ([]*models.Relationship, error) {
@@ -48,23 +149,10 @@ func (s *SqliteStore) ListRelationships(entityID uuid.UUID) ([]*models.Relations
 			return nil, fmt.Errorf("scan relationship: %w", err)
 		}
 
-		id, err := uuid.Parse(idStr)
-		if err != nil {
-			return nil, fmt.Errorf("parse relationship id: %w", err)
-		}
-		srcID, err := uuid.Parse(srcStr)
-		if err != nil {
-			return nil, fmt.Errorf("parse source_id: %w", err)
-		}
-		tgtID, err := uuid.Parse(tgtStr)
-		if err != nil {
-			return nil, fmt.Errorf("parse target_id: %w", err)
+		if err := setRelationshipIDs(&r, idStr, srcStr, tgtStr); err != nil {
+			return nil, err
 		}
-
-		r.ID = id
-		r.SourceID = srcID
-		r.TargetID = tgtID
-		r.CreatedAt = createdAt
+		r.CreatedAt = createdAt.UTC()
 
 		rels = append(rels, &r)
 	}
@@ -76,6 +164,26 @@ func (s *SqliteStore) ListRelationships(entityID uuid.UUID) ([]*models.Relations
 	return rels, nil
 }
 
+// setRelationshipIDs parses the stored UUID strings into r.
+func setRelationshipIDs(r *models.Relationship, idStr, srcStr, tgtStr string) error {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return fmt.Errorf("parse relationship id: %w", err)
+	}
+	srcID, err := uuid.Parse(srcStr)
+	if err != nil {
+		return fmt.Errorf("parse source_id: %w", err)
+	}
+	tgtID, err := uuid.Parse(tgtStr)
+	if err != nil {
+		return fmt.Errorf("parse target_id: %w", err)
+	}
+	r.ID = id
+	r.SourceID = srcID
+	r.TargetID = tgtID
+	return nil
+}
+
 // DeleteRelationship removes a relationship by UUID, returning
 // ErrRelationshipNotFound if no row matches.
 func (s *SqliteStore) DeleteRelationship(id uuid.UUID) error {
@@ -93,3 +201,83 @@ func (s *SqliteStore) DeleteRelationship(id uuid.UUID) error {
 	}
 	return nil
 }
+
+// deleteRelationshipsFor removes every relationship with id at either end,
+// so deleting an entity leaves no dangling edges behind.
+func deleteRelationshipsFor(tx *sql.Tx, id uuid.UUID) error {
+	_, err := tx.Exec("DELETE FROM relationships WHERE source_id = ? OR target_id = ?", id.String(), id.String())
+	if err != nil {
+		return fmt.Errorf("delete relationships: %w", err)
+	}
+	return nil
+}
+
+// relationshipExistsChunk bounds the pairs checked per query so the IN
+// lists stay well under SQLite's bound-parameter limit.
+const relationshipExistsChunk = 200
+
+// RelationshipExistsMany reports, for each given pair, whether any
+// relationship joins the two entities in either direction. The result is
+// keyed by the pairs exactly as given.
+func (s *SqliteStore) RelationshipExistsMany(pairs [][2]uuid.UUID) (map[[2]uuid.UUID]bool, error) {
+	result := make(map[[2]uuid.UUID]bool, len(pairs))
+	for start := 0; start < len(pairs); start += relationshipExistsChunk {
+		chunk := pairs[start:min(start+relationshipExistsChunk, len(pairs))]
+		found, err := s.relatedPairs(chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range chunk {
+			result[p] = found[pairKey(p[0], p[1])]
+		}
+	}
+	return result, nil
+}
+
+// relatedPairs returns the normalized keys of relationships whose endpoints
+// are both among the IDs in pairs.
+func (s *SqliteStore) relatedPairs(pairs [][2]uuid.UUID) (map[[2]uuid.UUID]bool, error) {
+	seen := make(map[uuid.UUID]bool)
+	var ids []any
+	for _, p := range pairs {
+		for _, id := range p {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id.String())
+			}
+		}
+	}
+	placeholders := placeholderList(len(ids))
+
+	args := append(append([]any{}, ids...), ids...)
+	rows, err := s.db.Query(`
+		SELECT source_id, target_id FROM relationships
+		WHERE source_id IN (`+placeholders+`) AND target_id IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query relationship pairs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	found := make(map[[2]uuid.UUID]bool)
+	for rows.Next() {
+		var srcStr, tgtStr string
+		if err := rows.Scan(&srcStr, &tgtStr); err != nil {
+			return nil, fmt.Errorf("scan relationship pair: %w", err)
+		}
+		src, err := uuid.Parse(srcStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse source_id: %w", err)
+		}
+		tgt, err := uuid.Parse(tgtStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse target_id: %w", err)
+		}
+		found[pairKey(src, tgt)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate relationship pairs: %w", err)
+	}
+	return found, nil
+}
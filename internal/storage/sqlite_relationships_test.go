@@ -4,17 +4,33 @@ package storage
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/crm/internal/models"
 )
 
+// newTestEntities creates n contacts in store and returns their IDs, giving
+// relationship tests real endpoints to link.
+func newTestEntities(t *testing.T, store Storage, n int) []uuid.UUID {
+	t.Helper()
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		c := models.NewContact(fmt.Sprintf("Entity %d", i))
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+		ids[i] = c.ID
+	}
+	return ids
+}
+
 func TestCreateRelationship(t *testing.T) {
 	store := newTestStore(t)
 
-	srcID := uuid.New()
-	tgtID := uuid.New()
+	ids := newTestEntities(t, store, 2)
+	srcID, tgtID := ids[0], ids[1]
 	rel := models.NewRelationship(srcID, tgtID, "works_at", "engineering team")
 
 	if err := store.CreateRelationship(rel); err != nil {
@@ -39,9 +55,8 @@ func TestCreateRelationship(t *testing.T) {
 func TestListRelationshipsBidirectional(t *testing.T) {
 	store := newTestStore(t)
 
-	a := uuid.New()
-	b := uuid.New()
-	c := uuid.New()
+	ids := newTestEntities(t, store, 3)
+	a, b, c := ids[0], ids[1], ids[2]
 
 	r1 := models.NewRelationship(a, b, "knows", "")
 	r2 := models.NewRelationship(c, a, "manages", "")
@@ -74,7 +89,8 @@ func TestListRelationshipsBidirectional(t *testing.T) {
 func TestDeleteRelationship(t *testing.T) {
 	store := newTestStore(t)
 
-	rel := models.NewRelationship(uuid.New(), uuid.New(), "partner", "")
+	ids := newTestEntities(t, store, 2)
+	rel := models.NewRelationship(ids[0], ids[1], "partner", "")
 	if err := store.CreateRelationship(rel); err != nil {
 		t.Fatalf("CreateRelationship: %v", err)
 	}
@@ -99,3 +115,123 @@ func TestDeleteRelationshipNotFound(t *testing.T) {
 		t.Errorf("expected ErrRelationshipNotFound, got %v", err)
 	}
 }
+
+func TestCreateRelationshipSelf(t *testing.T) {
+	store := newTestStore(t)
+
+	id := newTestEntities(t, store, 1)[0]
+	err := store.CreateRelationship(models.NewRelationship(id, id, "knows", ""))
+	if !errors.Is(err, ErrSelfRelationship) {
+		t.Errorf("expected ErrSelfRelationship, got %v", err)
+	}
+}
+
+func TestCreateRelationshipMissingEndpoint(t *testing.T) {
+	store := newTestStore(t)
+
+	id := newTestEntities(t, store, 1)[0]
+	acme := models.NewCompany("Acme")
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	for _, rel := range []*models.Relationship{
+		models.NewRelationship(id, uuid.New(), "knows", ""),
+		models.NewRelationship(uuid.New(), acme.ID, "works_at", ""),
+	} {
+		if err := store.CreateRelationship(rel); !errors.Is(err, ErrEndpointNotFound) {
+			t.Errorf("expected ErrEndpointNotFound, got %v", err)
+		}
+	}
+
+	// Contact-to-company links are still accepted.
+	if err := store.CreateRelationship(models.NewRelationship(id, acme.ID, "works_at", "")); err != nil {
+		t.Errorf("CreateRelationship(contact, company): %v", err)
+	}
+}
+
+func TestCreateRelationshipDuplicate(t *testing.T) {
+	store := newTestStore(t)
+
+	ids := newTestEntities(t, store, 2)
+	if err := store.CreateRelationship(models.NewRelationship(ids[0], ids[1], "colleague", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+
+	// The same type in either direction is a duplicate.
+	for _, rel := range []*models.Relationship{
+		models.NewRelationship(ids[0], ids[1], "colleague", ""),
+		models.NewRelationship(ids[1], ids[0], "colleague", ""),
+	} {
+		if err := store.CreateRelationship(rel); !errors.Is(err, ErrDuplicateRelationship) {
+			t.Errorf("expected ErrDuplicateRelationship, got %v", err)
+		}
+	}
+
+	// A different type between the same pair is allowed.
+	if err := store.CreateRelationship(models.NewRelationship(ids[1], ids[0], "mentor", "")); err != nil {
+		t.Errorf("CreateRelationship(mentor): %v", err)
+	}
+}
+
+func TestCreateOrUpdateRelationship(t *testing.T) {
+	store := newTestStore(t)
+
+	ids := newTestEntities(t, store, 2)
+	first := models.NewRelationship(ids[0], ids[1], "colleague", "same team")
+	if err := store.CreateOrUpdateRelationship(first); err != nil {
+		t.Fatalf("CreateOrUpdateRelationship(first): %v", err)
+	}
+
+	again := models.NewRelationship(ids[1], ids[0], "colleague", "different teams now")
+	if err := store.CreateOrUpdateRelationship(again); err != nil {
+		t.Fatalf("CreateOrUpdateRelationship(again): %v", err)
+	}
+	if again.ID != first.ID || again.SourceID != ids[0] {
+		t.Errorf("update returned ID %s source %s, want existing %s from %s", again.ID, again.SourceID, first.ID, ids[0])
+	}
+
+	rels, err := store.ListRelationships(ids[0])
+	if err != nil {
+		t.Fatalf("ListRelationships: %v", err)
+	}
+	if len(rels) != 1 || rels[0].Context != "different teams now" {
+		t.Errorf("relationships = %+v, want one with updated context", rels)
+	}
+}
+
+func TestRelationshipExistsMany(t *testing.T) {
+	store := newTestStore(t)
+
+	ids := newTestEntities(t, store, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+	if err := store.CreateRelationship(models.NewRelationship(a, b, "knows", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+
+	pairs := [][2]uuid.UUID{{a, b}, {b, a}, {a, c}}
+	got, err := store.RelationshipExistsMany(pairs)
+	if err != nil {
+		t.Fatalf("RelationshipExistsMany: %v", err)
+	}
+	if !got[pairs[0]] || !got[pairs[1]] {
+		t.Errorf("expected a-b to exist in both orders, got %v", got)
+	}
+	if got[pairs[2]] {
+		t.Error("expected a-c not to exist")
+	}
+
+	// More pairs than fit in one query chunk.
+	many := make([][2]uuid.UUID, relationshipExistsChunk+5)
+	for i := range many {
+		many[i] = [2]uuid.UUID{uuid.New(), uuid.New()}
+	}
+	many[len(many)-1] = [2]uuid.UUID{b, a}
+	got, err = store.RelationshipExistsMany(many)
+	if err != nil {
+		t.Fatalf("RelationshipExistsMany(many): %v", err)
+	}
+	if len(got) != len(many) || !got[many[len(many)-1]] || got[many[0]] {
+		t.Errorf("chunked lookup returned %d entries with wrong values", len(got))
+	}
+}
@@ -26,12 +26,13 @@ func (s *SqliteStore) Search(query string) (*SearchResults, error) {
 	}, nil
 }
 
-// searchContacts performs an FTS5 search on the contacts table.
+// searchContacts performs an FTS5 search on the contacts table, adding
+// phone-number matches when the query looks like one.
 func (s *SqliteStore) searchContacts(query string) ([]*models.Contact, error) {
 	escaped := escapeFTS5Query(query)
 
 	rows, err := s.db.Query(`
-		SELECT c.id, c.name, c.email, c.phone, c.fields, c.tags, c.created_at, c.updated_at
+		SELECT c.id, c.name, c.email, c.phone, c.fields, c.tags, c.created_at, c.updated_at, c.do_not_contact, c.version, c.source
 		FROM contacts c
 		JOIN contacts_fts fts ON c.rowid = fts.rowid
 		WHERE contacts_fts MATCH ?
@@ -39,7 +40,20 @@ func (s *SqliteStore) searchContacts(query string) ([]*models.Contact, error) {
 	if err != nil {
 		return nil, fmt.Errorf("fts query contacts: %w", err)
 	}
-	return scanContactRows(rows)
+	contacts, err := scanContactRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := phoneSearchDigits(query)
+	if digits == "" {
+		return contacts, nil
+	}
+	byPhone, err := s.contactsByPhone(digits, nil)
+	if err != nil {
+		return nil, err
+	}
+	return appendMissingContacts(contacts, byPhone), nil
 }
 
 // searchCompanies performs an FTS5 search on the companies table.
@@ -47,7 +61,7 @@ func (s *SqliteStore) searchCompanies(query string) ([]*models.Company, error) {
 	escaped := escapeFTS5Query(query)
 
 	rows, err := s.db.Query(`
-		SELECT c.id, c.name, c.domain, c.fields, c.tags, c.created_at, c.updated_at
+		SELECT c.id, c.name, c.domain, c.fields, c.tags, c.created_at, c.updated_at, c.version, c.source
 		FROM companies c
 		JOIN companies_fts fts ON c.rowid = fts.rowid
 		WHERE companies_fts MATCH ?
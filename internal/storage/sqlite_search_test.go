@@ -3,6 +3,7 @@
 package storage
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/harperreed/crm/internal/models"
@@ -64,3 +65,79 @@ func TestSearchNoResults(t *testing.T) {
 		t.Errorf("Companies len = %d, want 0", len(results.Companies))
 	}
 }
+
+func TestSearchMatchesPhoneDigits(t *testing.T) {
+	store := newTestStore(t)
+
+	caller := models.NewContact("Caller")
+	caller.Phone = "+1 (555) 123-4567"
+	if err := store.CreateContact(caller); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	other := models.NewContact("Other")
+	other.Phone = "555-9999"
+	if err := store.CreateContact(other); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	for _, q := range []string{"5551234567", "555-123-4567", "1234567"} {
+		results, err := store.Search(q)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", q, err)
+		}
+		if len(results.Contacts) != 1 || results.Contacts[0].ID != caller.ID {
+			t.Errorf("Search(%q) contacts = %v, want only Caller", q, results.Contacts)
+		}
+
+		listed, err := store.ListContacts(&ContactFilter{Search: q})
+		if err != nil {
+			t.Fatalf("ListContacts(%q): %v", q, err)
+		}
+		if len(listed) != 1 || listed[0].ID != caller.ID {
+			t.Errorf("ListContacts(%q) = %v, want only Caller", q, listed)
+		}
+	}
+
+	// Digits from the middle of a number are not a phone match.
+	if listed, err := store.ListContacts(&ContactFilter{Search: "5551234"}); err != nil || len(listed) != 0 {
+		t.Errorf("ListContacts(middle digits) = %v (err %v), want none", listed, err)
+	}
+
+	// Updating the phone keeps the normalized column in step.
+	caller.Phone = "555.777.8888"
+	if err := store.UpdateContact(caller); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	results, err := store.Search("7778888")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results.Contacts) != 1 {
+		t.Errorf("Search after phone change found %d contacts, want 1", len(results.Contacts))
+	}
+}
+
+func TestPhoneSearchUsesIndex(t *testing.T) {
+	store := newTestStore(t)
+
+	key := phoneSearchKey("1234567")
+	rows, err := store.db.Query(`EXPLAIN QUERY PLAN
+		SELECT id FROM contacts WHERE phone_key >= ? AND phone_key < ?`, key, key+":")
+	if err != nil {
+		t.Fatalf("EXPLAIN QUERY PLAN: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var plan []string
+	for rows.Next() {
+		var id, parent, notused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notused, &detail); err != nil {
+			t.Fatalf("scan plan: %v", err)
+		}
+		plan = append(plan, detail)
+	}
+	if !strings.Contains(strings.Join(plan, "\n"), "USING INDEX idx_contacts_phone_key") {
+		t.Errorf("phone search plan = %q, want a range scan of idx_contacts_phone_key", plan)
+	}
+}
@@ -0,0 +1,60 @@
+// ABOUTME: Tests for the SQLite settings key/value store.
+// ABOUTME: Covers get, set, overwrite, listing, blank keys, and JSON-valued helpers.
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkSettings exercises the settings store on either backend.
+func checkSettings(t *testing.T, store Storage) {
+	t.Helper()
+
+	if _, ok, err := store.GetSetting("theme"); err != nil || ok {
+		t.Fatalf("GetSetting unset: ok = %v, err = %v; want false, nil", ok, err)
+	}
+	if err := store.SetSetting("theme", "dark"); err != nil {
+		t.Fatalf("SetSetting: %v", err)
+	}
+	if err := store.SetSetting("theme", "light"); err != nil {
+		t.Fatalf("SetSetting overwrite: %v", err)
+	}
+	if got, ok, err := store.GetSetting("theme"); err != nil || !ok || got != "light" {
+		t.Fatalf("GetSetting = %q, %v, %v; want light, true, nil", got, ok, err)
+	}
+	if err := store.SetSetting(" ", "x"); !errors.Is(err, ErrSettingKeyRequired) {
+		t.Errorf("blank key: err = %v, want ErrSettingKeyRequired", err)
+	}
+
+	type columns struct {
+		Fields []string `json:"fields"`
+		Width  int      `json:"width"`
+	}
+	want := columns{Fields: []string{"name", "email"}, Width: 80}
+	if err := SetSettingJSON(store, "list.columns", want); err != nil {
+		t.Fatalf("SetSettingJSON: %v", err)
+	}
+	var got columns
+	if ok, err := GetSettingJSON(store, "list.columns", &got); err != nil || !ok {
+		t.Fatalf("GetSettingJSON: ok = %v, err = %v", ok, err)
+	}
+	if len(got.Fields) != 2 || got.Fields[1] != "email" || got.Width != 80 {
+		t.Errorf("GetSettingJSON = %+v, want %+v", got, want)
+	}
+	if ok, err := GetSettingJSON(store, "theme", &got); err == nil || ok {
+		t.Errorf("GetSettingJSON on non-JSON value: ok = %v, err = %v; want decode error", ok, err)
+	}
+
+	all, err := store.ListSettings()
+	if err != nil {
+		t.Fatalf("ListSettings: %v", err)
+	}
+	if len(all) != 2 || all["theme"] != "light" {
+		t.Errorf("ListSettings = %v, want theme and list.columns", all)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	checkSettings(t, newTestStore(t))
+}
@@ -0,0 +1,79 @@
+// ABOUTME: Tests for the contact and company source (provenance) field in SQLite.
+// ABOUTME: Covers round-tripping Source and filtering lists and searches by it.
+package storage
+
+import (
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestContactSource(t *testing.T) {
+	store := newTestStore(t)
+	manual := models.NewContact("Alice Manual")
+	manual.Source = models.SourceManual
+	imported := models.NewContact("Alice Imported")
+	imported.Source = "csv"
+	imported.Phone = "+1 (555) 010-2000"
+	for _, c := range []*models.Contact{manual, imported} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+
+	got, err := store.GetContact(imported.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.Source != "csv" {
+		t.Errorf("Source = %q, want csv", got.Source)
+	}
+
+	for name, filter := range map[string]*ContactFilter{
+		"list":   {Source: "csv"},
+		"search": {Source: "csv", Search: "Alice"},
+		"phone":  {Source: "csv", Search: "5550102000"},
+	} {
+		contacts, err := store.ListContacts(filter)
+		if err != nil {
+			t.Fatalf("%s: ListContacts: %v", name, err)
+		}
+		if len(contacts) != 1 || contacts[0].ID != imported.ID {
+			t.Errorf("%s: ListContacts = %v, want only the csv contact", name, contacts)
+		}
+	}
+
+	got.Source = models.SourceManual
+	if err := store.UpdateContact(got); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	contacts, err := store.ListContacts(&ContactFilter{Source: models.SourceManual})
+	if err != nil {
+		t.Fatalf("ListContacts: %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Errorf("manual contacts after update = %d, want 2", len(contacts))
+	}
+}
+
+func TestCompanySource(t *testing.T) {
+	store := newTestStore(t)
+	acme := models.NewCompany("Acme")
+	acme.Source = "csv"
+	other := models.NewCompany("Acme Labs")
+	for _, c := range []*models.Company{acme, other} {
+		if err := store.CreateCompany(c); err != nil {
+			t.Fatalf("CreateCompany: %v", err)
+		}
+	}
+
+	for _, filter := range []*CompanyFilter{{Source: "csv"}, {Source: "csv", Search: "Acme"}} {
+		companies, err := store.ListCompanies(filter)
+		if err != nil {
+			t.Fatalf("ListCompanies: %v", err)
+		}
+		if len(companies) != 1 || companies[0].Source != "csv" {
+			t.Errorf("ListCompanies(%+v) = %v, want only Acme", filter, companies)
+		}
+	}
+}
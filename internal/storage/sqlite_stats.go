@@ -0,0 +1,31 @@
+// ABOUTME: SQLite record counts per entity and schema version for health and diagnostics reporting.
+// ABOUTME: Uses COUNT(*) queries so the cost stays constant regardless of row size.
+package storage
+
+import "fmt"
+
+// Stats returns the number of rows in each entity table.
+func (s *SqliteStore) Stats() (*Stats, error) {
+	var st Stats
+	err := s.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM contacts),
+			(SELECT COUNT(*) FROM companies),
+			(SELECT COUNT(*) FROM relationships),
+			(SELECT COUNT(*) FROM attachments)`,
+	).Scan(&st.Contacts, &st.Companies, &st.Relationships, &st.Attachments)
+	if err != nil {
+		return nil, fmt.Errorf("count rows: %w", err)
+	}
+	return &st, nil
+}
+
+// SchemaVersion returns the database's PRAGMA user_version, which records
+// the last one-time migration applied.
+func (s *SqliteStore) SchemaVersion() (int, bool, error) {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, true, nil
+}
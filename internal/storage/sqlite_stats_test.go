@@ -0,0 +1,50 @@
+// ABOUTME: Tests for SQLite per-entity record counts.
+// ABOUTME: Verifies Stats reflects inserted contacts, companies, relationships, and attachments.
+package storage
+
+import (
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestStats(t *testing.T) {
+	store := newTestStore(t)
+
+	st, err := store.Stats()
+	if err != nil {
+		t.Fatalf("Stats on empty store: %v", err)
+	}
+	if *st != (Stats{}) {
+		t.Errorf("empty store Stats = %+v, want all zero", *st)
+	}
+
+	alice := models.NewContact("Alice")
+	bob := models.NewContact("Bob")
+	acme := models.NewCompany("Acme")
+	for _, c := range []*models.Contact{alice, bob} {
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact: %v", err)
+		}
+	}
+	if err := store.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	if err := store.CreateRelationship(models.NewRelationship(alice.ID, acme.ID, "works_at", "")); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	a := models.NewAttachment("company", acme.ID, "deck.pdf")
+	a.Path = "/docs/deck.pdf"
+	if err := store.AddAttachment(a); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	st, err = store.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	want := Stats{Contacts: 2, Companies: 1, Relationships: 1, Attachments: 1}
+	if *st != want {
+		t.Errorf("Stats = %+v, want %+v", *st, want)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/harperreed/crm/internal/models"
 )
 
 // newTestStore creates a SqliteStore in a temp directory and registers cleanup.
@@ -241,7 +243,7 @@ func tableColumnExists(db *sql.DB, table, column string) bool {
 func TestStoreContactsColumns(t *testing.T) {
 	store := newTestStore(t)
 
-	cols := []string{"id", "name", "email", "phone", "fields", "tags", "created_at", "updated_at"}
+	cols := []string{"id", "name", "email", "phone", "fields", "tags", "created_at", "updated_at", "do_not_contact", "version", "phone_key"}
 	for _, col := range cols {
 		if !tableColumnExists(store.db, "contacts", col) {
 			t.Errorf("contacts table missing column %q", col)
@@ -252,7 +254,7 @@ func TestStoreContactsColumns(t *testing.T) {
 func TestStoreCompaniesColumns(t *testing.T) {
 	store := newTestStore(t)
 
-	cols := []string{"id", "name", "domain", "fields", "tags", "created_at", "updated_at"}
+	cols := []string{"id", "name", "domain", "fields", "tags", "created_at", "updated_at", "version"}
 	for _, col := range cols {
 		if !tableColumnExists(store.db, "companies", col) {
 			t.Errorf("companies table missing column %q", col)
@@ -270,3 +272,21 @@ func TestStoreRelationshipsColumns(t *testing.T) {
 		}
 	}
 }
+
+func TestCloseCheckpointsWAL(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "wal.db")
+	store, err := NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore: %v", err)
+	}
+	if err := store.CreateContact(models.NewContact("Durable")); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if info, err := os.Stat(dbPath + "-wal"); err == nil && info.Size() > 0 {
+		t.Errorf("WAL still holds %d bytes after Close", info.Size())
+	}
+}
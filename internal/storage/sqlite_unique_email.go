@@ -0,0 +1,40 @@
+// ABOUTME: SQLite unique index on normalized contact email, kept in step with Options.EnforceUniqueEmail.
+// ABOUTME: Maps violations of that index to ErrDuplicateEmail.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// uniqueEmailIndex names the partial unique index on normalized email.
+const uniqueEmailIndex = "idx_contacts_unique_email"
+
+// syncUniqueEmailIndex creates the unique email index when
+// Options.EnforceUniqueEmail is set and drops it otherwise, so turning the
+// option off lifts the constraint. Creation fails if existing contacts
+// already share an email.
+func (s *SqliteStore) syncUniqueEmailIndex(tx *sql.Tx) error {
+	if !s.opts.EnforceUniqueEmail {
+		if _, err := tx.Exec("DROP INDEX IF EXISTS " + uniqueEmailIndex); err != nil {
+			return fmt.Errorf("drop unique email index: %w", err)
+		}
+		return nil
+	}
+	_, err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS ` + uniqueEmailIndex + `
+		ON contacts(lower(trim(email))) WHERE trim(email) != ''`)
+	if err != nil {
+		return fmt.Errorf("create unique email index (merge contacts that share an email first): %w", err)
+	}
+	return nil
+}
+
+// uniqueEmailError returns ErrDuplicateEmail when err is a violation of the
+// unique email index, and otherwise wraps err with op.
+func uniqueEmailError(err error, op string) error {
+	if strings.Contains(err.Error(), "UNIQUE constraint failed") && strings.Contains(err.Error(), "email") {
+		return ErrDuplicateEmail
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,114 @@
+// ABOUTME: Tests for Options.EnforceUniqueEmail and CreateOrUpdateContactByEmail.
+// ABOUTME: Covers create/update rejection, blank emails, the SQLite index lifecycle, and the upsert helper.
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+// checkUniqueEmailEnforced verifies a store opened with EnforceUniqueEmail
+// rejects contacts that reuse another contact's normalized email.
+func checkUniqueEmailEnforced(t *testing.T, store Storage) {
+	t.Helper()
+
+	alice := models.NewContact("Alice")
+	alice.Email = "alice@example.com"
+	if err := store.CreateContact(alice); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	dup := models.NewContact("Alice Again")
+	dup.Email = " ALICE@example.com "
+	if err := store.CreateContact(dup); !errors.Is(err, ErrDuplicateEmail) {
+		t.Errorf("CreateContact duplicate: err = %v, want ErrDuplicateEmail", err)
+	}
+
+	bob := models.NewContact("Bob")
+	if err := store.CreateContact(bob); err != nil {
+		t.Fatalf("CreateContact bob: %v", err)
+	}
+	if err := store.CreateContact(models.NewContact("Carol")); err != nil {
+		t.Errorf("second blank email rejected: %v", err)
+	}
+	bob.Email = "Alice@Example.com"
+	if err := store.UpdateContact(bob); !errors.Is(err, ErrDuplicateEmail) {
+		t.Errorf("UpdateContact duplicate: err = %v, want ErrDuplicateEmail", err)
+	}
+
+	alice.Name = "Alice Smith"
+	if err := store.UpdateContact(alice); err != nil {
+		t.Errorf("UpdateContact keeping own email: %v", err)
+	}
+}
+
+func TestEnforceUniqueEmail(t *testing.T) {
+	store, err := NewSqliteStoreWithOptions(filepath.Join(t.TempDir(), "test.db"), Options{EnforceUniqueEmail: true})
+	if err != nil {
+		t.Fatalf("NewSqliteStoreWithOptions: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	checkUniqueEmailEnforced(t, store)
+
+	// The index backs the check even for writes that skip it.
+	_, err = store.db.Exec(`INSERT INTO contacts (id, name, email, created_at, updated_at)
+		VALUES ('raw', 'Raw', 'alice@example.com', datetime('now'), datetime('now'))`)
+	if err == nil || !errors.Is(uniqueEmailError(err, "insert contact"), ErrDuplicateEmail) {
+		t.Errorf("raw duplicate insert: err = %v, want unique email violation", err)
+	}
+}
+
+func TestUniqueEmailOffByDefault(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := NewSqliteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSqliteStore: %v", err)
+	}
+	for _, name := range []string{"Shared One", "Shared Two"} {
+		c := models.NewContact(name)
+		c.Email = "team@example.com"
+		if err := store.CreateContact(c); err != nil {
+			t.Fatalf("CreateContact %s: %v", name, err)
+		}
+	}
+	_ = store.Close()
+
+	if _, err := NewSqliteStoreWithOptions(dbPath, Options{EnforceUniqueEmail: true}); err == nil {
+		t.Error("enabling EnforceUniqueEmail over duplicate emails: expected error, got nil")
+	}
+}
+
+func TestCreateOrUpdateContactByEmail(t *testing.T) {
+	store := newTestStore(t)
+
+	first := models.NewContact("Alice")
+	first.Email = "alice@example.com"
+	created, err := CreateOrUpdateContactByEmail(store, first)
+	if err != nil || !created {
+		t.Fatalf("first upsert: created = %v, err = %v; want true, nil", created, err)
+	}
+
+	again := models.NewContact("Alice Smith")
+	again.Email = "ALICE@example.com"
+	created, err = CreateOrUpdateContactByEmail(store, again)
+	if err != nil || created {
+		t.Fatalf("second upsert: created = %v, err = %v; want false, nil", created, err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("upsert ID = %s, want existing %s", again.ID, first.ID)
+	}
+	got, err := store.GetContact(first.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.Name != "Alice Smith" || got.Version != 2 {
+		t.Errorf("stored contact = %q v%d, want Alice Smith v2", got.Name, got.Version)
+	}
+
+	if created, err := CreateOrUpdateContactByEmail(store, models.NewContact("No Email")); err != nil || !created {
+		t.Errorf("blank email upsert: created = %v, err = %v; want true, nil", created, err)
+	}
+}
@@ -0,0 +1,90 @@
+// ABOUTME: Tests for optimistic-concurrency versions on SQLite contacts and companies.
+// ABOUTME: Verifies stale updates are rejected while fresh ones succeed and bump the version.
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/crm/internal/models"
+)
+
+func TestUpdateContactRejectsStaleVersion(t *testing.T) {
+	store := newTestStore(t)
+	c := models.NewContact("Alice")
+	if err := store.CreateContact(c); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	first, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	stale, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if first.Version != 1 {
+		t.Fatalf("new contact version = %d, want 1", first.Version)
+	}
+
+	first.Email = "alice@example.com"
+	if err := store.UpdateContact(first); err != nil {
+		t.Fatalf("UpdateContact: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("version after update = %d, want 2", first.Version)
+	}
+
+	stale.Phone = "555-0100"
+	if err := store.UpdateContact(stale); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("stale UpdateContact err = %v, want ErrVersionConflict", err)
+	}
+
+	got, err := store.GetContact(c.ID)
+	if err != nil {
+		t.Fatalf("GetContact: %v", err)
+	}
+	if got.Email != "alice@example.com" || got.Phone != "" || got.Version != 2 {
+		t.Errorf("stored contact = %+v, want first update only at version 2", got)
+	}
+}
+
+func TestUpdateContactMissingIsNotFound(t *testing.T) {
+	store := newTestStore(t)
+	c := models.NewContact("Ghost")
+	c.ID = uuid.New()
+	if err := store.UpdateContact(c); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("UpdateContact err = %v, want ErrContactNotFound", err)
+	}
+}
+
+func TestUpdateCompanyRejectsStaleVersion(t *testing.T) {
+	store := newTestStore(t)
+	co := models.NewCompany("Acme")
+	if err := store.CreateCompany(co); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	stale, err := store.GetCompany(co.ID)
+	if err != nil {
+		t.Fatalf("GetCompany: %v", err)
+	}
+
+	co.Domain = "acme.com"
+	if err := store.UpdateCompany(co); err != nil {
+		t.Fatalf("UpdateCompany: %v", err)
+	}
+	if co.Version != 2 {
+		t.Errorf("version after update = %d, want 2", co.Version)
+	}
+
+	stale.Domain = "acme.org"
+	if err := store.UpdateCompany(stale); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("stale UpdateCompany err = %v, want ErrVersionConflict", err)
+	}
+	if err := store.UpdateCompany(&models.Company{ID: uuid.New(), Name: "Nobody", Version: 1}); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("UpdateCompany on missing id err = %v, want ErrCompanyNotFound", err)
+	}
+}
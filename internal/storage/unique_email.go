@@ -0,0 +1,50 @@
+// ABOUTME: Backend-independent email uniqueness check and upsert-by-email helper.
+// ABOUTME: Backs Options.EnforceUniqueEmail and lets email-keyed imports rerun without duplicating contacts.
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/harperreed/crm/internal/models"
+)
+
+// checkUniqueEmail returns ErrDuplicateEmail if a contact other than c
+// already uses c's normalized email. Blank emails never conflict.
+func checkUniqueEmail(s Storage, c *models.Contact) error {
+	if NormalizeEmail(c.Email) == "" {
+		return nil
+	}
+	other, err := s.GetContactByEmail(c.Email)
+	if errors.Is(err, ErrContactNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if other.ID != c.ID {
+		return ErrDuplicateEmail
+	}
+	return nil
+}
+
+// CreateOrUpdateContactByEmail creates c, or, if a contact with the same
+// normalized email exists, overwrites that contact with c's data instead.
+// On update, c takes the existing ID, creation time, and version so callers
+// see the stored record. It reports whether a new contact was created. A
+// contact without an email is always created.
+func CreateOrUpdateContactByEmail(s Storage, c *models.Contact) (bool, error) {
+	existing, err := s.GetContactByEmail(c.Email)
+	if errors.Is(err, ErrContactNotFound) {
+		return true, s.CreateContact(c)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	c.ID = existing.ID
+	c.CreatedAt = existing.CreatedAt
+	c.Version = existing.Version
+	c.UpdatedAt = time.Now().UTC()
+	return false, s.UpdateContact(c)
+}